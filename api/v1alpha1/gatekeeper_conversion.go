@@ -0,0 +1,734 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/gatekeeper/gatekeeper-operator/api/v1beta1"
+)
+
+const (
+	// V1alpha1SpecAnnotation holds the v1alpha1 spec of a Gatekeeper
+	// resource converted to v1beta1 when the conversion loses part of it, so
+	// that converting the resource back restores the spec as written.
+	V1alpha1SpecAnnotation = "operator.gatekeeper.sh/v1alpha1-spec"
+	// V1beta1SpecAnnotation holds the v1beta1 spec of a Gatekeeper resource
+	// converted to v1alpha1 when the conversion loses part of it.
+	V1beta1SpecAnnotation = "operator.gatekeeper.sh/v1beta1-spec"
+)
+
+var _ conversion.Convertible = &Gatekeeper{}
+
+// ConvertTo converts the Gatekeeper resource to v1beta1, the hub and
+// storage version. The status conditions gain an observed generation and
+// lose their probe time, the status is otherwise preserved.
+func (r *Gatekeeper) ConvertTo(hub conversion.Hub) error {
+	dst := hub.(*v1beta1.Gatekeeper)
+	dst.ObjectMeta = *r.ObjectMeta.DeepCopy()
+	withoutSpecAnnotations(&dst.ObjectMeta)
+
+	dst.Spec = convertSpecToBeta(&r.Spec)
+	original := &v1beta1.GatekeeperSpec{}
+	if getAnnotatedSpec(&r.ObjectMeta, V1beta1SpecAnnotation, original) &&
+		equality.Semantic.DeepEqual(convertSpecFromBeta(original), r.Spec) {
+		dst.Spec = *original
+	}
+	if !equality.Semantic.DeepEqual(convertSpecFromBeta(&dst.Spec), r.Spec) {
+		if err := setAnnotatedSpec(&dst.ObjectMeta, V1alpha1SpecAnnotation, &r.Spec); err != nil {
+			return err
+		}
+	}
+
+	dst.Status = convertStatusToBeta(&r.Status)
+	return nil
+}
+
+// ConvertFrom converts the Gatekeeper resource from v1beta1, the hub and
+// storage version.
+func (r *Gatekeeper) ConvertFrom(hub conversion.Hub) error {
+	src := hub.(*v1beta1.Gatekeeper)
+	r.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	withoutSpecAnnotations(&r.ObjectMeta)
+
+	r.Spec = convertSpecFromBeta(&src.Spec)
+	original := &GatekeeperSpec{}
+	if getAnnotatedSpec(&src.ObjectMeta, V1alpha1SpecAnnotation, original) &&
+		equality.Semantic.DeepEqual(convertSpecToBeta(original), src.Spec) {
+		r.Spec = *original
+	}
+	if !equality.Semantic.DeepEqual(convertSpecToBeta(&r.Spec), src.Spec) {
+		if err := setAnnotatedSpec(&r.ObjectMeta, V1beta1SpecAnnotation, &src.Spec); err != nil {
+			return err
+		}
+	}
+
+	r.Status = convertStatusFromBeta(&src.Status)
+	return nil
+}
+
+// getAnnotatedSpec decodes the spec stored in the given annotation. An
+// annotation that cannot be decoded is ignored as the spec then no longer
+// matches it.
+func getAnnotatedSpec(meta *metav1.ObjectMeta, annotation string, spec interface{}) bool {
+	value, ok := meta.Annotations[annotation]
+	if !ok {
+		return false
+	}
+	return json.Unmarshal([]byte(value), spec) == nil
+}
+
+func setAnnotatedSpec(meta *metav1.ObjectMeta, annotation string, spec interface{}) error {
+	value, err := json.Marshal(spec)
+	if err != nil {
+		return errors.Wrapf(err, "Unable to encode %s annotation", annotation)
+	}
+	if meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+	meta.Annotations[annotation] = string(value)
+	return nil
+}
+
+func withoutSpecAnnotations(meta *metav1.ObjectMeta) {
+	delete(meta.Annotations, V1alpha1SpecAnnotation)
+	delete(meta.Annotations, V1beta1SpecAnnotation)
+	if len(meta.Annotations) == 0 {
+		meta.Annotations = nil
+	}
+}
+
+func convertSpecToBeta(in *GatekeeperSpec) v1beta1.GatekeeperSpec {
+	in = in.DeepCopy()
+	out := v1beta1.GatekeeperSpec{
+		ManagementState:  (*v1beta1.ManagementState)(in.ManagementState),
+		Version:          in.Version,
+		Upgrade:          (*v1beta1.UpgradeConfig)(in.Upgrade),
+		Image:            convertImageToBeta(in.Image),
+		PodSecurityLevel: (*v1beta1.PodSecurityLevel)(in.PodSecurityLevel),
+		Config:           convertConfigToBeta(in.Config),
+		UninstallPolicy:  (*v1beta1.UninstallPolicy)(in.UninstallPolicy),
+	}
+
+	// The pod annotations of a component replace those of the spec.
+	var auditScheduling, webhookScheduling *SchedulingConfig
+	auditAnnotations, webhookAnnotations := in.PodAnnotations, in.PodAnnotations
+	if in.Audit != nil {
+		auditScheduling = in.Audit.Scheduling
+		if in.Audit.PodAnnotations != nil {
+			auditAnnotations = in.Audit.PodAnnotations
+		}
+	}
+	if in.Webhook != nil {
+		webhookScheduling = in.Webhook.Scheduling
+		if in.Webhook.PodAnnotations != nil {
+			webhookAnnotations = in.Webhook.PodAnnotations
+		}
+	}
+
+	audit := v1beta1.AuditConfig{
+		PodConfig: v1beta1.PodConfig{
+			Scheduling:     convertSchedulingToBeta(in, auditScheduling),
+			PodAnnotations: auditAnnotations,
+		},
+	}
+	if in.Audit != nil {
+		audit.Replicas = in.Audit.Replicas
+		audit.AuditInterval = in.Audit.AuditInterval
+		audit.ConstraintViolationLimit = in.Audit.ConstraintViolationLimit
+		audit.AuditFromCache = (*v1beta1.Mode)(in.Audit.AuditFromCache)
+		audit.AuditChunkSize = in.Audit.AuditChunkSize
+		audit.LogLevel = (*v1beta1.LogLevel)(in.Audit.LogLevel)
+		audit.EmitAuditEvents = (*v1beta1.Mode)(in.Audit.EmitAuditEvents)
+		audit.Resources = in.Audit.Resources
+		audit.ExemptNamespaces = in.Audit.ExemptNamespaces
+		audit.Image = in.Audit.Image
+		audit.PodDisruptionBudget = (*v1beta1.PodDisruptionBudgetConfig)(in.Audit.PodDisruptionBudget)
+	}
+	if !equality.Semantic.DeepEqual(audit, v1beta1.AuditConfig{}) {
+		out.Audit = &audit
+	}
+
+	webhook := v1beta1.WebhookConfig{
+		PodConfig: v1beta1.PodConfig{
+			Scheduling:     convertSchedulingToBeta(in, webhookScheduling),
+			PodAnnotations: webhookAnnotations,
+		},
+	}
+	if in.Webhook != nil {
+		webhook.Replicas = in.Webhook.Replicas
+		webhook.LogLevel = (*v1beta1.LogLevel)(in.Webhook.LogLevel)
+		webhook.EmitAdmissionEvents = (*v1beta1.Mode)(in.Webhook.EmitAdmissionEvents)
+		webhook.Resources = in.Webhook.Resources
+		webhook.Certificates = convertCertificatesToBeta(in.Webhook.Certificates)
+		webhook.ExemptNamespaces = in.Webhook.ExemptNamespaces
+		webhook.FailurePolicy = in.Webhook.FailurePolicy
+		webhook.NamespaceSelector = in.Webhook.NamespaceSelector
+		webhook.ObjectSelector = in.Webhook.ObjectSelector
+		webhook.Rules = convertRulesToBeta(in.Webhook.Rules)
+		webhook.TimeoutSeconds = in.Webhook.TimeoutSeconds
+		webhook.MatchPolicy = in.Webhook.MatchPolicy
+		webhook.Image = in.Webhook.Image
+		webhook.PodDisruptionBudget = (*v1beta1.PodDisruptionBudgetConfig)(in.Webhook.PodDisruptionBudget)
+	}
+	if in.ValidatingWebhook != nil {
+		webhook.Validating = &v1beta1.ValidatingWebhookConfig{Mode: (*v1beta1.Mode)(in.ValidatingWebhook)}
+	}
+	mutating := v1beta1.MutatingWebhookConfig{
+		Mode:       (*v1beta1.Mode)(in.MutatingWebhook),
+		CRDsPolicy: (*v1beta1.MutationCRDsPolicy)(in.MutationCRDsPolicy),
+	}
+	if in.MutatingWebhookConfig != nil {
+		mutating.FailurePolicy = in.MutatingWebhookConfig.FailurePolicy
+		mutating.NamespaceSelector = in.MutatingWebhookConfig.NamespaceSelector
+		mutating.ObjectSelector = in.MutatingWebhookConfig.ObjectSelector
+		mutating.TimeoutSeconds = in.MutatingWebhookConfig.TimeoutSeconds
+		mutating.ReinvocationPolicy = in.MutatingWebhookConfig.ReinvocationPolicy
+		mutating.Rules = convertRulesToBeta(in.MutatingWebhookConfig.Rules)
+	}
+	if !equality.Semantic.DeepEqual(mutating, v1beta1.MutatingWebhookConfig{}) {
+		webhook.Mutating = &mutating
+	}
+	if !equality.Semantic.DeepEqual(webhook, v1beta1.WebhookConfig{}) {
+		out.Webhook = &webhook
+	}
+
+	return out
+}
+
+func convertSpecFromBeta(in *v1beta1.GatekeeperSpec) GatekeeperSpec {
+	in = in.DeepCopy()
+	out := GatekeeperSpec{
+		ManagementState:  (*ManagementState)(in.ManagementState),
+		Version:          in.Version,
+		Upgrade:          (*UpgradeConfig)(in.Upgrade),
+		Image:            convertImageFromBeta(in.Image),
+		PodSecurityLevel: (*PodSecurityLevel)(in.PodSecurityLevel),
+		Config:           convertConfigFromBeta(in.Config),
+		UninstallPolicy:  (*UninstallPolicy)(in.UninstallPolicy),
+	}
+
+	audit := &v1beta1.AuditConfig{}
+	if in.Audit != nil {
+		audit = in.Audit
+	}
+	webhook := &v1beta1.WebhookConfig{}
+	if in.Webhook != nil {
+		webhook = in.Webhook
+	}
+	// The pod annotations the components have in common are shared.
+	var auditAnnotations, webhookAnnotations map[string]string
+	if equality.Semantic.DeepEqual(audit.PodAnnotations, webhook.PodAnnotations) {
+		out.PodAnnotations = webhook.PodAnnotations
+	} else {
+		auditAnnotations = audit.PodAnnotations
+		webhookAnnotations = webhook.PodAnnotations
+	}
+	auditScheduling, webhookScheduling := convertSchedulingFromBeta(&out, audit.Scheduling, webhook.Scheduling)
+
+	alphaAudit := AuditConfig{
+		Replicas:                 audit.Replicas,
+		AuditInterval:            audit.AuditInterval,
+		ConstraintViolationLimit: audit.ConstraintViolationLimit,
+		AuditFromCache:           (*AuditFromCacheMode)(audit.AuditFromCache),
+		AuditChunkSize:           audit.AuditChunkSize,
+		LogLevel:                 (*LogLevelMode)(audit.LogLevel),
+		EmitAuditEvents:          (*EmitEventsMode)(audit.EmitAuditEvents),
+		Resources:                audit.Resources,
+		ExemptNamespaces:         audit.ExemptNamespaces,
+		Scheduling:               auditScheduling,
+		PodAnnotations:           auditAnnotations,
+		Image:                    audit.Image,
+		PodDisruptionBudget:      (*PodDisruptionBudgetConfig)(audit.PodDisruptionBudget),
+	}
+	if !equality.Semantic.DeepEqual(alphaAudit, AuditConfig{}) {
+		out.Audit = &alphaAudit
+	}
+
+	alphaWebhook := WebhookConfig{
+		Replicas:            webhook.Replicas,
+		LogLevel:            (*LogLevelMode)(webhook.LogLevel),
+		EmitAdmissionEvents: (*EmitEventsMode)(webhook.EmitAdmissionEvents),
+		FailurePolicy:       webhook.FailurePolicy,
+		NamespaceSelector:   webhook.NamespaceSelector,
+		ObjectSelector:      webhook.ObjectSelector,
+		Rules:               convertRulesFromBeta(webhook.Rules),
+		TimeoutSeconds:      webhook.TimeoutSeconds,
+		MatchPolicy:         webhook.MatchPolicy,
+		Resources:           webhook.Resources,
+		Certificates:        convertCertificatesFromBeta(webhook.Certificates),
+		ExemptNamespaces:    webhook.ExemptNamespaces,
+		Scheduling:          webhookScheduling,
+		PodAnnotations:      webhookAnnotations,
+		Image:               webhook.Image,
+		PodDisruptionBudget: (*PodDisruptionBudgetConfig)(webhook.PodDisruptionBudget),
+	}
+	if !equality.Semantic.DeepEqual(alphaWebhook, WebhookConfig{}) {
+		out.Webhook = &alphaWebhook
+	}
+	if webhook.Validating != nil {
+		out.ValidatingWebhook = (*WebhookMode)(webhook.Validating.Mode)
+	}
+	if mutating := webhook.Mutating; mutating != nil {
+		out.MutatingWebhook = (*WebhookMode)(mutating.Mode)
+		out.MutationCRDsPolicy = (*MutationCRDsPolicy)(mutating.CRDsPolicy)
+		mutatingConfig := MutatingWebhookConfig{
+			FailurePolicy:      mutating.FailurePolicy,
+			NamespaceSelector:  mutating.NamespaceSelector,
+			ObjectSelector:     mutating.ObjectSelector,
+			TimeoutSeconds:     mutating.TimeoutSeconds,
+			ReinvocationPolicy: mutating.ReinvocationPolicy,
+			Rules:              convertRulesFromBeta(mutating.Rules),
+		}
+		if !equality.Semantic.DeepEqual(mutatingConfig, MutatingWebhookConfig{}) {
+			out.MutatingWebhookConfig = &mutatingConfig
+		}
+	}
+
+	return out
+}
+
+// convertSchedulingToBeta returns the scheduling of a component, whose
+// fields take precedence over the fields of the spec shared by both
+// components.
+func convertSchedulingToBeta(spec *GatekeeperSpec, component *SchedulingConfig) *v1beta1.SchedulingConfig {
+	out := v1beta1.SchedulingConfig{
+		NodeSelector: spec.NodeSelector,
+		Affinity:     spec.Affinity,
+		Tolerations:  spec.Tolerations,
+	}
+	if component != nil {
+		if component.NodeSelector != nil {
+			out.NodeSelector = component.NodeSelector
+		}
+		if component.Affinity != nil {
+			out.Affinity = component.Affinity
+		}
+		if component.Tolerations != nil {
+			out.Tolerations = component.Tolerations
+		}
+		out.TopologySpreadConstraints = component.TopologySpreadConstraints
+	}
+	if equality.Semantic.DeepEqual(out, v1beta1.SchedulingConfig{}) {
+		return nil
+	}
+	return out.DeepCopy()
+}
+
+// convertSchedulingFromBeta sets the scheduling fields of the spec shared by
+// both components to the fields the audit and webhook have in common and
+// returns the scheduling of each component with the other fields.
+func convertSchedulingFromBeta(spec *GatekeeperSpec, audit, webhook *v1beta1.SchedulingConfig) (*SchedulingConfig, *SchedulingConfig) {
+	if audit == nil {
+		audit = &v1beta1.SchedulingConfig{}
+	}
+	if webhook == nil {
+		webhook = &v1beta1.SchedulingConfig{}
+	}
+	auditOut := &SchedulingConfig{TopologySpreadConstraints: audit.TopologySpreadConstraints}
+	webhookOut := &SchedulingConfig{TopologySpreadConstraints: webhook.TopologySpreadConstraints}
+	if equality.Semantic.DeepEqual(audit.NodeSelector, webhook.NodeSelector) {
+		spec.NodeSelector = webhook.NodeSelector
+	} else {
+		auditOut.NodeSelector = audit.NodeSelector
+		webhookOut.NodeSelector = webhook.NodeSelector
+	}
+	if equality.Semantic.DeepEqual(audit.Affinity, webhook.Affinity) {
+		spec.Affinity = webhook.Affinity
+	} else {
+		auditOut.Affinity = audit.Affinity
+		webhookOut.Affinity = webhook.Affinity
+	}
+	if equality.Semantic.DeepEqual(audit.Tolerations, webhook.Tolerations) {
+		spec.Tolerations = webhook.Tolerations
+	} else {
+		auditOut.Tolerations = audit.Tolerations
+		webhookOut.Tolerations = webhook.Tolerations
+	}
+	if equality.Semantic.DeepEqual(auditOut, &SchedulingConfig{}) {
+		auditOut = nil
+	}
+	if equality.Semantic.DeepEqual(webhookOut, &SchedulingConfig{}) {
+		webhookOut = nil
+	}
+	return auditOut, webhookOut
+}
+
+func convertRulesToBeta(in []WebhookRule) []v1beta1.WebhookRule {
+	if in == nil {
+		return nil
+	}
+	out := make([]v1beta1.WebhookRule, 0, len(in))
+	for _, rule := range in {
+		operations := make([]v1beta1.WebhookOperation, 0, len(rule.Operations))
+		for _, operation := range rule.Operations {
+			operations = append(operations, v1beta1.WebhookOperation(operation))
+		}
+		out = append(out, v1beta1.WebhookRule{
+			Operations:  operations,
+			APIGroups:   rule.APIGroups,
+			APIVersions: rule.APIVersions,
+			Resources:   rule.Resources,
+		})
+	}
+	return out
+}
+
+func convertRulesFromBeta(in []v1beta1.WebhookRule) []WebhookRule {
+	if in == nil {
+		return nil
+	}
+	out := make([]WebhookRule, 0, len(in))
+	for _, rule := range in {
+		operations := make([]WebhookOperation, 0, len(rule.Operations))
+		for _, operation := range rule.Operations {
+			operations = append(operations, WebhookOperation(operation))
+		}
+		out = append(out, WebhookRule{
+			Operations:  operations,
+			APIGroups:   rule.APIGroups,
+			APIVersions: rule.APIVersions,
+			Resources:   rule.Resources,
+		})
+	}
+	return out
+}
+
+func convertImageToBeta(in *ImageConfig) *v1beta1.ImageConfig {
+	if in == nil {
+		return nil
+	}
+	return &v1beta1.ImageConfig{
+		Image:            in.Image,
+		ImagePullPolicy:  in.ImagePullPolicy,
+		PullSecrets:      in.PullSecrets,
+		DigestPinning:    (*v1beta1.Mode)(in.DigestPinning),
+		RegistryOverride: in.RegistryOverride,
+	}
+}
+
+func convertImageFromBeta(in *v1beta1.ImageConfig) *ImageConfig {
+	if in == nil {
+		return nil
+	}
+	return &ImageConfig{
+		Image:            in.Image,
+		ImagePullPolicy:  in.ImagePullPolicy,
+		PullSecrets:      in.PullSecrets,
+		DigestPinning:    (*DigestPinningMode)(in.DigestPinning),
+		RegistryOverride: in.RegistryOverride,
+	}
+}
+
+func convertCertificatesToBeta(in *CertificatesConfig) *v1beta1.CertificatesConfig {
+	if in == nil {
+		return nil
+	}
+	return &v1beta1.CertificatesConfig{
+		Mode:         (*v1beta1.CertificatesMode)(in.Mode),
+		Validity:     in.Validity,
+		RotateBefore: in.RotateBefore,
+		SecretRef:    in.SecretRef,
+	}
+}
+
+func convertCertificatesFromBeta(in *v1beta1.CertificatesConfig) *CertificatesConfig {
+	if in == nil {
+		return nil
+	}
+	return &CertificatesConfig{
+		Mode:         (*CertificatesMode)(in.Mode),
+		Validity:     in.Validity,
+		RotateBefore: in.RotateBefore,
+		SecretRef:    in.SecretRef,
+	}
+}
+
+func convertConfigToBeta(in *ConfigSpec) *v1beta1.ConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := &v1beta1.ConfigSpec{}
+	if in.Sync != nil {
+		out.Sync = &v1beta1.SyncConfig{}
+		if in.Sync.SyncOnly != nil {
+			out.Sync.SyncOnly = make([]v1beta1.GVK, 0, len(in.Sync.SyncOnly))
+			for _, gvk := range in.Sync.SyncOnly {
+				out.Sync.SyncOnly = append(out.Sync.SyncOnly, v1beta1.GVK(gvk))
+			}
+		}
+	}
+	if in.Match != nil {
+		out.Match = make([]v1beta1.MatchEntry, 0, len(in.Match))
+		for _, match := range in.Match {
+			out.Match = append(out.Match, v1beta1.MatchEntry(match))
+		}
+	}
+	if in.Validation != nil {
+		out.Validation = &v1beta1.ValidationConfig{}
+		if in.Validation.Traces != nil {
+			out.Validation.Traces = make([]v1beta1.TraceEntry, 0, len(in.Validation.Traces))
+			for _, trace := range in.Validation.Traces {
+				out.Validation.Traces = append(out.Validation.Traces, v1beta1.TraceEntry{
+					User: trace.User,
+					Kind: v1beta1.GVK(trace.Kind),
+					Dump: trace.Dump,
+				})
+			}
+		}
+	}
+	if in.Readiness != nil {
+		out.Readiness = &v1beta1.ReadinessConfig{}
+		if in.Readiness.StatsEnabled {
+			stats := v1beta1.Enabled
+			out.Readiness.Stats = &stats
+		}
+	}
+	return out
+}
+
+func convertConfigFromBeta(in *v1beta1.ConfigSpec) *ConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := &ConfigSpec{}
+	if in.Sync != nil {
+		out.Sync = &SyncConfig{}
+		if in.Sync.SyncOnly != nil {
+			out.Sync.SyncOnly = make([]GVK, 0, len(in.Sync.SyncOnly))
+			for _, gvk := range in.Sync.SyncOnly {
+				out.Sync.SyncOnly = append(out.Sync.SyncOnly, GVK(gvk))
+			}
+		}
+	}
+	if in.Match != nil {
+		out.Match = make([]MatchEntry, 0, len(in.Match))
+		for _, match := range in.Match {
+			out.Match = append(out.Match, MatchEntry(match))
+		}
+	}
+	if in.Validation != nil {
+		out.Validation = &ValidationConfig{}
+		if in.Validation.Traces != nil {
+			out.Validation.Traces = make([]TraceEntry, 0, len(in.Validation.Traces))
+			for _, trace := range in.Validation.Traces {
+				out.Validation.Traces = append(out.Validation.Traces, TraceEntry{
+					User: trace.User,
+					Kind: GVK(trace.Kind),
+					Dump: trace.Dump,
+				})
+			}
+		}
+	}
+	if in.Readiness != nil {
+		out.Readiness = &ReadinessConfig{
+			StatsEnabled: in.Readiness.Stats != nil && *in.Readiness.Stats == v1beta1.Enabled,
+		}
+	}
+	return out
+}
+
+func convertStatusToBeta(in *GatekeeperStatus) v1beta1.GatekeeperStatus {
+	in = in.DeepCopy()
+	out := v1beta1.GatekeeperStatus{
+		ObservedGeneration: in.ObservedGeneration,
+		APIVersions:        in.APIVersions,
+		Version:            in.Version,
+		LastKnownGood:      convertReleaseToBeta(in.LastKnownGood),
+		Images:             (*v1beta1.ComponentImages)(in.Images),
+	}
+	out.Conditions = appendConditionToBeta(out.Conditions, v1beta1.ConditionAuditReady, in.AuditConditions, in.ObservedGeneration)
+	out.Conditions = appendConditionToBeta(out.Conditions, v1beta1.ConditionWebhookReady, in.WebhookConditions, in.ObservedGeneration)
+	out.Conditions = appendConditionToBeta(out.Conditions, v1beta1.ConditionUpgraded, in.UpgradeConditions, in.ObservedGeneration)
+	out.Conditions = appendConditionToBeta(out.Conditions, v1beta1.ConditionDegraded, in.DegradedConditions, in.ObservedGeneration)
+	if in.Certificates != nil {
+		out.Certificates = &v1beta1.CertificatesStatus{
+			Mode:     v1beta1.CertificatesMode(in.Certificates.Mode),
+			NotAfter: in.Certificates.NotAfter,
+		}
+	}
+	if in.Conflicts != nil {
+		out.Conflicts = make([]v1beta1.ApplyConflict, 0, len(in.Conflicts))
+		for _, conflict := range in.Conflicts {
+			out.Conflicts = append(out.Conflicts, v1beta1.ApplyConflict(conflict))
+		}
+	}
+	if in.ImageDigests != nil {
+		out.ImageDigests = make([]v1beta1.ImageDigest, 0, len(in.ImageDigests))
+		for _, digest := range in.ImageDigests {
+			out.ImageDigests = append(out.ImageDigests, v1beta1.ImageDigest(digest))
+		}
+	}
+	if in.Upgrade != nil {
+		out.Upgrade = &v1beta1.UpgradeStatus{
+			Release:   *convertReleaseToBeta(&in.Upgrade.Release),
+			Phase:     v1beta1.UpgradePhase(in.Upgrade.Phase),
+			StartTime: in.Upgrade.StartTime,
+		}
+	}
+	return out
+}
+
+// convertReleaseToBeta converts the release along with the spec recorded
+// with it. A spec that cannot be decoded is dropped, so that the release
+// alone is restored.
+func convertReleaseToBeta(in *GatekeeperRelease) *v1beta1.GatekeeperRelease {
+	if in == nil {
+		return nil
+	}
+	out := &v1beta1.GatekeeperRelease{
+		Version:      in.Version,
+		Image:        in.Image,
+		AuditImage:   in.AuditImage,
+		WebhookImage: in.WebhookImage,
+	}
+	spec := &GatekeeperSpec{}
+	if in.Spec != nil && json.Unmarshal(in.Spec.Raw, spec) == nil {
+		out.Spec = encodeReleaseSpec(convertSpecToBeta(spec))
+	}
+	return out
+}
+
+func convertReleaseFromBeta(in *v1beta1.GatekeeperRelease) *GatekeeperRelease {
+	if in == nil {
+		return nil
+	}
+	out := &GatekeeperRelease{
+		Version:      in.Version,
+		Image:        in.Image,
+		AuditImage:   in.AuditImage,
+		WebhookImage: in.WebhookImage,
+	}
+	spec := &v1beta1.GatekeeperSpec{}
+	if in.Spec != nil && json.Unmarshal(in.Spec.Raw, spec) == nil {
+		out.Spec = encodeReleaseSpec(convertSpecFromBeta(spec))
+	}
+	return out
+}
+
+func encodeReleaseSpec(spec interface{}) *runtime.RawExtension {
+	raw, err := json.Marshal(spec)
+	if err != nil {
+		return nil
+	}
+	return &runtime.RawExtension{Raw: raw}
+}
+
+// appendConditionToBeta converts the condition of a v1alpha1 condition
+// list, which the operator keeps to a single condition, to a condition of
+// the given type. A Degraded condition keeps its status, the others are
+// true when Ready.
+func appendConditionToBeta(conditions []metav1.Condition, conditionType string,
+	in []StatusCondition, generation int64) []metav1.Condition {
+	if len(in) == 0 {
+		return conditions
+	}
+	status := metav1.ConditionUnknown
+	switch {
+	case in[0].Type == StatusDegraded:
+		status = metav1.ConditionStatus(in[0].Status)
+	case in[0].Status == corev1.ConditionTrue:
+		status = metav1.ConditionFalse
+		if in[0].Type == StatusReady {
+			status = metav1.ConditionTrue
+		}
+	}
+	return append(conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: generation,
+		LastTransitionTime: in[0].LastTransitionTime,
+		Reason:             in[0].Reason,
+		Message:            in[0].Message,
+	})
+}
+
+func convertStatusFromBeta(in *v1beta1.GatekeeperStatus) GatekeeperStatus {
+	in = in.DeepCopy()
+	out := GatekeeperStatus{
+		ObservedGeneration: in.ObservedGeneration,
+		APIVersions:        in.APIVersions,
+		Version:            in.Version,
+		LastKnownGood:      convertReleaseFromBeta(in.LastKnownGood),
+		Images:             (*ComponentImages)(in.Images),
+	}
+	for _, condition := range in.Conditions {
+		switch condition.Type {
+		case v1beta1.ConditionAuditReady:
+			out.AuditConditions = []StatusCondition{convertConditionFromBeta(condition)}
+		case v1beta1.ConditionWebhookReady:
+			out.WebhookConditions = []StatusCondition{convertConditionFromBeta(condition)}
+		case v1beta1.ConditionUpgraded:
+			out.UpgradeConditions = []StatusCondition{convertConditionFromBeta(condition)}
+		case v1beta1.ConditionDegraded:
+			degraded := convertConditionFromBeta(condition)
+			degraded.Type = StatusDegraded
+			degraded.Status = corev1.ConditionStatus(condition.Status)
+			out.DegradedConditions = []StatusCondition{degraded}
+		}
+	}
+	if in.Certificates != nil {
+		out.Certificates = &CertificatesStatus{
+			Mode:     CertificatesMode(in.Certificates.Mode),
+			NotAfter: in.Certificates.NotAfter,
+		}
+	}
+	if in.Conflicts != nil {
+		out.Conflicts = make([]ApplyConflict, 0, len(in.Conflicts))
+		for _, conflict := range in.Conflicts {
+			out.Conflicts = append(out.Conflicts, ApplyConflict(conflict))
+		}
+	}
+	if in.ImageDigests != nil {
+		out.ImageDigests = make([]ImageDigest, 0, len(in.ImageDigests))
+		for _, digest := range in.ImageDigests {
+			out.ImageDigests = append(out.ImageDigests, ImageDigest(digest))
+		}
+	}
+	if in.Upgrade != nil {
+		out.Upgrade = &UpgradeStatus{
+			Release:   *convertReleaseFromBeta(&in.Upgrade.Release),
+			Phase:     UpgradePhase(in.Upgrade.Phase),
+			StartTime: in.Upgrade.StartTime,
+		}
+	}
+	return out
+}
+
+func convertConditionFromBeta(in metav1.Condition) StatusCondition {
+	out := StatusCondition{
+		Type:               StatusNotReady,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: in.LastTransitionTime,
+		Reason:             in.Reason,
+		Message:            in.Message,
+	}
+	switch in.Status {
+	case metav1.ConditionTrue:
+		out.Type = StatusReady
+	case metav1.ConditionUnknown:
+		out.Status = corev1.ConditionUnknown
+	}
+	return out
+}
@@ -0,0 +1,362 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+	"testing"
+
+	fuzz "github.com/google/gofuzz"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/diff"
+
+	"github.com/gatekeeper/gatekeeper-operator/api/v1beta1"
+)
+
+const fuzzIterations = 500
+
+// fuzzAlphaStatus limits the v1alpha1 status to the single condition per
+// list written by the operator.
+func fuzzAlphaStatus(status *GatekeeperStatus, c fuzz.Continue) {
+	c.FuzzNoCustom(status)
+	statuses := []corev1.ConditionStatus{corev1.ConditionTrue, corev1.ConditionFalse, corev1.ConditionUnknown}
+	if len(status.DegradedConditions) > 1 {
+		status.DegradedConditions = status.DegradedConditions[:1]
+	}
+	for i := range status.DegradedConditions {
+		condition := &status.DegradedConditions[i]
+		condition.Type = StatusDegraded
+		condition.Status = statuses[c.Intn(len(statuses))]
+		condition.LastProbeTime = metav1.Time{}
+	}
+	for _, conditions := range []*[]StatusCondition{
+		&status.AuditConditions, &status.WebhookConditions, &status.UpgradeConditions,
+	} {
+		if len(*conditions) > 1 {
+			*conditions = (*conditions)[:1]
+		}
+		for i := range *conditions {
+			condition := &(*conditions)[i]
+			condition.Type = StatusNotReady
+			if c.RandBool() {
+				condition.Type = StatusReady
+			}
+			condition.Status = corev1.ConditionTrue
+			condition.LastProbeTime = metav1.Time{}
+		}
+	}
+}
+
+// fuzzAlphaSpec leaves out the empty audit and webhook configurations and
+// sets the scheduling fields and pod annotations as the conversion from
+// v1beta1 does, as other specs do not convert to v1beta1 without the
+// v1alpha1 spec annotation.
+func fuzzAlphaSpec(spec *GatekeeperSpec, c fuzz.Continue) {
+	c.FuzzNoCustom(spec)
+	if spec.Audit == nil {
+		spec.Audit = &AuditConfig{}
+	}
+	if spec.Audit.Scheduling == nil {
+		spec.Audit.Scheduling = &SchedulingConfig{}
+	}
+	if spec.Webhook == nil {
+		spec.Webhook = &WebhookConfig{}
+	}
+	if spec.Webhook.Scheduling == nil {
+		spec.Webhook.Scheduling = &SchedulingConfig{}
+	}
+	for _, name := range []string{"NodeSelector", "Affinity", "Tolerations"} {
+		shared := reflect.ValueOf(spec).Elem().FieldByName(name)
+		audit := reflect.ValueOf(spec.Audit.Scheduling).Elem().FieldByName(name)
+		webhook := reflect.ValueOf(spec.Webhook.Scheduling).Elem().FieldByName(name)
+		if audit.IsNil() {
+			audit.Set(shared)
+		}
+		if webhook.IsNil() {
+			webhook.Set(shared)
+		}
+		shared.Set(reflect.Zero(shared.Type()))
+		if equality.Semantic.DeepEqual(audit.Interface(), webhook.Interface()) {
+			shared.Set(webhook)
+			audit.Set(reflect.Zero(audit.Type()))
+			webhook.Set(reflect.Zero(webhook.Type()))
+		}
+	}
+	auditAnnotations, webhookAnnotations := spec.PodAnnotations, spec.PodAnnotations
+	if spec.Audit.PodAnnotations != nil {
+		auditAnnotations = spec.Audit.PodAnnotations
+	}
+	if spec.Webhook.PodAnnotations != nil {
+		webhookAnnotations = spec.Webhook.PodAnnotations
+	}
+	spec.PodAnnotations = nil
+	spec.Audit.PodAnnotations, spec.Webhook.PodAnnotations = auditAnnotations, webhookAnnotations
+	if equality.Semantic.DeepEqual(auditAnnotations, webhookAnnotations) {
+		spec.PodAnnotations = webhookAnnotations
+		spec.Audit.PodAnnotations, spec.Webhook.PodAnnotations = nil, nil
+	}
+	if equality.Semantic.DeepEqual(spec.Audit.Scheduling, &SchedulingConfig{}) {
+		spec.Audit.Scheduling = nil
+	}
+	if equality.Semantic.DeepEqual(spec.Webhook.Scheduling, &SchedulingConfig{}) {
+		spec.Webhook.Scheduling = nil
+	}
+	if equality.Semantic.DeepEqual(spec.Audit, &AuditConfig{}) {
+		spec.Audit = nil
+	}
+	if equality.Semantic.DeepEqual(spec.Webhook, &WebhookConfig{}) {
+		spec.Webhook = nil
+	}
+	if equality.Semantic.DeepEqual(spec.MutatingWebhookConfig, &MutatingWebhookConfig{}) {
+		spec.MutatingWebhookConfig = nil
+	}
+}
+
+// fuzzBetaStatus limits the v1beta1 status to the known condition types
+// observed at the generation of the status.
+func fuzzBetaStatus(status *v1beta1.GatekeeperStatus, c fuzz.Continue) {
+	c.FuzzNoCustom(status)
+	statuses := []metav1.ConditionStatus{metav1.ConditionTrue, metav1.ConditionFalse, metav1.ConditionUnknown}
+	var conditions []metav1.Condition
+	conditionTypes := []string{
+		v1beta1.ConditionAuditReady, v1beta1.ConditionWebhookReady, v1beta1.ConditionUpgraded, v1beta1.ConditionDegraded,
+	}
+	for _, conditionType := range conditionTypes {
+		if c.RandBool() {
+			continue
+		}
+		condition := metav1.Condition{}
+		c.Fuzz(&condition)
+		condition.Type = conditionType
+		condition.Status = statuses[c.Intn(len(statuses))]
+		condition.ObservedGeneration = status.ObservedGeneration
+		conditions = append(conditions, condition)
+	}
+	status.Conditions = conditions
+}
+
+// fuzzReleaseSpec returns a spec that converts to v1beta1 without the
+// v1alpha1 spec annotation, which the spec recorded with a release has no
+// room for. It is converted once so that its empty and nil lists encode
+// alike.
+func fuzzReleaseSpec(c fuzz.Continue) v1beta1.GatekeeperSpec {
+	spec := &GatekeeperSpec{}
+	c.Fuzz(spec)
+	converted := convertSpecToBeta(spec)
+	restored := convertSpecFromBeta(&converted)
+	return convertSpecToBeta(&restored)
+}
+
+func fuzzAlphaRelease(release *GatekeeperRelease, c fuzz.Continue) {
+	c.Fuzz(&release.Version)
+	c.Fuzz(&release.Image)
+	c.Fuzz(&release.AuditImage)
+	c.Fuzz(&release.WebhookImage)
+	if c.RandBool() {
+		spec := fuzzReleaseSpec(c)
+		release.Spec = encodeReleaseSpec(convertSpecFromBeta(&spec))
+	}
+}
+
+func fuzzBetaRelease(release *v1beta1.GatekeeperRelease, c fuzz.Continue) {
+	c.Fuzz(&release.Version)
+	c.Fuzz(&release.Image)
+	c.Fuzz(&release.AuditImage)
+	c.Fuzz(&release.WebhookImage)
+	if c.RandBool() {
+		release.Spec = encodeReleaseSpec(fuzzReleaseSpec(c))
+	}
+}
+
+func newFuzzer() *fuzz.Fuzzer {
+	return fuzz.New().NilChance(0.3).Funcs(fuzzAlphaSpec, fuzzAlphaStatus, fuzzBetaStatus,
+		fuzzAlphaRelease, fuzzBetaRelease)
+}
+
+func TestConvertToBetaRoundTrip(t *testing.T) {
+	g := NewWithT(t)
+	f := newFuzzer()
+	for i := 0; i < fuzzIterations; i++ {
+		original := &Gatekeeper{}
+		f.Fuzz(&original.ObjectMeta)
+		f.Fuzz(&original.Spec)
+		f.Fuzz(&original.Status)
+
+		converted := &v1beta1.Gatekeeper{}
+		g.Expect(original.ConvertTo(converted)).To(Succeed())
+		// v1beta1 can represent any v1alpha1 spec
+		g.Expect(converted.Annotations).NotTo(HaveKey(V1alpha1SpecAnnotation))
+		restored := &Gatekeeper{}
+		g.Expect(restored.ConvertFrom(converted)).To(Succeed())
+
+		g.Expect(equality.Semantic.DeepEqual(restored.ObjectMeta, original.ObjectMeta)).To(BeTrue(), func() string {
+			return diff.ObjectReflectDiff(original.ObjectMeta, restored.ObjectMeta)
+		})
+		g.Expect(equality.Semantic.DeepEqual(restored.Spec, original.Spec)).To(BeTrue(), func() string {
+			return diff.ObjectReflectDiff(original.Spec, restored.Spec)
+		})
+		g.Expect(equality.Semantic.DeepEqual(restored.Status, original.Status)).To(BeTrue(), func() string {
+			return diff.ObjectReflectDiff(original.Status, restored.Status)
+		})
+	}
+}
+
+func TestConvertFromBetaRoundTrip(t *testing.T) {
+	g := NewWithT(t)
+	f := newFuzzer()
+	for i := 0; i < fuzzIterations; i++ {
+		original := &v1beta1.Gatekeeper{}
+		f.Fuzz(&original.ObjectMeta)
+		f.Fuzz(&original.Spec)
+		f.Fuzz(&original.Status)
+
+		converted := &Gatekeeper{}
+		g.Expect(converted.ConvertFrom(original)).To(Succeed())
+		restored := &v1beta1.Gatekeeper{}
+		g.Expect(converted.ConvertTo(restored)).To(Succeed())
+
+		g.Expect(equality.Semantic.DeepEqual(restored.ObjectMeta, original.ObjectMeta)).To(BeTrue(), func() string {
+			return diff.ObjectReflectDiff(original.ObjectMeta, restored.ObjectMeta)
+		})
+		g.Expect(equality.Semantic.DeepEqual(restored.Spec, original.Spec)).To(BeTrue(), func() string {
+			return diff.ObjectReflectDiff(original.Spec, restored.Spec)
+		})
+		g.Expect(equality.Semantic.DeepEqual(restored.Status, original.Status)).To(BeTrue(), func() string {
+			return diff.ObjectReflectDiff(original.Status, restored.Status)
+		})
+	}
+}
+
+func TestConvertToBeta(t *testing.T) {
+	g := NewWithT(t)
+	enabled := WebhookEnabled
+	replicas := int32(2)
+	original := &Gatekeeper{
+		Spec: GatekeeperSpec{
+			MutatingWebhook: &enabled,
+			Webhook:         &WebhookConfig{Replicas: &replicas},
+			Audit:           &AuditConfig{PodAnnotations: map[string]string{"foo": "audit"}},
+			NodeSelector:    map[string]string{"kubernetes.io/os": "linux"},
+			PodAnnotations:  map[string]string{"foo": "bar"},
+		},
+		Status: GatekeeperStatus{
+			ObservedGeneration: 3,
+			AuditConditions: []StatusCondition{{
+				Type:   StatusReady,
+				Status: corev1.ConditionTrue,
+				Reason: "DeploymentAvailable",
+			}},
+			WebhookConditions: []StatusCondition{{
+				Type:   StatusNotReady,
+				Status: corev1.ConditionTrue,
+				Reason: "RolloutInProgress",
+			}},
+		},
+	}
+
+	// the shared pod annotations replaced for the audit only convert back
+	// to annotations set for each component, so the spec is kept as written
+	converted := &v1beta1.Gatekeeper{}
+	g.Expect(original.ConvertTo(converted)).To(Succeed())
+	g.Expect(converted.Annotations).To(HaveKey(V1alpha1SpecAnnotation))
+
+	// test shared settings apply to both components unless set for one
+	spec := converted.Spec
+	g.Expect(*spec.Webhook.Mutating.Mode).To(Equal(v1beta1.Enabled))
+	g.Expect(*spec.Webhook.Replicas).To(Equal(replicas))
+	g.Expect(spec.Webhook.Scheduling.NodeSelector).To(Equal(original.Spec.NodeSelector))
+	g.Expect(spec.Webhook.PodAnnotations).To(Equal(original.Spec.PodAnnotations))
+	g.Expect(spec.Audit.Scheduling.NodeSelector).To(Equal(original.Spec.NodeSelector))
+	g.Expect(spec.Audit.PodAnnotations).To(Equal(original.Spec.Audit.PodAnnotations))
+	g.Expect(spec.Audit.Replicas).To(BeNil())
+
+	conditions := converted.Status.Conditions
+	g.Expect(conditions).To(HaveLen(2))
+	g.Expect(conditions[0].Type).To(Equal(v1beta1.ConditionAuditReady))
+	g.Expect(conditions[0].Status).To(Equal(metav1.ConditionTrue))
+	g.Expect(conditions[0].ObservedGeneration).To(Equal(int64(3)))
+	g.Expect(conditions[1].Type).To(Equal(v1beta1.ConditionWebhookReady))
+	g.Expect(conditions[1].Status).To(Equal(metav1.ConditionFalse))
+	g.Expect(conditions[1].Reason).To(Equal("RolloutInProgress"))
+}
+
+func TestConvertFromBeta(t *testing.T) {
+	g := NewWithT(t)
+	tolerations := []corev1.Toleration{{Key: "node-role.kubernetes.io/infra", Operator: corev1.TolerationOpExists}}
+	original := &v1beta1.Gatekeeper{
+		Spec: v1beta1.GatekeeperSpec{
+			Audit: &v1beta1.AuditConfig{
+				PodConfig: v1beta1.PodConfig{
+					Scheduling: &v1beta1.SchedulingConfig{
+						NodeSelector: map[string]string{"node-role.kubernetes.io/infra": ""},
+						Tolerations:  tolerations,
+					},
+					PodAnnotations: map[string]string{"foo": "audit"},
+				},
+			},
+			Webhook: &v1beta1.WebhookConfig{
+				PodConfig: v1beta1.PodConfig{
+					Scheduling: &v1beta1.SchedulingConfig{
+						Tolerations: tolerations,
+						TopologySpreadConstraints: []corev1.TopologySpreadConstraint{{
+							MaxSkew:           1,
+							TopologyKey:       "topology.kubernetes.io/zone",
+							WhenUnsatisfiable: corev1.ScheduleAnyway,
+						}},
+					},
+					PodAnnotations: map[string]string{"foo": "webhook"},
+				},
+			},
+		},
+	}
+
+	// test the scheduling fields the components have in common are shared
+	converted := &Gatekeeper{}
+	g.Expect(converted.ConvertFrom(original)).To(Succeed())
+	spec := converted.Spec
+	g.Expect(spec.Tolerations).To(Equal(tolerations))
+	g.Expect(spec.NodeSelector).To(BeNil())
+	g.Expect(spec.Audit.Scheduling.NodeSelector).To(Equal(original.Spec.Audit.Scheduling.NodeSelector))
+	g.Expect(spec.Audit.Scheduling.Tolerations).To(BeNil())
+	g.Expect(spec.Audit.Scheduling.TopologySpreadConstraints).To(BeNil())
+	g.Expect(spec.Webhook.Scheduling.NodeSelector).To(BeNil())
+	g.Expect(spec.Webhook.Scheduling.TopologySpreadConstraints).To(HaveLen(1))
+
+	// test differing pod annotations are kept for each component
+	g.Expect(spec.PodAnnotations).To(BeNil())
+	g.Expect(spec.Audit.PodAnnotations).To(Equal(map[string]string{"foo": "audit"}))
+	g.Expect(spec.Webhook.PodAnnotations).To(Equal(map[string]string{"foo": "webhook"}))
+	g.Expect(converted.Annotations).To(BeEmpty())
+
+	// test the settings v1alpha1 cannot represent are kept in the annotation
+	original.Spec.Webhook.Validating = &v1beta1.ValidatingWebhookConfig{}
+	converted = &Gatekeeper{}
+	g.Expect(converted.ConvertFrom(original)).To(Succeed())
+	g.Expect(converted.Annotations).To(HaveKey(V1beta1SpecAnnotation))
+	restored := &v1beta1.Gatekeeper{}
+	g.Expect(converted.ConvertTo(restored)).To(Succeed())
+	g.Expect(restored.Spec.Webhook.Validating).ToNot(BeNil())
+
+	// test the annotation is ignored once the v1alpha1 spec changes
+	converted.Spec.Webhook.PodAnnotations = map[string]string{"foo": "all"}
+	restored = &v1beta1.Gatekeeper{}
+	g.Expect(converted.ConvertTo(restored)).To(Succeed())
+	g.Expect(restored.Annotations).To(BeEmpty())
+	g.Expect(restored.Spec.Webhook.Validating).To(BeNil())
+	g.Expect(restored.Spec.Webhook.PodAnnotations).To(Equal(converted.Spec.Webhook.PodAnnotations))
+}
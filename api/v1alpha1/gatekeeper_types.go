@@ -20,6 +20,8 @@ import (
 	admregv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -30,6 +32,30 @@ import (
 
 // GatekeeperSpec defines the desired state of Gatekeeper
 type GatekeeperSpec struct {
+	// ManagementState controls whether the operator manages Gatekeeper.
+	// Managed, the default, deploys Gatekeeper and keeps its resources in
+	// the desired state. Unmanaged leaves the Gatekeeper resources untouched,
+	// e.g. while they are modified by hand, and only reports their status;
+	// deleting the Gatekeeper resource then leaves them in place. Removed
+	// removes Gatekeeper as when the Gatekeeper resource is deleted,
+	// following the uninstall policy, but keeps the Gatekeeper resource.
+	// +optional
+	ManagementState *ManagementState `json:"managementState,omitempty"`
+	// Version of Gatekeeper to deploy, selecting among the Gatekeeper
+	// manifests bundled with the operator, e.g. v3.3.0. Defaults to the
+	// latest version known to the operator at the time of its release.
+	// +optional
+	Version string `json:"version,omitempty"`
+	// Upgrade configures the health checks gating changes of the Gatekeeper
+	// version or image. The audit Deployment is upgraded first, then the
+	// webhook Deployment, and the last known-good version and image are
+	// restored if either fails its health checks. Once an upgrade is rolled
+	// back, the whole last known-good spec is deployed and later spec
+	// changes are held back, as reported by the upgrade conditions, until
+	// the last known-good version and image or another release are
+	// requested.
+	// +optional
+	Upgrade *UpgradeConfig `json:"upgrade,omitempty"`
 	// +optional
 	Image *ImageConfig `json:"image,omitempty"`
 	// +optional
@@ -38,16 +64,57 @@ type GatekeeperSpec struct {
 	ValidatingWebhook *WebhookMode `json:"validatingWebhook,omitempty"`
 	// +optional
 	MutatingWebhook *WebhookMode `json:"mutatingWebhook,omitempty"`
+	// MutationCRDsPolicy controls what happens to the Assign and
+	// AssignMetadata CRDs when the mutating webhook is disabled. Retain, the
+	// default, keeps the CRDs along with all of their resources. Delete
+	// removes the CRDs and with them all Assign and AssignMetadata resources.
+	// +optional
+	MutationCRDsPolicy *MutationCRDsPolicy `json:"mutationCRDsPolicy,omitempty"`
+	// Webhook configures the webhook. Its failurePolicy, namespaceSelector,
+	// objectSelector, rules, timeoutSeconds and matchPolicy apply to both the
+	// validating and the mutating webhooks.
 	// +optional
 	Webhook *WebhookConfig `json:"webhook,omitempty"`
+	// MutatingWebhookConfig configures the mutating webhook only. Fields set
+	// here replace the same fields of Webhook for the mutating webhook, which
+	// inherits every field left unset from Webhook.
+	// +optional
+	MutatingWebhookConfig *MutatingWebhookConfig `json:"mutatingWebhookConfig,omitempty"`
+	// NodeSelector applies to both components unless set in the scheduling
+	// of a component.
 	// +optional
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Affinity applies to both components unless set in the scheduling of a
+	// component. It is merged into the affinity of the Gatekeeper manifests
+	// as described in SchedulingConfig.
 	// +optional
 	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// Tolerations apply to both components unless set in the scheduling of a
+	// component.
 	// +optional
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// PodAnnotations apply to both components unless set for a component.
 	// +optional
 	PodAnnotations map[string]string `json:"podAnnotations,omitempty"`
+	// PodSecurityLevel is the Pod Security Standard enforced, audited and
+	// warned about in the Gatekeeper namespace through the Pod Security
+	// Admission labels, which are left untouched when unset. On OpenShift,
+	// Gatekeeper is granted the use of the privileged security context
+	// constraints for the privileged level, and of the restricted ones
+	// otherwise.
+	// +optional
+	PodSecurityLevel *PodSecurityLevel `json:"podSecurityLevel,omitempty"`
+	// Config is rendered into the Gatekeeper Config resource, named config,
+	// which configures data replication, namespace exclusions, validation
+	// traces and readiness stats. The Config resource is left untouched when
+	// this is not set so that it can be managed outside of the operator.
+	// +optional
+	Config *ConfigSpec `json:"config,omitempty"`
+	// UninstallPolicy controls which resources are removed when the
+	// Gatekeeper resource is deleted. RetainCRDs, the default, keeps the
+	// Gatekeeper CRDs along with any policies created from them.
+	// +optional
+	UninstallPolicy *UninstallPolicy `json:"uninstallPolicy,omitempty"`
 }
 
 type ImageConfig struct {
@@ -57,6 +124,44 @@ type ImageConfig struct {
 	Image *string `json:"image,omitempty"`
 	// +optional
 	ImagePullPolicy *corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+	// PullSecrets are the secrets used by the audit and the webhook pods to
+	// pull the Gatekeeper image, e.g. from a private registry.
+	// +optional
+	PullSecrets []corev1.LocalObjectReference `json:"pullSecrets,omitempty"`
+	// DigestPinning, when Enabled, pins the image of each component to the
+	// digest its tag resolved to on the nodes, as recorded in
+	// status.imageDigests, so that moving the tag does not change the image
+	// run by new pods. Disabled by default.
+	// +optional
+	DigestPinning *DigestPinningMode `json:"digestPinning,omitempty"`
+	// RegistryOverride replaces the registry host of every container image
+	// of the audit and the webhook, e.g. with the host of a mirror registry
+	// in a disconnected cluster. Images without a registry host are on
+	// Docker Hub.
+	// +kubebuilder:validation:Pattern:=`^[a-zA-Z0-9]([a-zA-Z0-9.-]*[a-zA-Z0-9])?(:[0-9]+)?$`
+	// +optional
+	RegistryOverride *string `json:"registryOverride,omitempty"`
+}
+
+// +kubebuilder:validation:Enum:=Enabled;Disabled
+type DigestPinningMode string
+
+const (
+	DigestPinningEnabled  DigestPinningMode = "Enabled"
+	DigestPinningDisabled DigestPinningMode = "Disabled"
+)
+
+type UpgradeConfig struct {
+	// HealthCheckTimeout is how long each upgraded component is given to
+	// become ready before the upgrade is rolled back. Defaults to 5m.
+	// +optional
+	HealthCheckTimeout *metav1.Duration `json:"healthCheckTimeout,omitempty"`
+	// MaxRestarts is the number of container restarts tolerated in the pods
+	// of an upgraded component before the upgrade is rolled back. Defaults
+	// to 3.
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	MaxRestarts *int32 `json:"maxRestarts,omitempty"`
 }
 
 type AuditConfig struct {
@@ -79,6 +184,53 @@ type AuditConfig struct {
 	EmitAuditEvents *EmitEventsMode `json:"emitAuditEvents,omitempty"`
 	// +optional
 	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// ExemptNamespaces lists namespaces excluded from audit in addition to
+	// the Gatekeeper and operator namespaces. They are rendered into the
+	// Gatekeeper Config resource and therefore require spec.config to be set.
+	// +optional
+	ExemptNamespaces []string `json:"exemptNamespaces,omitempty"`
+	// +optional
+	Scheduling *SchedulingConfig `json:"scheduling,omitempty"`
+	// PodAnnotations replace spec.podAnnotations for the audit pods.
+	// +optional
+	PodAnnotations map[string]string `json:"podAnnotations,omitempty"`
+	// Image overrides spec.image.image for the audit, e.g. to try a patched
+	// image on the audit only.
+	// +optional
+	Image *string `json:"image,omitempty"`
+	// PodDisruptionBudget renders a PodDisruptionBudget for the audit, which
+	// has none by default.
+	// +optional
+	PodDisruptionBudget *PodDisruptionBudgetConfig `json:"podDisruptionBudget,omitempty"`
+}
+
+// SchedulingConfig constrains the nodes the pods of a component run on. The
+// fields set here take precedence over the same fields of the spec, which
+// apply to both components.
+type SchedulingConfig struct {
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Affinity is merged into the affinity of the Gatekeeper manifests per
+	// kind of affinity, e.g. the default pod anti-affinity of the webhook is
+	// kept unless podAntiAffinity is set. Set podAntiAffinity to {} to
+	// remove it.
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+}
+
+// PodDisruptionBudgetConfig configures the PodDisruptionBudget of a
+// component. Exactly one of minAvailable and maxUnavailable must be set, and
+// the budget must allow at least one of the replicas of the component to be
+// evicted so that node drains can complete.
+type PodDisruptionBudgetConfig struct {
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
 }
 
 // +kubebuilder:validation:Enum:=Enabled;Disabled
@@ -89,6 +241,23 @@ const (
 	WebhookDisabled WebhookMode = "Disabled"
 )
 
+// +kubebuilder:validation:Enum:=privileged;baseline;restricted
+type PodSecurityLevel string
+
+const (
+	PodSecurityPrivileged PodSecurityLevel = "privileged"
+	PodSecurityBaseline   PodSecurityLevel = "baseline"
+	PodSecurityRestricted PodSecurityLevel = "restricted"
+)
+
+// +kubebuilder:validation:Enum:=Retain;Delete
+type MutationCRDsPolicy string
+
+const (
+	MutationCRDsRetain MutationCRDsPolicy = "Retain"
+	MutationCRDsDelete MutationCRDsPolicy = "Delete"
+)
+
 type WebhookConfig struct {
 	// +kubebuilder:validation:Minimum:=0
 	// +optional
@@ -102,7 +271,193 @@ type WebhookConfig struct {
 	// +optional
 	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
 	// +optional
+	ObjectSelector *metav1.LabelSelector `json:"objectSelector,omitempty"`
+	// Rules replace the rules of the Gatekeeper webhooks, which by default
+	// send CREATE and UPDATE requests for all resources.
+	// +kubebuilder:validation:MinItems:=1
+	// +optional
+	Rules []WebhookRule `json:"rules,omitempty"`
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=30
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+	// +kubebuilder:validation:Enum:=Exact;Equivalent
+	// +optional
+	MatchPolicy *admregv1.MatchPolicyType `json:"matchPolicy,omitempty"`
+	// +optional
 	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// +optional
+	Certificates *CertificatesConfig `json:"certificates,omitempty"`
+	// ExemptNamespaces lists namespaces the webhook ignores in addition to
+	// the Gatekeeper and operator namespaces, which are always exempt. The
+	// operator labels them with admission.gatekeeper.sh/ignore.
+	// +optional
+	ExemptNamespaces []string `json:"exemptNamespaces,omitempty"`
+	// +optional
+	Scheduling *SchedulingConfig `json:"scheduling,omitempty"`
+	// PodAnnotations replace spec.podAnnotations for the webhook pods.
+	// +optional
+	PodAnnotations map[string]string `json:"podAnnotations,omitempty"`
+	// Image overrides spec.image.image for the webhook.
+	// +optional
+	Image *string `json:"image,omitempty"`
+	// PodDisruptionBudget overrides the PodDisruptionBudget of the webhook,
+	// which keeps at least one pod available by default. The default budget
+	// is not rendered with a single replica, as it would block all evictions.
+	// +optional
+	PodDisruptionBudget *PodDisruptionBudgetConfig `json:"podDisruptionBudget,omitempty"`
+}
+
+type MutatingWebhookConfig struct {
+	// +optional
+	FailurePolicy *admregv1.FailurePolicyType `json:"failurePolicy,omitempty"`
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// +optional
+	ObjectSelector *metav1.LabelSelector `json:"objectSelector,omitempty"`
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=30
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+	// ReinvocationPolicy controls whether the mutating webhook is called
+	// again when other mutating webhooks modify the object after it.
+	// +kubebuilder:validation:Enum:=Never;IfNeeded
+	// +optional
+	ReinvocationPolicy *admregv1.ReinvocationPolicyType `json:"reinvocationPolicy,omitempty"`
+	// Rules replace the rules of the mutating webhook, which by default
+	// sends CREATE and UPDATE requests for all resources.
+	// +kubebuilder:validation:MinItems:=1
+	// +optional
+	Rules []WebhookRule `json:"rules,omitempty"`
+}
+
+type WebhookRule struct {
+	// +kubebuilder:validation:MinItems:=1
+	Operations []WebhookOperation `json:"operations"`
+	// APIGroups the rule applies to. All groups when not set.
+	// +optional
+	APIGroups []string `json:"apiGroups,omitempty"`
+	// APIVersions the rule applies to. All versions when not set.
+	// +optional
+	APIVersions []string `json:"apiVersions,omitempty"`
+	// Resources the rule applies to. All resources when not set.
+	// +optional
+	Resources []string `json:"resources,omitempty"`
+}
+
+// +kubebuilder:validation:Enum:=CREATE;UPDATE;DELETE;CONNECT;"*"
+type WebhookOperation string
+
+const (
+	WebhookOperationCreate  WebhookOperation = "CREATE"
+	WebhookOperationUpdate  WebhookOperation = "UPDATE"
+	WebhookOperationDelete  WebhookOperation = "DELETE"
+	WebhookOperationConnect WebhookOperation = "CONNECT"
+	WebhookOperationAll     WebhookOperation = "*"
+)
+
+type CertificatesConfig struct {
+	// Mode selects who manages the webhook serving certificate. With
+	// Gatekeeper, the default, Gatekeeper generates and rotates its own
+	// certificate. With Operator, the operator generates a CA and serving
+	// certificate, injects the CA into the webhook configurations and rotates
+	// them before they expire. With ServiceCA, only available on OpenShift,
+	// the OpenShift service CA issues the certificate and injects its CA.
+	// With CertManager, cert-manager issues the certificate from a
+	// self-signed Issuer and injects its CA. cert-manager must be installed.
+	// With Secret, the operator copies the certificate and CA from the Secret
+	// referenced by SecretRef and injects the CA.
+	// +optional
+	Mode *CertificatesMode `json:"mode,omitempty"`
+	// Validity of the serving certificates generated by the operator.
+	// Defaults to 8760h (1 year).
+	// +optional
+	Validity *metav1.Duration `json:"validity,omitempty"`
+	// RotateBefore is how long before expiry the operator rotates the serving
+	// certificate. It must be shorter than the validity. Defaults to 720h
+	// (30 days).
+	// +optional
+	RotateBefore *metav1.Duration `json:"rotateBefore,omitempty"`
+	// SecretRef references the Secret providing the webhook serving
+	// certificate when the mode is Secret. The Secret must contain the
+	// tls.crt, tls.key and ca.crt keys and the certificate must be valid for
+	// the webhook service. The namespace defaults to the Gatekeeper
+	// namespace. The operator must be granted get, list and watch on the
+	// Secrets of any other namespace.
+	// +optional
+	SecretRef *corev1.SecretReference `json:"secretRef,omitempty"`
+}
+
+// +kubebuilder:validation:Enum:=Gatekeeper;Operator;ServiceCA;CertManager;Secret
+type CertificatesMode string
+
+const (
+	CertificatesGatekeeper  CertificatesMode = "Gatekeeper"
+	CertificatesOperator    CertificatesMode = "Operator"
+	CertificatesServiceCA   CertificatesMode = "ServiceCA"
+	CertificatesCertManager CertificatesMode = "CertManager"
+	CertificatesSecret      CertificatesMode = "Secret"
+)
+
+// ConfigSpec is rendered into the spec of the Gatekeeper Config resource.
+type ConfigSpec struct {
+	// +optional
+	Sync *SyncConfig `json:"sync,omitempty"`
+	// Match excludes namespaces from Gatekeeper processes.
+	// +optional
+	Match []MatchEntry `json:"match,omitempty"`
+	// +optional
+	Validation *ValidationConfig `json:"validation,omitempty"`
+	// +optional
+	Readiness *ReadinessConfig `json:"readiness,omitempty"`
+}
+
+type SyncConfig struct {
+	// SyncOnly lists the resources replicated into OPA for use by policies.
+	// +optional
+	SyncOnly []GVK `json:"syncOnly,omitempty"`
+}
+
+type MatchEntry struct {
+	// +optional
+	ExcludedNamespaces []string `json:"excludedNamespaces,omitempty"`
+	// Processes the namespaces are excluded from, e.g. audit, webhook, sync
+	// or * for all of them.
+	// +optional
+	Processes []string `json:"processes,omitempty"`
+}
+
+type ValidationConfig struct {
+	// Traces lists the admission requests to trace.
+	// +optional
+	Traces []TraceEntry `json:"traces,omitempty"`
+}
+
+type TraceEntry struct {
+	// User whose requests are traced.
+	// +optional
+	User string `json:"user,omitempty"`
+	// Kind of the requests traced.
+	// +optional
+	Kind GVK `json:"kind,omitempty"`
+	// Dump the state of OPA along with the trace. Set to All to dump
+	// everything.
+	// +optional
+	Dump string `json:"dump,omitempty"`
+}
+
+type ReadinessConfig struct {
+	// +optional
+	StatsEnabled bool `json:"statsEnabled,omitempty"`
+}
+
+type GVK struct {
+	// +optional
+	Group string `json:"group,omitempty"`
+	// +optional
+	Version string `json:"version,omitempty"`
+	// +optional
+	Kind string `json:"kind,omitempty"`
 }
 
 // +kubebuilder:validation:Enum:=DEBUG;INFO;WARNING;ERROR
@@ -131,6 +486,23 @@ const (
 	EmitEventsDisabled EmitEventsMode = "Disabled"
 )
 
+// +kubebuilder:validation:Enum:=Managed;Unmanaged;Removed
+type ManagementState string
+
+const (
+	ManagementStateManaged   ManagementState = "Managed"
+	ManagementStateUnmanaged ManagementState = "Unmanaged"
+	ManagementStateRemoved   ManagementState = "Removed"
+)
+
+// +kubebuilder:validation:Enum:=RetainCRDs;DeleteAll
+type UninstallPolicy string
+
+const (
+	UninstallPolicyRetainCRDs UninstallPolicy = "RetainCRDs"
+	UninstallPolicyDeleteAll  UninstallPolicy = "DeleteAll"
+)
+
 // INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 // Important: Run "make" to regenerate code after modifying this file
 
@@ -140,6 +512,125 @@ type GatekeeperStatus struct {
 	ObservedGeneration int64             `json:"observedGeneration"`
 	AuditConditions    []StatusCondition `json:"auditConditions"`
 	WebhookConditions  []StatusCondition `json:"webhookConditions"`
+	// +optional
+	Certificates *CertificatesStatus `json:"certificates,omitempty"`
+	// Conflicts lists the fields of Gatekeeper resources that were managed
+	// by another field manager and taken over by the operator during the
+	// last reconciliation.
+	// +optional
+	Conflicts []ApplyConflict `json:"conflicts,omitempty"`
+	// APIVersions lists the versions of the Kubernetes APIs used to deploy
+	// Gatekeeper, as discovered from the cluster.
+	// +optional
+	APIVersions []string `json:"apiVersions,omitempty"`
+	// Version of the Gatekeeper manifests last deployed.
+	// +optional
+	Version string `json:"version,omitempty"`
+	// LastKnownGood is the last Gatekeeper release deployed with both the
+	// audit and the webhook ready, restored along with the spec it was
+	// deployed with when an upgrade fails.
+	// +optional
+	LastKnownGood *GatekeeperRelease `json:"lastKnownGood,omitempty"`
+	// Upgrade describes the last upgrade from the last known-good release.
+	// +optional
+	Upgrade *UpgradeStatus `json:"upgrade,omitempty"`
+	// +optional
+	UpgradeConditions []StatusCondition `json:"upgradeConditions,omitempty"`
+	// DegradedConditions report the configuration the operator does not
+	// apply, e.g. a PodDisruptionBudget that would block every eviction.
+	// +optional
+	DegradedConditions []StatusCondition `json:"degradedConditions,omitempty"`
+	// ImageDigests records the digests the images of the components resolved
+	// to when spec.image.digestPinning is Enabled.
+	// +listType=map
+	// +listMapKey=image
+	// +optional
+	ImageDigests []ImageDigest `json:"imageDigests,omitempty"`
+	// Images are the images last rendered for the components, after the
+	// overrides of spec.image are applied.
+	// +optional
+	Images *ComponentImages `json:"images,omitempty"`
+}
+
+// ComponentImages are the images of the Gatekeeper components.
+type ComponentImages struct {
+	// +optional
+	Audit string `json:"audit,omitempty"`
+	// +optional
+	Webhook string `json:"webhook,omitempty"`
+}
+
+// ImageDigest records the digest an image reference resolved to.
+type ImageDigest struct {
+	// Image reference, e.g. with a tag.
+	Image string `json:"image"`
+	// Digest of the image, e.g. sha256:<hex>.
+	Digest string `json:"digest"`
+}
+
+// GatekeeperRelease identifies the Gatekeeper manifests and image deployed.
+type GatekeeperRelease struct {
+	// Version of the Gatekeeper manifests.
+	Version string `json:"version"`
+	// Image of the Gatekeeper containers. The image of the manifests is used
+	// when not set.
+	// +optional
+	Image string `json:"image,omitempty"`
+	// AuditImage overrides the image for the audit.
+	// +optional
+	AuditImage string `json:"auditImage,omitempty"`
+	// WebhookImage overrides the image for the webhook.
+	// +optional
+	WebhookImage string `json:"webhookImage,omitempty"`
+	// Spec is the v1alpha1 Gatekeeper spec deployed with the release. It is
+	// only recorded for the last known-good release.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Spec *runtime.RawExtension `json:"spec,omitempty"`
+}
+
+// UpgradeStatus describes the upgrade of Gatekeeper to a new release.
+type UpgradeStatus struct {
+	// Release being upgraded to.
+	Release GatekeeperRelease `json:"release"`
+	// Phase of the upgrade.
+	Phase UpgradePhase `json:"phase"`
+	// StartTime is when the current phase started.
+	StartTime metav1.Time `json:"startTime"`
+}
+
+// +kubebuilder:validation:Enum:=UpgradingAudit;UpgradingWebhook;Succeeded;RolledBack
+type UpgradePhase string
+
+const (
+	UpgradePhaseAudit      UpgradePhase = "UpgradingAudit"
+	UpgradePhaseWebhook    UpgradePhase = "UpgradingWebhook"
+	UpgradePhaseSucceeded  UpgradePhase = "Succeeded"
+	UpgradePhaseRolledBack UpgradePhase = "RolledBack"
+)
+
+// ApplyConflict describes a conflict encountered when applying a Gatekeeper
+// resource.
+type ApplyConflict struct {
+	// Kind of the conflicting resource.
+	Kind string `json:"kind"`
+	// Namespace of the conflicting resource.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Name of the conflicting resource.
+	Name string `json:"name"`
+	// Message describing the conflicting fields and their managers.
+	Message string `json:"message"`
+}
+
+// CertificatesStatus describes the webhook serving certificate.
+type CertificatesStatus struct {
+	// Mode in which the webhook serving certificate is managed.
+	Mode CertificatesMode `json:"mode"`
+	// NotAfter is the expiry time of the webhook serving certificate when it
+	// is not managed by Gatekeeper.
+	// +optional
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
 }
 
 // StatusCondition describes the current state of a component.
@@ -162,19 +653,20 @@ type StatusCondition struct {
 	Message string `json:"message,omitempty"`
 }
 
-// +kubebuilder:validation:Enum:=Ready;Not Ready
+// +kubebuilder:validation:Enum:=Ready;Not Ready;Degraded
 type StatusConditionType string
 
 const (
 	StatusReady    StatusConditionType = "Ready"
 	StatusNotReady StatusConditionType = "Not Ready"
+	StatusDegraded StatusConditionType = "Degraded"
 )
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:path=gatekeepers,scope=Cluster
-//// +kubebuilder:printcolumn:name="Audit Status",type=string,JSONPath=`.status.auditConditions[0].type`,description="The status of the Gatekeeper Audit"
-//// +kubebuilder:printcolumn:name="Webhook Status",type=string,JSONPath=`.status.webhookConditions[0].type`,description="The status of the Gatekeeper Webhook"
+// +kubebuilder:printcolumn:name="Audit Status",type=string,JSONPath=`.status.auditConditions[0].type`,description="The status of the Gatekeeper Audit"
+// +kubebuilder:printcolumn:name="Webhook Status",type=string,JSONPath=`.status.webhookConditions[0].type`,description="The status of the Gatekeeper Webhook"
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // Gatekeeper is the Schema for the gatekeepers API
@@ -0,0 +1,279 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"time"
+
+	admregv1 "k8s.io/api/admissionregistration/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+const (
+	// GatekeeperName is the name of the only Gatekeeper resource managed by
+	// the operator.
+	GatekeeperName = "gatekeeper"
+
+	DefaultHealthCheckTimeout = 5 * time.Minute
+	DefaultMaxRestarts        = 3
+
+	// The replicas of the Gatekeeper manifests, used when the spec does not
+	// set them.
+	DefaultAuditReplicas   = 1
+	DefaultWebhookReplicas = 3
+)
+
+// log is for logging in this package.
+var gatekeeperlog = logf.Log.WithName("gatekeeper-resource")
+
+func (r *Gatekeeper) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-operator-gatekeeper-sh-v1alpha1-gatekeeper,mutating=true,failurePolicy=fail,groups=operator.gatekeeper.sh,resources=gatekeepers,verbs=create;update,versions=v1alpha1,name=mgatekeeper.kb.io,sideEffects=None
+
+var _ webhook.Defaulter = &Gatekeeper{}
+
+// Default implements webhook.Defaulter so that the effective configuration
+// is stored in the Gatekeeper resource. The version and image are not
+// defaulted so that they follow the operator when it is upgraded, nor are
+// the fields defaulted by the Gatekeeper manifests, e.g. the replicas.
+func (r *Gatekeeper) Default() {
+	gatekeeperlog.Info("default", "name", r.Name)
+
+	spec := &r.Spec
+	if spec.ManagementState == nil {
+		state := ManagementStateManaged
+		spec.ManagementState = &state
+	}
+	if spec.Upgrade == nil {
+		spec.Upgrade = &UpgradeConfig{}
+	}
+	if spec.Upgrade.HealthCheckTimeout == nil {
+		spec.Upgrade.HealthCheckTimeout = &metav1.Duration{Duration: DefaultHealthCheckTimeout}
+	}
+	if spec.Upgrade.MaxRestarts == nil {
+		maxRestarts := int32(DefaultMaxRestarts)
+		spec.Upgrade.MaxRestarts = &maxRestarts
+	}
+	if spec.ValidatingWebhook == nil {
+		mode := WebhookEnabled
+		spec.ValidatingWebhook = &mode
+	}
+	if spec.MutatingWebhook == nil {
+		mode := WebhookDisabled
+		spec.MutatingWebhook = &mode
+	}
+	if spec.MutationCRDsPolicy == nil {
+		policy := MutationCRDsRetain
+		spec.MutationCRDsPolicy = &policy
+	}
+	if spec.UninstallPolicy == nil {
+		policy := UninstallPolicyRetainCRDs
+		spec.UninstallPolicy = &policy
+	}
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-operator-gatekeeper-sh-v1alpha1-gatekeeper,mutating=false,failurePolicy=fail,groups=operator.gatekeeper.sh,resources=gatekeepers,versions=v1alpha1,name=vgatekeeper.kb.io,sideEffects=None
+
+var _ webhook.Validator = &Gatekeeper{}
+
+// ValidateCreate implements webhook.Validator.
+func (r *Gatekeeper) ValidateCreate() error {
+	gatekeeperlog.Info("validate create", "name", r.Name)
+
+	return r.validate()
+}
+
+// ValidateUpdate implements webhook.Validator.
+func (r *Gatekeeper) ValidateUpdate(old runtime.Object) error {
+	gatekeeperlog.Info("validate update", "name", r.Name)
+
+	return r.validate()
+}
+
+// ValidateDelete implements webhook.Validator.
+func (r *Gatekeeper) ValidateDelete() error {
+	return nil
+}
+
+func (r *Gatekeeper) validate() error {
+	var allErrs field.ErrorList
+	if r.Name != GatekeeperName {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("metadata", "name"), r.Name,
+			fmt.Sprintf("must be %s, only one Gatekeeper resource is supported", GatekeeperName)))
+	}
+	allErrs = append(allErrs, validateGatekeeperSpec(&r.Spec, field.NewPath("spec"))...)
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("Gatekeeper").GroupKind(), r.Name, allErrs)
+}
+
+func validateGatekeeperSpec(spec *GatekeeperSpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	validatingEnabled := spec.ValidatingWebhook == nil || *spec.ValidatingWebhook == WebhookEnabled
+	mutatingEnabled := spec.MutatingWebhook != nil && *spec.MutatingWebhook == WebhookEnabled
+
+	if mutatingEnabled && !validatingEnabled {
+		allErrs = append(allErrs, field.Invalid(path.Child("mutatingWebhook"), *spec.MutatingWebhook,
+			"must be Disabled when validatingWebhook is Disabled"))
+	}
+
+	if spec.Webhook != nil && spec.Webhook.Replicas != nil && *spec.Webhook.Replicas == 0 {
+		replicasPath := path.Child("webhook", "replicas")
+		if validatingEnabled && isFailurePolicyFail(spec.Webhook.FailurePolicy) {
+			allErrs = append(allErrs, field.Invalid(replicasPath, 0,
+				"must be greater than 0 when webhook.failurePolicy is Fail, "+
+					"as no admission request could be served"))
+		}
+		if mutatingEnabled && isFailurePolicyFail(GetMutatingWebhookConfig(spec).FailurePolicy) {
+			allErrs = append(allErrs, field.Invalid(replicasPath, 0,
+				"must be greater than 0 when the mutating webhook is enabled with failurePolicy Fail, "+
+					"as no admission request could be served"))
+		}
+	}
+
+	if spec.Audit != nil && spec.Audit.AuditChunkSize != nil && *spec.Audit.AuditChunkSize == 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("audit", "auditChunkSize"), 0,
+			"must be greater than 0, unset it to use the Gatekeeper default"))
+	}
+
+	if spec.Audit != nil && len(spec.Audit.ExemptNamespaces) > 0 && spec.Config == nil {
+		allErrs = append(allErrs, field.Forbidden(path.Child("audit", "exemptNamespaces"),
+			"requires spec.config, as the Config resource is otherwise managed outside of the operator"))
+	}
+
+	if spec.Audit != nil {
+		allErrs = append(allErrs, ValidatePodDisruptionBudget(spec.Audit.PodDisruptionBudget,
+			GetAuditReplicas(spec), path.Child("audit", "podDisruptionBudget"))...)
+	}
+	if spec.Webhook != nil {
+		allErrs = append(allErrs, ValidatePodDisruptionBudget(spec.Webhook.PodDisruptionBudget,
+			GetWebhookReplicas(spec), path.Child("webhook", "podDisruptionBudget"))...)
+	}
+
+	if spec.Image != nil {
+		for i, secret := range spec.Image.PullSecrets {
+			if secret.Name == "" {
+				allErrs = append(allErrs, field.Required(path.Child("image", "pullSecrets").Index(i).Child("name"), ""))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// ValidatePodDisruptionBudget validates that the given PodDisruptionBudget
+// sets exactly one of minAvailable and maxUnavailable, and allows at least
+// one of the given replicas to be evicted, as a budget blocking all evictions
+// prevents node drains from completing.
+func ValidatePodDisruptionBudget(pdb *PodDisruptionBudgetConfig, replicas int32, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if pdb == nil {
+		return allErrs
+	}
+	switch {
+	case pdb.MinAvailable == nil && pdb.MaxUnavailable == nil:
+		return append(allErrs, field.Required(path, "one of minAvailable or maxUnavailable must be set"))
+	case pdb.MinAvailable != nil && pdb.MaxUnavailable != nil:
+		return append(allErrs, field.Forbidden(path.Child("maxUnavailable"), "must not be set with minAvailable"))
+	}
+
+	if pdb.MinAvailable != nil {
+		minAvailablePath := path.Child("minAvailable")
+		// Percentages are rounded up as by the disruption controller.
+		minAvailable, err := intstr.GetValueFromIntOrPercent(pdb.MinAvailable, int(replicas), true)
+		if err != nil || minAvailable < 0 {
+			allErrs = append(allErrs, field.Invalid(minAvailablePath, pdb.MinAvailable.String(),
+				"must be a non-negative integer or percentage"))
+		} else if replicas > 0 && minAvailable >= int(replicas) {
+			allErrs = append(allErrs, field.Invalid(minAvailablePath, pdb.MinAvailable.String(),
+				fmt.Sprintf("must be less than the %d replicas, as no pod could be evicted", replicas)))
+		}
+	}
+	if pdb.MaxUnavailable != nil {
+		maxUnavailablePath := path.Child("maxUnavailable")
+		maxUnavailable, err := intstr.GetValueFromIntOrPercent(pdb.MaxUnavailable, int(replicas), true)
+		if err != nil || maxUnavailable < 0 {
+			allErrs = append(allErrs, field.Invalid(maxUnavailablePath, pdb.MaxUnavailable.String(),
+				"must be a non-negative integer or percentage"))
+		} else if replicas > 0 && maxUnavailable == 0 {
+			allErrs = append(allErrs, field.Invalid(maxUnavailablePath, pdb.MaxUnavailable.String(),
+				"must be greater than 0, as no pod could be evicted"))
+		}
+	}
+	return allErrs
+}
+
+// GetAuditReplicas returns the replicas of the audit.
+func GetAuditReplicas(spec *GatekeeperSpec) int32 {
+	if spec.Audit != nil && spec.Audit.Replicas != nil {
+		return *spec.Audit.Replicas
+	}
+	return DefaultAuditReplicas
+}
+
+// GetWebhookReplicas returns the replicas of the webhook.
+func GetWebhookReplicas(spec *GatekeeperSpec) int32 {
+	if spec.Webhook != nil && spec.Webhook.Replicas != nil {
+		return *spec.Webhook.Replicas
+	}
+	return DefaultWebhookReplicas
+}
+
+// GetMutatingWebhookConfig returns the configuration of the mutating webhook.
+// Fields not set in mutatingWebhookConfig are inherited from webhook, which
+// configures both the validating and the mutating webhooks.
+func GetMutatingWebhookConfig(spec *GatekeeperSpec) MutatingWebhookConfig {
+	config := MutatingWebhookConfig{}
+	if spec.MutatingWebhookConfig != nil {
+		spec.MutatingWebhookConfig.DeepCopyInto(&config)
+	}
+	webhook := spec.Webhook
+	if webhook == nil {
+		return config
+	}
+	if config.FailurePolicy == nil {
+		config.FailurePolicy = webhook.FailurePolicy
+	}
+	if config.NamespaceSelector == nil {
+		config.NamespaceSelector = webhook.NamespaceSelector.DeepCopy()
+	}
+	if config.ObjectSelector == nil {
+		config.ObjectSelector = webhook.ObjectSelector.DeepCopy()
+	}
+	if config.TimeoutSeconds == nil {
+		config.TimeoutSeconds = webhook.TimeoutSeconds
+	}
+	if len(config.Rules) == 0 {
+		config.Rules = webhook.Rules
+	}
+	return config
+}
+
+func isFailurePolicyFail(policy *admregv1.FailurePolicyType) bool {
+	return policy != nil && *policy == admregv1.Fail
+}
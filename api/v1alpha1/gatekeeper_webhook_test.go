@@ -0,0 +1,202 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	admregv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func newGatekeeper() *Gatekeeper {
+	gatekeeper := &Gatekeeper{}
+	gatekeeper.SetName(GatekeeperName)
+	return gatekeeper
+}
+
+func TestDefault(t *testing.T) {
+	g := NewWithT(t)
+	gatekeeper := newGatekeeper()
+	gatekeeper.Default()
+
+	spec := gatekeeper.Spec
+	g.Expect(*spec.ManagementState).To(Equal(ManagementStateManaged))
+	g.Expect(spec.Upgrade.HealthCheckTimeout.Duration).To(Equal(DefaultHealthCheckTimeout))
+	g.Expect(*spec.Upgrade.MaxRestarts).To(Equal(int32(DefaultMaxRestarts)))
+	g.Expect(*spec.ValidatingWebhook).To(Equal(WebhookEnabled))
+	g.Expect(*spec.MutatingWebhook).To(Equal(WebhookDisabled))
+	g.Expect(*spec.MutationCRDsPolicy).To(Equal(MutationCRDsRetain))
+	g.Expect(spec.PodSecurityLevel).To(BeNil())
+	g.Expect(*spec.UninstallPolicy).To(Equal(UninstallPolicyRetainCRDs))
+	g.Expect(spec.Version).To(BeEmpty())
+	g.Expect(spec.Image).To(BeNil())
+	g.Expect(spec.Webhook).To(BeNil())
+	g.Expect(gatekeeper.ValidateCreate()).To(Succeed())
+
+	// test set fields are kept
+	mode := WebhookEnabled
+	gatekeeper = newGatekeeper()
+	gatekeeper.Spec.MutatingWebhook = &mode
+	gatekeeper.Default()
+	g.Expect(*gatekeeper.Spec.MutatingWebhook).To(Equal(WebhookEnabled))
+}
+
+func TestValidate(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(newGatekeeper().ValidateCreate()).To(Succeed())
+	g.Expect(newGatekeeper().ValidateUpdate(newGatekeeper())).To(Succeed())
+
+	// test name
+	gatekeeper := newGatekeeper()
+	gatekeeper.SetName("other")
+	err := gatekeeper.ValidateCreate()
+	g.Expect(apierrors.IsInvalid(err)).To(BeTrue())
+	g.Expect(err.Error()).To(ContainSubstring("metadata.name"))
+
+	// test mutation without validation
+	enabled, disabled := WebhookEnabled, WebhookDisabled
+	gatekeeper = newGatekeeper()
+	gatekeeper.Spec.ValidatingWebhook = &disabled
+	gatekeeper.Spec.MutatingWebhook = &enabled
+	err = gatekeeper.ValidateCreate()
+	g.Expect(apierrors.IsInvalid(err)).To(BeTrue())
+	g.Expect(err.Error()).To(ContainSubstring("spec.mutatingWebhook"))
+
+	// test no webhook replicas
+	replicas := int32(0)
+	fail, ignore := admregv1.Fail, admregv1.Ignore
+	gatekeeper = newGatekeeper()
+	gatekeeper.Spec.Webhook = &WebhookConfig{Replicas: &replicas}
+	g.Expect(gatekeeper.ValidateCreate()).To(Succeed())
+	gatekeeper.Spec.Webhook.FailurePolicy = &fail
+	err = gatekeeper.ValidateUpdate(newGatekeeper())
+	g.Expect(apierrors.IsInvalid(err)).To(BeTrue())
+	g.Expect(err.Error()).To(ContainSubstring("spec.webhook.replicas"))
+	gatekeeper.Spec.ValidatingWebhook = &disabled
+	g.Expect(gatekeeper.ValidateCreate()).To(Succeed())
+
+	// test no webhook replicas with mutation
+	gatekeeper.Spec.ValidatingWebhook = &enabled
+	gatekeeper.Spec.Webhook.FailurePolicy = &ignore
+	gatekeeper.Spec.MutatingWebhook = &enabled
+	gatekeeper.Spec.MutatingWebhookConfig = &MutatingWebhookConfig{FailurePolicy: &fail}
+	err = gatekeeper.ValidateCreate()
+	g.Expect(apierrors.IsInvalid(err)).To(BeTrue())
+	g.Expect(err.Error()).To(ContainSubstring("mutating webhook"))
+
+	// test audit chunk size
+	chunkSize := uint64(0)
+	gatekeeper = newGatekeeper()
+	gatekeeper.Spec.Audit = &AuditConfig{AuditChunkSize: &chunkSize}
+	err = gatekeeper.ValidateCreate()
+	g.Expect(apierrors.IsInvalid(err)).To(BeTrue())
+	g.Expect(err.Error()).To(ContainSubstring("spec.audit.auditChunkSize"))
+
+	// test audit exempt namespaces require a Config managed by the operator
+	gatekeeper = newGatekeeper()
+	gatekeeper.Spec.Audit = &AuditConfig{ExemptNamespaces: []string{"kube-system"}}
+	err = gatekeeper.ValidateCreate()
+	g.Expect(apierrors.IsInvalid(err)).To(BeTrue())
+	g.Expect(err.Error()).To(ContainSubstring("spec.audit.exemptNamespaces"))
+	gatekeeper.Spec.Config = &ConfigSpec{}
+	g.Expect(gatekeeper.ValidateCreate()).To(Succeed())
+
+	// test image pull secret names
+	gatekeeper = newGatekeeper()
+	gatekeeper.Spec.Image = &ImageConfig{PullSecrets: []corev1.LocalObjectReference{{Name: "registry"}, {}}}
+	err = gatekeeper.ValidateCreate()
+	g.Expect(apierrors.IsInvalid(err)).To(BeTrue())
+	g.Expect(err.Error()).To(ContainSubstring("spec.image.pullSecrets[1].name"))
+
+	// test the webhook PodDisruptionBudget allows evictions
+	one, half := intstr.FromInt(1), intstr.FromString("50%")
+	gatekeeper = newGatekeeper()
+	gatekeeper.Spec.Webhook = &WebhookConfig{PodDisruptionBudget: &PodDisruptionBudgetConfig{MinAvailable: &half}}
+	g.Expect(gatekeeper.ValidateCreate()).To(Succeed())
+	replicas = 1
+	gatekeeper.Spec.Webhook.Replicas = &replicas
+	err = gatekeeper.ValidateUpdate(newGatekeeper())
+	g.Expect(apierrors.IsInvalid(err)).To(BeTrue())
+	g.Expect(err.Error()).To(ContainSubstring("spec.webhook.podDisruptionBudget.minAvailable"))
+	gatekeeper.Spec.Webhook.PodDisruptionBudget = &PodDisruptionBudgetConfig{MaxUnavailable: &one}
+	g.Expect(gatekeeper.ValidateCreate()).To(Succeed())
+	gatekeeper.Spec.Webhook.PodDisruptionBudget.MinAvailable = &one
+	err = gatekeeper.ValidateCreate()
+	g.Expect(apierrors.IsInvalid(err)).To(BeTrue())
+	g.Expect(err.Error()).To(ContainSubstring("spec.webhook.podDisruptionBudget.maxUnavailable"))
+	gatekeeper.Spec.Webhook.PodDisruptionBudget = &PodDisruptionBudgetConfig{}
+	err = gatekeeper.ValidateCreate()
+	g.Expect(apierrors.IsInvalid(err)).To(BeTrue())
+	g.Expect(err.Error()).To(ContainSubstring("spec.webhook.podDisruptionBudget"))
+
+	// test the audit PodDisruptionBudget at the default replica
+	zero, invalid := intstr.FromInt(0), intstr.FromString("half")
+	gatekeeper = newGatekeeper()
+	gatekeeper.Spec.Audit = &AuditConfig{PodDisruptionBudget: &PodDisruptionBudgetConfig{MaxUnavailable: &zero}}
+	err = gatekeeper.ValidateCreate()
+	g.Expect(apierrors.IsInvalid(err)).To(BeTrue())
+	g.Expect(err.Error()).To(ContainSubstring("spec.audit.podDisruptionBudget.maxUnavailable"))
+	gatekeeper.Spec.Audit.PodDisruptionBudget = &PodDisruptionBudgetConfig{MinAvailable: &invalid}
+	err = gatekeeper.ValidateCreate()
+	g.Expect(apierrors.IsInvalid(err)).To(BeTrue())
+	g.Expect(err.Error()).To(ContainSubstring("must be a non-negative integer or percentage"))
+
+	g.Expect(gatekeeper.ValidateDelete()).To(Succeed())
+}
+
+func TestGetMutatingWebhookConfig(t *testing.T) {
+	g := NewWithT(t)
+	fail, ignore := admregv1.Fail, admregv1.Ignore
+	timeoutSeconds, mutationTimeoutSeconds := int32(10), int32(5)
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"gatekeeper": "true"}}
+	rules := []WebhookRule{{Operations: []WebhookOperation{WebhookOperationCreate}}}
+	spec := &GatekeeperSpec{}
+
+	// test default
+	g.Expect(GetMutatingWebhookConfig(spec)).To(Equal(MutatingWebhookConfig{}))
+
+	// test the webhook fields are inherited
+	spec.Webhook = &WebhookConfig{
+		FailurePolicy:     &fail,
+		NamespaceSelector: selector,
+		ObjectSelector:    selector,
+		TimeoutSeconds:    &timeoutSeconds,
+		Rules:             rules,
+	}
+	g.Expect(GetMutatingWebhookConfig(spec)).To(Equal(MutatingWebhookConfig{
+		FailurePolicy:     &fail,
+		NamespaceSelector: selector,
+		ObjectSelector:    selector,
+		TimeoutSeconds:    &timeoutSeconds,
+		Rules:             rules,
+	}))
+
+	// test the mutatingWebhookConfig fields take precedence
+	spec.MutatingWebhookConfig = &MutatingWebhookConfig{
+		FailurePolicy:  &ignore,
+		TimeoutSeconds: &mutationTimeoutSeconds,
+	}
+	config := GetMutatingWebhookConfig(spec)
+	g.Expect(config.FailurePolicy).To(Equal(&ignore))
+	g.Expect(config.TimeoutSeconds).To(Equal(&mutationTimeoutSeconds))
+	g.Expect(config.NamespaceSelector).To(Equal(selector))
+	g.Expect(config.Rules).To(Equal(rules))
+	g.Expect(spec.MutatingWebhookConfig.NamespaceSelector).To(BeNil())
+}
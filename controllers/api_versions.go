@@ -0,0 +1,303 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"github.com/pkg/errors"
+	admregv1 "k8s.io/api/admissionregistration/v1"
+	admregv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+
+	"github.com/gatekeeper/gatekeeper-operator/pkg/util"
+)
+
+// policyV1GroupVersion is policy/v1, which is not part of the vendored API.
+var policyV1GroupVersion = schema.GroupVersion{Group: policyv1beta1.GroupName, Version: "v1"}
+
+// APIVersions describes the API versions served by the cluster that the
+// Gatekeeper resources are deployed with. The zero value selects the v1beta1
+// APIs, including PodSecurityPolicy.
+type APIVersions struct {
+	// AdmissionRegistrationV1 is whether admissionregistration.k8s.io/v1 is
+	// served.
+	AdmissionRegistrationV1 bool
+	// APIExtensionsV1 is whether apiextensions.k8s.io/v1 is served.
+	APIExtensionsV1 bool
+	// PodSecurityPolicyUnserved is whether policy/v1beta1 PodSecurityPolicy
+	// is not served, e.g. as of Kubernetes 1.25.
+	PodSecurityPolicyUnserved bool
+	// PodDisruptionBudgetV1 is whether policy/v1 PodDisruptionBudget is
+	// served, as of Kubernetes 1.21.
+	PodDisruptionBudgetV1 bool
+}
+
+// DiscoverAPIVersions discovers the API versions served by the cluster.
+func DiscoverAPIVersions(cfg *rest.Config) (APIVersions, error) {
+	client, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return APIVersions{}, errors.Wrapf(err, "Unable to create discovery client")
+	}
+
+	apiVersions := APIVersions{}
+	apiVersions.AdmissionRegistrationV1, err = isResourceServed(client, admregv1.SchemeGroupVersion, "validatingwebhookconfigurations")
+	if err != nil {
+		return APIVersions{}, err
+	}
+	apiVersions.APIExtensionsV1, err = isResourceServed(client, apiextensionsv1.SchemeGroupVersion, "customresourcedefinitions")
+	if err != nil {
+		return APIVersions{}, err
+	}
+	podSecurityPolicyServed, err := isResourceServed(client, policyv1beta1.SchemeGroupVersion, "podsecuritypolicies")
+	if err != nil {
+		return APIVersions{}, err
+	}
+	apiVersions.PodSecurityPolicyUnserved = !podSecurityPolicyServed
+	apiVersions.PodDisruptionBudgetV1, err = isResourceServed(client, policyV1GroupVersion, "poddisruptionbudgets")
+	if err != nil {
+		return APIVersions{}, err
+	}
+	return apiVersions, nil
+}
+
+// isResourceServed returns whether the given resource of the given group
+// version is served by the cluster.
+func isResourceServed(client discovery.DiscoveryInterface, groupVersion schema.GroupVersion, resource string) (bool, error) {
+	resources, err := client.ServerResourcesForGroupVersion(groupVersion.String())
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Wrapf(err, "Unable to discover resources of %s", groupVersion)
+	}
+	for _, r := range resources.APIResources {
+		if r.Name == resource {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// convert converts the given object, which the Gatekeeper manifests declare
+// with the v1beta1 APIs, to the selected API versions.
+func (v APIVersions) convert(obj *unstructured.Unstructured) error {
+	switch obj.GetAPIVersion() {
+	case admregv1beta1.SchemeGroupVersion.String():
+		if v.AdmissionRegistrationV1 {
+			return convertWebhookConfigurationToV1(obj)
+		}
+	case apiextensionsv1beta1.SchemeGroupVersion.String():
+		if v.APIExtensionsV1 {
+			return convertCRDToV1(obj)
+		}
+	case policyv1beta1.SchemeGroupVersion.String():
+		// PodSecurityPolicy shares the policy group and has no v1 version.
+		if v.PodDisruptionBudgetV1 && obj.GetKind() == "PodDisruptionBudget" {
+			obj.SetAPIVersion(policyV1GroupVersion.String())
+		}
+	}
+	return nil
+}
+
+// convertWebhookConfigurationToV1 converts a v1beta1 webhook configuration to
+// v1, keeping the v1beta1 defaults of the fields whose defaults changed and
+// declaring the side effects required by v1.
+func convertWebhookConfigurationToV1(obj *unstructured.Unstructured) error {
+	webhooks, _, err := unstructured.NestedSlice(obj.Object, "webhooks")
+	if err != nil {
+		return errors.Wrapf(err, "Failed to retrieve webhooks of %s", obj.GetName())
+	}
+	for _, w := range webhooks {
+		webhook, ok := w.(map[string]interface{})
+		if !ok {
+			return errors.Errorf("Invalid webhook in %s", obj.GetName())
+		}
+		if _, ok := webhook["admissionReviewVersions"]; !ok {
+			webhook["admissionReviewVersions"] = []interface{}{admregv1beta1.SchemeGroupVersion.Version}
+		}
+		if _, ok := webhook["matchPolicy"]; !ok {
+			webhook["matchPolicy"] = string(admregv1beta1.Exact)
+		}
+		switch webhook["sideEffects"] {
+		case string(admregv1.SideEffectClassNone), string(admregv1.SideEffectClassNoneOnDryRun):
+		default:
+			webhook["sideEffects"] = string(admregv1.SideEffectClassNone)
+		}
+	}
+	if err := unstructured.SetNestedSlice(obj.Object, webhooks, "webhooks"); err != nil {
+		return errors.Wrapf(err, "Failed to set webhooks of %s", obj.GetName())
+	}
+	obj.SetAPIVersion(admregv1.SchemeGroupVersion.String())
+	return nil
+}
+
+// convertCRDToV1 converts a v1beta1 CustomResourceDefinition to v1, moving
+// the schema, subresources and printer columns shared by every version into
+// each version.
+func convertCRDToV1(obj *unstructured.Unstructured) error {
+	spec, _, err := unstructured.NestedMap(obj.Object, "spec")
+	if err != nil {
+		return errors.Wrapf(err, "Failed to retrieve spec of %s", obj.GetName())
+	}
+	versions, _, err := unstructured.NestedSlice(spec, "versions")
+	if err != nil {
+		return errors.Wrapf(err, "Failed to retrieve versions of %s", obj.GetName())
+	}
+	if len(versions) == 0 {
+		versions = []interface{}{map[string]interface{}{
+			"name":    spec["version"],
+			"served":  true,
+			"storage": true,
+		}}
+	}
+	for _, v := range versions {
+		version, ok := v.(map[string]interface{})
+		if !ok {
+			return errors.Errorf("Invalid version in %s", obj.GetName())
+		}
+		for v1beta1Field, v1Field := range map[string]string{"validation": "schema", "subresources": "subresources"} {
+			value, ok := spec[v1beta1Field]
+			if _, set := version[v1Field]; ok && !set {
+				version[v1Field] = runtime.DeepCopyJSONValue(value)
+			}
+		}
+		columns, ok := version["additionalPrinterColumns"]
+		if !ok {
+			columns, ok = spec["additionalPrinterColumns"]
+		}
+		if ok {
+			version["additionalPrinterColumns"] = convertPrinterColumnsToV1(columns)
+		}
+	}
+	for _, v := range versions {
+		schema, ok, _ := unstructured.NestedMap(v.(map[string]interface{}), "schema", "openAPIV3Schema")
+		if !ok {
+			continue
+		}
+		if _, ok := schema["type"]; !ok {
+			schema["type"] = "object"
+		}
+		preserveUnknownFields(schema, true)
+		if err := unstructured.SetNestedMap(v.(map[string]interface{}), schema, "schema", "openAPIV3Schema"); err != nil {
+			return errors.Wrapf(err, "Failed to set schema of %s", obj.GetName())
+		}
+	}
+	for _, field := range []string{"version", "validation", "subresources", "additionalPrinterColumns"} {
+		unstructured.RemoveNestedField(spec, field)
+	}
+	spec["versions"] = versions
+	spec["preserveUnknownFields"] = false
+	if err := unstructured.SetNestedMap(obj.Object, spec, "spec"); err != nil {
+		return errors.Wrapf(err, "Failed to set spec of %s", obj.GetName())
+	}
+	obj.SetAPIVersion(apiextensionsv1.SchemeGroupVersion.String())
+	return nil
+}
+
+// preserveUnknownFields marks the objects of the given schema that declare no
+// properties as preserving unknown fields, which v1beta1 did by not pruning
+// them. The metadata of the root object is left to the API server.
+func preserveUnknownFields(schema map[string]interface{}, root bool) {
+	_, hasProperties := schema["properties"]
+	_, hasAdditionalProperties := schema["additionalProperties"]
+	_, preserves := schema["x-kubernetes-preserve-unknown-fields"]
+	if schema["type"] == "object" && !hasProperties && !hasAdditionalProperties && !preserves {
+		schema["x-kubernetes-preserve-unknown-fields"] = true
+	}
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		for name, p := range properties {
+			if property, ok := p.(map[string]interface{}); ok && !(root && name == "metadata") {
+				preserveUnknownFields(property, false)
+			}
+		}
+	}
+	for _, field := range []string{"items", "additionalProperties"} {
+		if child, ok := schema[field].(map[string]interface{}); ok {
+			preserveUnknownFields(child, false)
+		}
+	}
+}
+
+// convertPrinterColumnsToV1 returns a copy of the given v1beta1 printer
+// columns, whose JSONPath field is named jsonPath in v1.
+func convertPrinterColumnsToV1(columns interface{}) interface{} {
+	columns = runtime.DeepCopyJSONValue(columns)
+	if columns, ok := columns.([]interface{}); ok {
+		for _, c := range columns {
+			if column, ok := c.(map[string]interface{}); ok {
+				if jsonPath, ok := column["JSONPath"]; ok {
+					column["jsonPath"] = jsonPath
+					unstructured.RemoveNestedField(column, "JSONPath")
+				}
+			}
+		}
+	}
+	return columns
+}
+
+// list returns the selected versions of the APIs that have several versions,
+// as reported in the Gatekeeper status.
+func (v APIVersions) list() []string {
+	apiVersions := []string{
+		admregv1beta1.SchemeGroupVersion.String(),
+		apiextensionsv1beta1.SchemeGroupVersion.String(),
+	}
+	if v.AdmissionRegistrationV1 {
+		apiVersions[0] = admregv1.SchemeGroupVersion.String()
+	}
+	if v.APIExtensionsV1 {
+		apiVersions[1] = apiextensionsv1.SchemeGroupVersion.String()
+	}
+	if !v.PodSecurityPolicyUnserved {
+		apiVersions = append(apiVersions, policyv1beta1.SchemeGroupVersion.String())
+	}
+	// PodDisruptionBudget shares policy/v1beta1 with PodSecurityPolicy,
+	// which is only listed once.
+	if v.PodDisruptionBudgetV1 {
+		apiVersions = append(apiVersions, policyV1GroupVersion.String())
+	} else if v.PodSecurityPolicyUnserved {
+		apiVersions = append(apiVersions, policyv1beta1.SchemeGroupVersion.String())
+	}
+	return apiVersions
+}
+
+// getServedAssets returns the given assets without those of APIs not served
+// by the cluster.
+func (r *GatekeeperReconciler) getServedAssets(assets []string) []string {
+	if r.APIVersions.PodSecurityPolicyUnserved {
+		return getSubsetOfAssets(assets, PodSecurityPolicyFile)
+	}
+	return assets
+}
+
+// getVersionedManifestObject returns the object of the given asset from the
+// manifests of the given Gatekeeper version, converted to the API versions
+// served by the cluster.
+func (r *GatekeeperReconciler) getVersionedManifestObject(version, asset string) (*unstructured.Unstructured, error) {
+	obj, err := util.GetVersionedManifestObject(version, asset)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.APIVersions.convert(obj); err != nil {
+		return nil, errors.Wrapf(err, "Unable to convert asset %s", asset)
+	}
+	return obj, nil
+}
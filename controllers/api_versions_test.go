@@ -0,0 +1,191 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	admregv1 "k8s.io/api/admissionregistration/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	operatorv1alpha1 "github.com/gatekeeper/gatekeeper-operator/api/v1alpha1"
+	"github.com/gatekeeper/gatekeeper-operator/pkg/util"
+)
+
+func TestConvert(t *testing.T) {
+	g := NewWithT(t)
+
+	// test default
+	apiVersions := APIVersions{}
+	for _, a := range orderedStaticAssets {
+		obj, err := util.GetManifestObject(a)
+		g.Expect(err).ToNot(HaveOccurred())
+		converted := obj.DeepCopy()
+		g.Expect(apiVersions.convert(converted)).To(Succeed())
+		g.Expect(converted).To(Equal(obj))
+	}
+	g.Expect(apiVersions.list()).To(Equal([]string{
+		"admissionregistration.k8s.io/v1beta1",
+		"apiextensions.k8s.io/v1beta1",
+		"policy/v1beta1",
+	}))
+
+	// test v1 APIs
+	apiVersions = APIVersions{
+		AdmissionRegistrationV1:   true,
+		APIExtensionsV1:           true,
+		PodSecurityPolicyUnserved: true,
+		PodDisruptionBudgetV1:     true,
+	}
+	g.Expect(apiVersions.list()).To(Equal([]string{
+		"admissionregistration.k8s.io/v1",
+		"apiextensions.k8s.io/v1",
+		"policy/v1",
+	}))
+
+	r := &GatekeeperReconciler{APIVersions: apiVersions}
+	for _, a := range r.getServedAssets(orderedStaticAssets) {
+		obj, err := r.getVersionedManifestObject(util.DefaultGatekeeperVersion, a)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(obj.GetAPIVersion()).ToNot(HaveSuffix("v1beta1"), a)
+	}
+	g.Expect(r.getServedAssets(orderedStaticAssets)).ToNot(ContainElement(PodSecurityPolicyFile))
+
+	// test PodSecurityPolicy is kept at v1beta1 along policy/v1
+	apiVersions = APIVersions{PodDisruptionBudgetV1: true}
+	obj, err := util.GetManifestObject(PodSecurityPolicyFile)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(apiVersions.convert(obj)).To(Succeed())
+	g.Expect(obj.GetAPIVersion()).To(Equal("policy/v1beta1"))
+	g.Expect(apiVersions.list()).To(Equal([]string{
+		"admissionregistration.k8s.io/v1beta1",
+		"apiextensions.k8s.io/v1beta1",
+		"policy/v1beta1",
+		"policy/v1",
+	}))
+
+	// test policy/v1beta1 is listed for PodDisruptionBudget without
+	// PodSecurityPolicy
+	apiVersions = APIVersions{PodSecurityPolicyUnserved: true}
+	g.Expect(apiVersions.list()).To(Equal([]string{
+		"admissionregistration.k8s.io/v1beta1",
+		"apiextensions.k8s.io/v1beta1",
+		"policy/v1beta1",
+	}))
+}
+
+func TestV1OwnedTypes(t *testing.T) {
+	g := NewWithT(t)
+	r := &GatekeeperReconciler{
+		APIVersions: APIVersions{
+			AdmissionRegistrationV1:   true,
+			APIExtensionsV1:           true,
+			PodSecurityPolicyUnserved: true,
+			PodDisruptionBudgetV1:     true,
+		},
+	}
+	ownedTypes, err := r.getOwnedTypes()
+	g.Expect(err).ToNot(HaveOccurred())
+
+	for _, o := range ownedTypes {
+		gvk := o.GetObjectKind().GroupVersionKind()
+		g.Expect(gvk.Version).ToNot(Equal("v1beta1"))
+		g.Expect(gvk.Kind).ToNot(Equal("PodSecurityPolicy"))
+	}
+}
+
+func TestV1WebhookConfigurationOverrides(t *testing.T) {
+	g := NewWithT(t)
+	r := &GatekeeperReconciler{
+		APIVersions: APIVersions{AdmissionRegistrationV1: true},
+	}
+	failurePolicy := admregv1.Fail
+	gatekeeper := &operatorv1alpha1.Gatekeeper{
+		Spec: operatorv1alpha1.GatekeeperSpec{
+			Webhook: &operatorv1alpha1.WebhookConfig{
+				FailurePolicy: &failurePolicy,
+			},
+		},
+	}
+
+	for _, asset := range []string{ValidatingWebhookConfiguration, MutatingWebhookConfiguration} {
+		obj, err := r.getVersionedManifestObject(util.DefaultGatekeeperVersion, asset)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(obj.GetAPIVersion()).To(Equal("admissionregistration.k8s.io/v1"))
+
+		err = crOverrides(gatekeeper, asset, obj, namespace, false)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		webhooks, found, err := unstructured.NestedSlice(obj.Object, "webhooks")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(found).To(BeTrue())
+		for _, w := range webhooks {
+			webhook := w.(map[string]interface{})
+			g.Expect(webhook).To(HaveKey("admissionReviewVersions"))
+			g.Expect(webhook).To(HaveKeyWithValue("sideEffects", "None"))
+			if webhook["name"] == ValidationGatekeeperWebhook || webhook["name"] == MutationGatekeeperWebhook {
+				g.Expect(webhook).To(HaveKeyWithValue("failurePolicy", string(admregv1.Fail)))
+			}
+			serviceNamespace, _, err := unstructured.NestedString(webhook, "clientConfig", "service", "namespace")
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(serviceNamespace).To(Equal(namespace))
+		}
+	}
+}
+
+func TestV1CRDConversion(t *testing.T) {
+	g := NewWithT(t)
+	r := &GatekeeperReconciler{
+		APIVersions: APIVersions{APIExtensionsV1: true},
+	}
+
+	for _, version := range []string{"v3.3.0", util.DefaultGatekeeperVersion} {
+		for _, asset := range orderedStaticAssets {
+			obj, err := r.getVersionedManifestObject(version, asset)
+			g.Expect(err).ToNot(HaveOccurred())
+			if obj.GetKind() != "CustomResourceDefinition" {
+				continue
+			}
+
+			crd := &apiextensionsv1.CustomResourceDefinition{}
+			err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, crd)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(crd.Spec.PreserveUnknownFields).To(BeFalse())
+			g.Expect(crd.Spec.Versions).ToNot(BeEmpty())
+			for _, v := range crd.Spec.Versions {
+				g.Expect(v.Schema).ToNot(BeNil(), asset)
+				schema := v.Schema.OpenAPIV3Schema
+				g.Expect(schema.Type).To(Equal("object"), asset)
+				g.Expect(schema.Properties["metadata"].XPreserveUnknownFields).To(BeNil(), asset)
+			}
+		}
+	}
+
+	// test objects without properties keep their unknown fields
+	obj, err := r.getVersionedManifestObject(util.DefaultGatekeeperVersion, ConfigCRDFile)
+	g.Expect(err).ToNot(HaveOccurred())
+	versions, _, err := unstructured.NestedSlice(obj.Object, "spec", "versions")
+	g.Expect(err).ToNot(HaveOccurred())
+	preserve, _, err := unstructured.NestedBool(versions[0].(map[string]interface{}),
+		"schema", "openAPIV3Schema", "properties", "status", "x-kubernetes-preserve-unknown-fields")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(preserve).To(BeTrue())
+	_, found, err := unstructured.NestedFieldNoCopy(obj.Object, "spec", "validation")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(found).To(BeFalse())
+}
@@ -0,0 +1,426 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	operatorv1alpha1 "github.com/gatekeeper/gatekeeper-operator/api/v1alpha1"
+	"github.com/gatekeeper/gatekeeper-operator/pkg/certs"
+	"github.com/gatekeeper/gatekeeper-operator/pkg/util"
+)
+
+const (
+	defaultCertificateValidity     = 365 * 24 * time.Hour
+	defaultCertificateRotateBefore = 30 * 24 * time.Hour
+	serviceCASecretNameAnnotation  = "service.beta.openshift.io/serving-cert-secret-name"
+	serviceCAInjectAnnotation      = "service.beta.openshift.io/inject-cabundle"
+	certManagerInjectAnnotation    = "cert-manager.io/inject-ca-from"
+)
+
+func getCertificatesMode(webhook *operatorv1alpha1.WebhookConfig) operatorv1alpha1.CertificatesMode {
+	if webhook == nil || webhook.Certificates == nil || webhook.Certificates.Mode == nil {
+		return operatorv1alpha1.CertificatesGatekeeper
+	}
+	return *webhook.Certificates.Mode
+}
+
+// getCertificateOptions returns the options for the certificates generated by
+// the operator for the webhook service of the given Gatekeeper version in the
+// given namespace.
+func getCertificateOptions(version string, webhook *operatorv1alpha1.WebhookConfig, namespace string) (certs.Options, error) {
+	service, err := util.GetVersionedManifestObject(version, ServiceFile)
+	if err != nil {
+		return certs.Options{}, err
+	}
+	opts := certs.Options{
+		DNSNames: []string{
+			fmt.Sprintf("%s.%s.svc", service.GetName(), namespace),
+			fmt.Sprintf("%s.%s.svc.cluster.local", service.GetName(), namespace),
+		},
+		Validity:     defaultCertificateValidity,
+		RotateBefore: defaultCertificateRotateBefore,
+	}
+	if webhook == nil || webhook.Certificates == nil {
+		return opts, nil
+	}
+	if webhook.Certificates.Validity != nil {
+		opts.Validity = webhook.Certificates.Validity.Duration
+	}
+	if webhook.Certificates.RotateBefore != nil {
+		opts.RotateBefore = webhook.Certificates.RotateBefore.Duration
+	}
+	return opts, nil
+}
+
+// reconcileCertificates returns the webhook certificates when they are not
+// managed by Gatekeeper. Certificates managed by the operator are reused from
+// the webhook server certificate secret until they are due for rotation, in
+// which case the returned rotation time is set. It returns nil when Gatekeeper
+// manages its own certificates.
+func (r *GatekeeperReconciler) reconcileCertificates(ctx context.Context, gatekeeper *operatorv1alpha1.Gatekeeper) (*certs.Certificates, time.Time, error) {
+	mode := getCertificatesMode(gatekeeper.Spec.Webhook)
+	if mode == operatorv1alpha1.CertificatesGatekeeper {
+		return nil, time.Time{}, nil
+	}
+	if mode == operatorv1alpha1.CertificatesServiceCA && !r.isOpenShift() {
+		return nil, time.Time{}, fmt.Errorf("Webhook certificates mode %s is only supported on OpenShift", mode)
+	}
+
+	if mode == operatorv1alpha1.CertificatesSecret {
+		certificates, err := r.getSecretCertificates(ctx, gatekeeper)
+		if isInvalidSecretError(err) {
+			// Keep the current certificates until the Secret is fixed, the
+			// error is reported in the webhook status. They are rendered
+			// again so that applying the webhook server certificate secret
+			// and the webhook configurations does not remove them.
+			r.Log.Info("Unable to use the webhook certificates secret", "reason", err.Error())
+			current, _, err := r.getCurrentCertificates(ctx, gatekeeper)
+			return current, time.Time{}, err
+		} else if err != nil {
+			return nil, time.Time{}, err
+		}
+		// Reconcile again when the certificate expires so that it is
+		// reported in the webhook status.
+		return certificates, certificates.NotAfter, nil
+	}
+
+	current, secret, err := r.getCurrentCertificates(ctx, gatekeeper)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	if mode == operatorv1alpha1.CertificatesServiceCA || mode == operatorv1alpha1.CertificatesCertManager {
+		// Neither the service CA nor cert-manager should inherit the
+		// certificates previously generated by the operator, so remove the
+		// secret holding them.
+		if secret != nil && metav1.IsControlledBy(secret, gatekeeper) {
+			if _, err := r.deleteAssets(ctx, gatekeeper, []string{ServerCertFile}); err != nil {
+				return nil, time.Time{}, err
+			}
+			return nil, time.Time{}, nil
+		}
+		return current, time.Time{}, nil
+	}
+
+	opts, err := getCertificateOptions(getManifestVersion(gatekeeper, ServiceFile), gatekeeper.Spec.Webhook, r.Namespace)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	certificates, err := certs.Ensure(current, opts, time.Now())
+	if err != nil {
+		return nil, time.Time{}, errors.Wrapf(err, "Unable to generate webhook certificates")
+	}
+	return certificates, certificates.RotateAt(opts), nil
+}
+
+// getCurrentCertificates returns the certificates of the webhook server
+// certificate secret in the cluster along with the secret. The certificates
+// are nil when the secret does not exist or holds no serving certificate.
+func (r *GatekeeperReconciler) getCurrentCertificates(ctx context.Context, gatekeeper *operatorv1alpha1.Gatekeeper) (*certs.Certificates, *unstructured.Unstructured, error) {
+	secret, err := r.getClusterObject(ctx, gatekeeper, ServerCertFile)
+	if err != nil || secret == nil {
+		return nil, nil, err
+	}
+	data, err := getSecretData(secret)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(data[certs.CertName]) == 0 {
+		return nil, secret, nil
+	}
+	return certs.FromSecretData(data), secret, nil
+}
+
+// invalidSecretError reports that the Secret referenced by the webhook
+// certificates configuration cannot be used to serve the webhook.
+type invalidSecretError struct {
+	error
+}
+
+func isInvalidSecretError(err error) bool {
+	_, ok := err.(*invalidSecretError)
+	return ok
+}
+
+// getCertificatesSecretRef returns the name of the Secret providing the
+// webhook certificates, defaulting its namespace to the given one.
+func getCertificatesSecretRef(webhook *operatorv1alpha1.WebhookConfig, namespace string) (types.NamespacedName, bool) {
+	if webhook == nil || webhook.Certificates == nil || webhook.Certificates.SecretRef == nil {
+		return types.NamespacedName{}, false
+	}
+	ref := types.NamespacedName{
+		Namespace: webhook.Certificates.SecretRef.Namespace,
+		Name:      webhook.Certificates.SecretRef.Name,
+	}
+	if ref.Namespace == "" {
+		ref.Namespace = namespace
+	}
+	return ref, true
+}
+
+// getSecretCertificates retrieves the webhook certificates from the Secret
+// referenced by the webhook certificates configuration and verifies that they
+// can serve the webhook service. An invalidSecretError is returned when they
+// cannot be used.
+func (r *GatekeeperReconciler) getSecretCertificates(ctx context.Context, gatekeeper *operatorv1alpha1.Gatekeeper) (*certs.Certificates, error) {
+	webhook := gatekeeper.Spec.Webhook
+	ref, ok := getCertificatesSecretRef(webhook, r.Namespace)
+	if !ok {
+		return nil, &invalidSecretError{fmt.Errorf("Webhook certificates mode %s requires a secretRef",
+			operatorv1alpha1.CertificatesSecret)}
+	}
+
+	if err := r.watchSecrets(ref.Namespace); err != nil {
+		return nil, err
+	}
+	secret := &unstructured.Unstructured{}
+	secret.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind(util.SecretKind))
+	if err := r.uncachedReader().Get(ctx, ref, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, &invalidSecretError{fmt.Errorf("Secret %s not found", ref)}
+		}
+		return nil, errors.Wrapf(err, "Unable to get secret %s", ref)
+	}
+	data, err := getSecretData(secret)
+	if err != nil {
+		return nil, &invalidSecretError{err}
+	}
+	certificates := &certs.Certificates{
+		CACert: data[certs.CACertName],
+		Cert:   data[certs.CertName],
+		Key:    data[certs.KeyName],
+	}
+
+	opts, err := getCertificateOptions(getManifestVersion(gatekeeper, ServiceFile), webhook, r.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	// The API server reaches the webhook through the service DNS name, the
+	// first of the DNS names.
+	cert, err := certificates.Verify(opts.DNSNames[:1], time.Now())
+	if err != nil {
+		return nil, &invalidSecretError{errors.Wrapf(err, "Secret %s", ref)}
+	}
+	certificates.NotAfter = cert.NotAfter
+	return certificates, nil
+}
+
+// watchSecrets watches the Secrets of the given namespace: the webhook server
+// certificate deployed by the operator, and the Secret providing the webhook
+// certificates, whose changes are copied to it. Only the Gatekeeper namespace
+// and the namespace of the referenced Secret are watched, so that the
+// operator does not cache every Secret of the cluster.
+func (r *GatekeeperReconciler) watchSecrets(namespace string) error {
+	if r.controller == nil || r.secretWatches[namespace] {
+		return nil
+	}
+	src, err := r.watchMetadata(corev1.SchemeGroupVersion.WithResource("secrets"), namespace)
+	if err != nil {
+		return err
+	}
+	err = r.controller.Watch(src, &handler.EnqueueRequestForOwner{
+		OwnerType:    &operatorv1alpha1.Gatekeeper{},
+		IsController: true,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "Unable to watch the secrets of namespace %s", namespace)
+	}
+	err = r.controller.Watch(src, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(gatekeeperRequests),
+	}, r.certificateSecretPredicate())
+	if err != nil {
+		return errors.Wrapf(err, "Unable to watch the secrets of namespace %s", namespace)
+	}
+	if r.secretWatches == nil {
+		r.secretWatches = make(map[string]bool)
+	}
+	r.secretWatches[namespace] = true
+	return nil
+}
+
+// certificateSecretPredicate keeps the events of the Secret providing the
+// webhook certificates, ignoring those of every other Secret.
+func (r *GatekeeperReconciler) certificateSecretPredicate() predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(meta metav1.Object, _ runtime.Object) bool {
+		gatekeeper, ok := r.getCachedGatekeeper()
+		if !ok || getCertificatesMode(gatekeeper.Spec.Webhook) != operatorv1alpha1.CertificatesSecret {
+			return false
+		}
+		ref, ok := getCertificatesSecretRef(gatekeeper.Spec.Webhook, r.Namespace)
+		return ok && ref.Name == meta.GetName() && ref.Namespace == meta.GetNamespace()
+	})
+}
+
+// writesCertificates returns whether the operator writes the webhook
+// certificates and CA bundle itself in the given mode.
+func writesCertificates(mode operatorv1alpha1.CertificatesMode) bool {
+	return mode == operatorv1alpha1.CertificatesOperator || mode == operatorv1alpha1.CertificatesSecret
+}
+
+func getCertificatesStatus(gatekeeper *operatorv1alpha1.Gatekeeper, certificates *certs.Certificates) *operatorv1alpha1.CertificatesStatus {
+	status := &operatorv1alpha1.CertificatesStatus{
+		Mode: getCertificatesMode(gatekeeper.Spec.Webhook),
+	}
+	if certificates != nil && !certificates.NotAfter.IsZero() {
+		notAfter := metav1.NewTime(certificates.NotAfter)
+		status.NotAfter = &notAfter
+	}
+	return status
+}
+
+// certificateOverrides configures the webhook server certificate secret,
+// service and webhook configurations for the given certificates mode. The
+// certificates are only set when they are written by the operator, otherwise
+// the CA bundle is left for Gatekeeper, the service CA or cert-manager to
+// inject.
+func certificateOverrides(mode operatorv1alpha1.CertificatesMode, version, asset string, obj *unstructured.Unstructured,
+	namespace string, certificates *certs.Certificates) error {
+	switch asset {
+	case ServerCertFile:
+		if writesCertificates(mode) && certificates != nil {
+			return setSecretData(obj, certificates.SecretData())
+		}
+	case ServiceFile:
+		if mode == operatorv1alpha1.CertificatesServiceCA {
+			secret, err := util.GetVersionedManifestObject(version, ServerCertFile)
+			if err != nil {
+				return err
+			}
+			setAnnotation(obj, serviceCASecretNameAnnotation, secret.GetName())
+		}
+	case ValidatingWebhookConfiguration, MutatingWebhookConfiguration:
+		switch mode {
+		case operatorv1alpha1.CertificatesServiceCA:
+			setAnnotation(obj, serviceCAInjectAnnotation, "true")
+		case operatorv1alpha1.CertificatesCertManager:
+			certificate, err := util.GetVersionedManifestObject(version, CertificateFile)
+			if err != nil {
+				return err
+			}
+			setAnnotation(obj, certManagerInjectAnnotation, fmt.Sprintf("%s/%s", namespace, certificate.GetName()))
+		}
+		var caBundle []byte
+		if writesCertificates(mode) && certificates != nil {
+			caBundle = certificates.CACert
+		}
+		return setCABundle(obj, caBundle)
+	}
+	return nil
+}
+
+// setCertManagerCertificate sets the DNS names, duration and renewal time of
+// the cert-manager Certificate from the webhook certificates configuration.
+func setCertManagerCertificate(obj *unstructured.Unstructured, version string, webhook *operatorv1alpha1.WebhookConfig, namespace string) error {
+	opts, err := getCertificateOptions(version, webhook, namespace)
+	if err != nil {
+		return err
+	}
+	if err := unstructured.SetNestedStringSlice(obj.Object, opts.DNSNames, "spec", "dnsNames"); err != nil {
+		return errors.Wrapf(err, "Failed to set certificate dnsNames")
+	}
+	if err := unstructured.SetNestedField(obj.Object, opts.Validity.String(), "spec", "duration"); err != nil {
+		return errors.Wrapf(err, "Failed to set certificate duration")
+	}
+	if err := unstructured.SetNestedField(obj.Object, opts.RotateBefore.String(), "spec", "renewBefore"); err != nil {
+		return errors.Wrapf(err, "Failed to set certificate renewBefore")
+	}
+	return nil
+}
+
+// isCertManagerInstalled returns whether the cert-manager CRDs are installed
+// in the cluster.
+func (r *GatekeeperReconciler) isCertManagerInstalled(ctx context.Context, gatekeeper *operatorv1alpha1.Gatekeeper) (bool, error) {
+	_, err := r.getClusterObject(ctx, gatekeeper, CertificateFile)
+	if err != nil {
+		if meta.IsNoMatchError(errors.Cause(err)) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func setAnnotation(obj *unstructured.Unstructured, key, value string) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[key] = value
+	obj.SetAnnotations(annotations)
+}
+
+func getSecretData(secret *unstructured.Unstructured) (map[string][]byte, error) {
+	encoded, _, err := unstructured.NestedStringMap(secret.Object, "data")
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to retrieve data from secret %s", secret.GetName())
+	}
+	data := make(map[string][]byte, len(encoded))
+	for k, v := range encoded {
+		decoded, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to decode %s from secret %s", k, secret.GetName())
+		}
+		data[k] = decoded
+	}
+	return data, nil
+}
+
+func setSecretData(obj *unstructured.Unstructured, data map[string][]byte) error {
+	encoded := make(map[string]string, len(data))
+	for k, v := range data {
+		encoded[k] = base64.StdEncoding.EncodeToString(v)
+	}
+	if err := unstructured.SetNestedStringMap(obj.Object, encoded, "data"); err != nil {
+		return errors.Wrapf(err, "Failed to set secret data")
+	}
+	return nil
+}
+
+func setCABundle(obj *unstructured.Unstructured, caBundle []byte) error {
+	webhooks, found, err := unstructured.NestedSlice(obj.Object, "webhooks")
+	if err != nil || !found {
+		return errors.Wrapf(err, "Failed to retrieve webhooks definition")
+	}
+	for _, w := range webhooks {
+		webhook := w.(map[string]interface{})
+		if caBundle == nil {
+			unstructured.RemoveNestedField(webhook, "clientConfig", "caBundle")
+			continue
+		}
+		err := unstructured.SetNestedField(webhook, base64.StdEncoding.EncodeToString(caBundle), "clientConfig", "caBundle")
+		if err != nil {
+			return errors.Wrapf(err, "Failed to set webhook clientConfig.caBundle")
+		}
+	}
+	if err := unstructured.SetNestedSlice(obj.Object, webhooks, "webhooks"); err != nil {
+		return errors.Wrapf(err, "Failed to set webhooks")
+	}
+	return nil
+}
@@ -0,0 +1,547 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	operatorv1alpha1 "github.com/gatekeeper/gatekeeper-operator/api/v1alpha1"
+	"github.com/gatekeeper/gatekeeper-operator/pkg/certs"
+	"github.com/gatekeeper/gatekeeper-operator/pkg/util"
+)
+
+func TestDisableCertRotationArg(t *testing.T) {
+	g := NewWithT(t)
+
+	gatekeeper := &operatorv1alpha1.Gatekeeper{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+	}
+
+	// test default
+	webhookObj, err := util.GetManifestObject(WebhookFile)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(webhookObj).ToNot(BeNil())
+	expectObjContainerArgument(g, managerContainer, webhookObj).NotTo(HaveKey(DisableCertRotationArg))
+	// test nil
+	err = crOverrides(gatekeeper, WebhookFile, webhookObj, namespace, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	expectObjContainerArgument(g, managerContainer, webhookObj).NotTo(HaveKey(DisableCertRotationArg))
+	// test Gatekeeper override
+	mode := operatorv1alpha1.CertificatesGatekeeper
+	gatekeeper.Spec.Webhook = &operatorv1alpha1.WebhookConfig{
+		Certificates: &operatorv1alpha1.CertificatesConfig{
+			Mode: &mode,
+		},
+	}
+	err = crOverrides(gatekeeper, WebhookFile, webhookObj, namespace, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	expectObjContainerArgument(g, managerContainer, webhookObj).NotTo(HaveKey(DisableCertRotationArg))
+	// test Operator override
+	mode = operatorv1alpha1.CertificatesOperator
+	err = crOverrides(gatekeeper, WebhookFile, webhookObj, namespace, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	expectObjContainerArgument(g, managerContainer, webhookObj).To(HaveKeyWithValue(DisableCertRotationArg, "true"))
+	// test ServiceCA override
+	webhookObj, err = util.GetManifestObject(WebhookFile)
+	g.Expect(err).ToNot(HaveOccurred())
+	mode = operatorv1alpha1.CertificatesServiceCA
+	err = crOverrides(gatekeeper, WebhookFile, webhookObj, namespace, true)
+	g.Expect(err).ToNot(HaveOccurred())
+	expectObjContainerArgument(g, managerContainer, webhookObj).To(HaveKeyWithValue(DisableCertRotationArg, "true"))
+}
+
+func TestGetCertificateOptions(t *testing.T) {
+	g := NewWithT(t)
+	mode := operatorv1alpha1.CertificatesOperator
+	webhook := &operatorv1alpha1.WebhookConfig{
+		Certificates: &operatorv1alpha1.CertificatesConfig{
+			Mode: &mode,
+		},
+	}
+
+	// test default
+	opts, err := getCertificateOptions(util.DefaultGatekeeperVersion, webhook, namespace)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(opts.DNSNames).To(ConsistOf(
+		"gatekeeper-webhook-service.testns.svc",
+		"gatekeeper-webhook-service.testns.svc.cluster.local",
+	))
+	g.Expect(opts.Validity).To(Equal(defaultCertificateValidity))
+	g.Expect(opts.RotateBefore).To(Equal(defaultCertificateRotateBefore))
+
+	// test override
+	webhook.Certificates.Validity = &metav1.Duration{Duration: 48 * time.Hour}
+	webhook.Certificates.RotateBefore = &metav1.Duration{Duration: 12 * time.Hour}
+	opts, err = getCertificateOptions(util.DefaultGatekeeperVersion, webhook, namespace)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(opts.Validity).To(Equal(48 * time.Hour))
+	g.Expect(opts.RotateBefore).To(Equal(12 * time.Hour))
+}
+
+func TestCertificateOverrides(t *testing.T) {
+	g := NewWithT(t)
+	certificates := &certs.Certificates{
+		CACert: []byte("ca cert"),
+		CAKey:  []byte("ca key"),
+		Cert:   []byte("cert"),
+		Key:    []byte("key"),
+	}
+
+	// test Gatekeeper managed certificates
+	serverCertObj, err := util.GetManifestObject(ServerCertFile)
+	g.Expect(err).ToNot(HaveOccurred())
+	err = certificateOverrides(operatorv1alpha1.CertificatesGatekeeper, util.DefaultGatekeeperVersion, ServerCertFile, serverCertObj, namespace, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	_, found, err := unstructured.NestedMap(serverCertObj.Object, "data")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(found).To(BeFalse())
+
+	for _, asset := range []string{ValidatingWebhookConfiguration, MutatingWebhookConfiguration} {
+		webhookConfiguration, err := util.GetManifestObject(asset)
+		g.Expect(err).ToNot(HaveOccurred())
+		err = certificateOverrides(operatorv1alpha1.CertificatesGatekeeper, util.DefaultGatekeeperVersion, asset, webhookConfiguration, namespace, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		assertWebhooksWithFn(g, webhookConfiguration, func(webhook map[string]interface{}) {
+			_, found, err := unstructured.NestedString(webhook, "clientConfig", "caBundle")
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(found).To(BeFalse())
+		})
+	}
+
+	// test operator managed certificates
+	err = certificateOverrides(operatorv1alpha1.CertificatesOperator, util.DefaultGatekeeperVersion, ServerCertFile, serverCertObj, namespace, certificates)
+	g.Expect(err).ToNot(HaveOccurred())
+	data, err := getSecretData(serverCertObj)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(data).To(Equal(certificates.SecretData()))
+
+	for _, asset := range []string{ValidatingWebhookConfiguration, MutatingWebhookConfiguration} {
+		webhookConfiguration, err := util.GetManifestObject(asset)
+		g.Expect(err).ToNot(HaveOccurred())
+		err = certificateOverrides(operatorv1alpha1.CertificatesOperator, util.DefaultGatekeeperVersion, asset, webhookConfiguration, namespace, certificates)
+		g.Expect(err).ToNot(HaveOccurred())
+		assertWebhooksWithFn(g, webhookConfiguration, func(webhook map[string]interface{}) {
+			caBundle, found, err := unstructured.NestedString(webhook, "clientConfig", "caBundle")
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(found).To(BeTrue())
+			g.Expect(caBundle).To(Equal(base64.StdEncoding.EncodeToString(certificates.CACert)))
+		})
+	}
+}
+
+func TestServiceCACertificateOverrides(t *testing.T) {
+	g := NewWithT(t)
+	mode := operatorv1alpha1.CertificatesServiceCA
+
+	// test service is annotated with the secret name
+	serviceObj, err := util.GetManifestObject(ServiceFile)
+	g.Expect(err).ToNot(HaveOccurred())
+	err = certificateOverrides(mode, util.DefaultGatekeeperVersion, ServiceFile, serviceObj, namespace, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(serviceObj.GetAnnotations()).To(HaveKeyWithValue(serviceCASecretNameAnnotation, "gatekeeper-webhook-server-cert"))
+
+	// test webhook configurations are annotated for CA injection
+	for _, asset := range []string{ValidatingWebhookConfiguration, MutatingWebhookConfiguration} {
+		webhookConfiguration, err := util.GetManifestObject(asset)
+		g.Expect(err).ToNot(HaveOccurred())
+		err = certificateOverrides(mode, util.DefaultGatekeeperVersion, asset, webhookConfiguration, namespace, &certs.Certificates{CACert: []byte("ca cert")})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(webhookConfiguration.GetAnnotations()).To(HaveKeyWithValue(serviceCAInjectAnnotation, "true"))
+		assertWebhooksWithFn(g, webhookConfiguration, func(webhook map[string]interface{}) {
+			_, found, err := unstructured.NestedString(webhook, "clientConfig", "caBundle")
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(found).To(BeFalse())
+		})
+	}
+
+	// test other modes do not annotate the service
+	serviceObj, err = util.GetManifestObject(ServiceFile)
+	g.Expect(err).ToNot(HaveOccurred())
+	err = certificateOverrides(operatorv1alpha1.CertificatesOperator, util.DefaultGatekeeperVersion, ServiceFile, serviceObj, namespace, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(serviceObj.GetAnnotations()).NotTo(HaveKey(serviceCASecretNameAnnotation))
+}
+
+func TestServiceCAAssets(t *testing.T) {
+	g := NewWithT(t)
+	mode := operatorv1alpha1.CertificatesServiceCA
+	gatekeeper := &operatorv1alpha1.Gatekeeper{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+		Spec: operatorv1alpha1.GatekeeperSpec{
+			Webhook: &operatorv1alpha1.WebhookConfig{
+				Certificates: &operatorv1alpha1.CertificatesConfig{
+					Mode: &mode,
+				},
+			},
+		},
+	}
+
+	// test the secret is left for the service CA to create
+	_, applyAssets := getStaticAssets(gatekeeper)
+	g.Expect(applyAssets).NotTo(ContainElement(ServerCertFile))
+
+	// test the service CA is only supported on OpenShift
+	r := &GatekeeperReconciler{
+		PlatformName: util.Kubernetes,
+	}
+	_, _, err := r.reconcileCertificates(context.Background(), gatekeeper)
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestGetCertificatesStatus(t *testing.T) {
+	g := NewWithT(t)
+	gatekeeper := &operatorv1alpha1.Gatekeeper{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+	}
+
+	// test default
+	status := getCertificatesStatus(gatekeeper, nil)
+	g.Expect(status.Mode).To(Equal(operatorv1alpha1.CertificatesGatekeeper))
+	g.Expect(status.NotAfter).To(BeNil())
+
+	// test operator managed certificates
+	mode := operatorv1alpha1.CertificatesOperator
+	gatekeeper.Spec.Webhook = &operatorv1alpha1.WebhookConfig{
+		Certificates: &operatorv1alpha1.CertificatesConfig{
+			Mode: &mode,
+		},
+	}
+	notAfter := time.Now().Add(time.Hour)
+	status = getCertificatesStatus(gatekeeper, &certs.Certificates{NotAfter: notAfter})
+	g.Expect(status.Mode).To(Equal(operatorv1alpha1.CertificatesOperator))
+	g.Expect(status.NotAfter).ToNot(BeNil())
+	g.Expect(status.NotAfter.Time).To(Equal(notAfter))
+}
+
+func TestCertManagerCertificateOverrides(t *testing.T) {
+	g := NewWithT(t)
+	mode := operatorv1alpha1.CertificatesCertManager
+	gatekeeper := &operatorv1alpha1.Gatekeeper{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+		Spec: operatorv1alpha1.GatekeeperSpec{
+			Webhook: &operatorv1alpha1.WebhookConfig{
+				Certificates: &operatorv1alpha1.CertificatesConfig{
+					Mode: &mode,
+				},
+			},
+		},
+	}
+
+	// test the certificate is issued for the webhook service in the namespace
+	certificateObj, err := util.GetManifestObject(CertificateFile)
+	g.Expect(err).ToNot(HaveOccurred())
+	err = crOverrides(gatekeeper, CertificateFile, certificateObj, namespace, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(certificateObj.GetNamespace()).To(Equal(namespace))
+	dnsNames, _, err := unstructured.NestedStringSlice(certificateObj.Object, "spec", "dnsNames")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(dnsNames).To(ConsistOf(
+		"gatekeeper-webhook-service.testns.svc",
+		"gatekeeper-webhook-service.testns.svc.cluster.local",
+	))
+	duration, _, err := unstructured.NestedString(certificateObj.Object, "spec", "duration")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(duration).To(Equal(defaultCertificateValidity.String()))
+	renewBefore, _, err := unstructured.NestedString(certificateObj.Object, "spec", "renewBefore")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(renewBefore).To(Equal(defaultCertificateRotateBefore.String()))
+
+	// test the validity overrides
+	gatekeeper.Spec.Webhook.Certificates.Validity = &metav1.Duration{Duration: 48 * time.Hour}
+	gatekeeper.Spec.Webhook.Certificates.RotateBefore = &metav1.Duration{Duration: 12 * time.Hour}
+	err = crOverrides(gatekeeper, CertificateFile, certificateObj, namespace, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	duration, _, err = unstructured.NestedString(certificateObj.Object, "spec", "duration")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(duration).To(Equal("48h0m0s"))
+	renewBefore, _, err = unstructured.NestedString(certificateObj.Object, "spec", "renewBefore")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(renewBefore).To(Equal("12h0m0s"))
+
+	// test webhook configurations are annotated for CA injection
+	for _, asset := range []string{ValidatingWebhookConfiguration, MutatingWebhookConfiguration} {
+		webhookConfiguration, err := util.GetManifestObject(asset)
+		g.Expect(err).ToNot(HaveOccurred())
+		err = certificateOverrides(mode, util.DefaultGatekeeperVersion, asset, webhookConfiguration, namespace, &certs.Certificates{CACert: []byte("ca cert")})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(webhookConfiguration.GetAnnotations()).To(HaveKeyWithValue(certManagerInjectAnnotation, "testns/gatekeeper-serving-cert"))
+		assertWebhooksWithFn(g, webhookConfiguration, func(webhook map[string]interface{}) {
+			_, found, err := unstructured.NestedString(webhook, "clientConfig", "caBundle")
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(found).To(BeFalse())
+		})
+	}
+}
+
+func TestCertManagerAssets(t *testing.T) {
+	g := NewWithT(t)
+	mode := operatorv1alpha1.CertificatesCertManager
+	gatekeeper := &operatorv1alpha1.Gatekeeper{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+		Spec: operatorv1alpha1.GatekeeperSpec{
+			Webhook: &operatorv1alpha1.WebhookConfig{
+				Certificates: &operatorv1alpha1.CertificatesConfig{
+					Mode: &mode,
+				},
+			},
+		},
+	}
+
+	// test the secret is replaced by the cert-manager resources
+	deleteAssets, applyAssets := getStaticAssets(gatekeeper)
+	g.Expect(applyAssets).NotTo(ContainElement(ServerCertFile))
+	g.Expect(applyAssets).To(ContainElements(IssuerFile, CertificateFile))
+	g.Expect(deleteAssets).NotTo(ContainElements(IssuerFile, CertificateFile))
+
+	// test the cert-manager resources are deleted when switching modes
+	gatekeeper.Status.Certificates = &operatorv1alpha1.CertificatesStatus{
+		Mode: operatorv1alpha1.CertificatesCertManager,
+	}
+	gatekeeper.Spec.Webhook = nil
+	deleteAssets, applyAssets = getStaticAssets(gatekeeper)
+	g.Expect(applyAssets).To(ContainElement(ServerCertFile))
+	g.Expect(applyAssets).NotTo(ContainElements(IssuerFile, CertificateFile))
+	g.Expect(deleteAssets).To(ContainElements(IssuerFile, CertificateFile))
+}
+
+// secretClient serves a single secret for Get requests.
+type secretClient struct {
+	client.Client
+	secret *unstructured.Unstructured
+}
+
+func (c *secretClient) Get(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+	if c.secret == nil || key.Namespace != c.secret.GetNamespace() || key.Name != c.secret.GetName() {
+		return apierrors.NewNotFound(corev1.Resource("secrets"), key.Name)
+	}
+	c.secret.DeepCopyInto(obj.(*unstructured.Unstructured))
+	return nil
+}
+
+func TestSecretCertificates(t *testing.T) {
+	g := NewWithT(t)
+	mode := operatorv1alpha1.CertificatesSecret
+	webhook := &operatorv1alpha1.WebhookConfig{
+		Certificates: &operatorv1alpha1.CertificatesConfig{
+			Mode: &mode,
+		},
+	}
+	gatekeeper := &operatorv1alpha1.Gatekeeper{
+		Spec: operatorv1alpha1.GatekeeperSpec{
+			Webhook: webhook,
+		},
+	}
+	opts, err := getCertificateOptions(util.DefaultGatekeeperVersion, webhook, namespace)
+	g.Expect(err).ToNot(HaveOccurred())
+	provided, err := certs.Ensure(nil, opts, time.Now())
+	g.Expect(err).ToNot(HaveOccurred())
+	secret := &unstructured.Unstructured{}
+	secret.SetNamespace("pki")
+	secret.SetName("webhook-cert")
+	g.Expect(setSecretData(secret, map[string][]byte{
+		certs.CACertName: provided.CACert,
+		certs.CertName:   provided.Cert,
+		certs.KeyName:    provided.Key,
+	})).To(Succeed())
+	r := &GatekeeperReconciler{
+		Client:    &secretClient{secret: secret},
+		Namespace: namespace,
+	}
+
+	// test missing secretRef
+	_, err = r.getSecretCertificates(context.Background(), gatekeeper)
+	g.Expect(isInvalidSecretError(err)).To(BeTrue())
+
+	// test missing secret defaults to the Gatekeeper namespace
+	webhook.Certificates.SecretRef = &corev1.SecretReference{Name: "webhook-cert"}
+	ref, ok := getCertificatesSecretRef(webhook, namespace)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(ref).To(Equal(types.NamespacedName{Namespace: namespace, Name: "webhook-cert"}))
+	_, err = r.getSecretCertificates(context.Background(), gatekeeper)
+	g.Expect(isInvalidSecretError(err)).To(BeTrue())
+
+	// test valid secret
+	webhook.Certificates.SecretRef.Namespace = "pki"
+	certificates, err := r.getSecretCertificates(context.Background(), gatekeeper)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(certificates.CACert).To(Equal(provided.CACert))
+	g.Expect(certificates.Cert).To(Equal(provided.Cert))
+	g.Expect(certificates.Key).To(Equal(provided.Key))
+	g.Expect(certificates.NotAfter).To(BeTemporally("~", provided.NotAfter, time.Second))
+
+	// test certificate issued for another namespace
+	r.Namespace = "otherns"
+	_, err = r.getSecretCertificates(context.Background(), gatekeeper)
+	g.Expect(isInvalidSecretError(err)).To(BeTrue())
+	r.Namespace = namespace
+
+	// test the certificates are copied and the CA injected
+	serverCertObj, err := util.GetManifestObject(ServerCertFile)
+	g.Expect(err).ToNot(HaveOccurred())
+	err = certificateOverrides(mode, util.DefaultGatekeeperVersion, ServerCertFile, serverCertObj, namespace, certificates)
+	g.Expect(err).ToNot(HaveOccurred())
+	data, err := getSecretData(serverCertObj)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(data).To(Equal(map[string][]byte{
+		certs.CACertName: provided.CACert,
+		certs.CertName:   provided.Cert,
+		certs.KeyName:    provided.Key,
+	}))
+	for _, asset := range []string{ValidatingWebhookConfiguration, MutatingWebhookConfiguration} {
+		webhookConfiguration, err := util.GetManifestObject(asset)
+		g.Expect(err).ToNot(HaveOccurred())
+		err = certificateOverrides(mode, util.DefaultGatekeeperVersion, asset, webhookConfiguration, namespace, certificates)
+		g.Expect(err).ToNot(HaveOccurred())
+		assertWebhooksWithFn(g, webhookConfiguration, func(webhook map[string]interface{}) {
+			caBundle, _, err := unstructured.NestedString(webhook, "clientConfig", "caBundle")
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(caBundle).To(Equal(base64.StdEncoding.EncodeToString(provided.CACert)))
+		})
+	}
+}
+
+func TestInvalidSecretKeepsCertificates(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	mode := operatorv1alpha1.CertificatesSecret
+	gatekeeper := &operatorv1alpha1.Gatekeeper{}
+	gatekeeper.Spec.Webhook = &operatorv1alpha1.WebhookConfig{
+		Certificates: &operatorv1alpha1.CertificatesConfig{
+			Mode:      &mode,
+			SecretRef: &corev1.SecretReference{Namespace: "pki", Name: "webhook-cert"},
+		},
+	}
+	opts, err := getCertificateOptions(util.DefaultGatekeeperVersion, gatekeeper.Spec.Webhook, namespace)
+	g.Expect(err).ToNot(HaveOccurred())
+	provided, err := certs.Ensure(nil, opts, time.Now())
+	g.Expect(err).ToNot(HaveOccurred())
+	secret := &unstructured.Unstructured{}
+	secret.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind(util.SecretKind))
+	secret.SetNamespace("pki")
+	secret.SetName("webhook-cert")
+	g.Expect(setSecretData(secret, map[string][]byte{
+		certs.CACertName: provided.CACert,
+		certs.CertName:   provided.Cert,
+		certs.KeyName:    provided.Key,
+	})).To(Succeed())
+	r := &GatekeeperReconciler{
+		Client:    fake.NewFakeClient(secret),
+		Log:       ctrl.Log.WithName("test"),
+		Namespace: namespace,
+	}
+
+	// render returns the webhook server certificate secret and the validating
+	// webhook configuration rendered for the reconciled certificates.
+	render := func() (*unstructured.Unstructured, *unstructured.Unstructured) {
+		certificates, _, err := r.reconcileCertificates(ctx, gatekeeper)
+		g.Expect(err).ToNot(HaveOccurred())
+		serverCertObj, err := util.GetManifestObject(ServerCertFile)
+		g.Expect(err).ToNot(HaveOccurred())
+		serverCertObj.SetNamespace(namespace)
+		g.Expect(certificateOverrides(mode, util.DefaultGatekeeperVersion, ServerCertFile, serverCertObj, namespace, certificates)).To(Succeed())
+		webhookConfiguration, err := util.GetManifestObject(ValidatingWebhookConfiguration)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(certificateOverrides(mode, util.DefaultGatekeeperVersion, ValidatingWebhookConfiguration, webhookConfiguration, namespace, certificates)).To(Succeed())
+		return serverCertObj, webhookConfiguration
+	}
+	assertCertificates := func(serverCertObj, webhookConfiguration *unstructured.Unstructured) {
+		data, err := getSecretData(serverCertObj)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(data).To(HaveKeyWithValue(certs.CertName, provided.Cert))
+		g.Expect(data).To(HaveKeyWithValue(certs.KeyName, provided.Key))
+		assertWebhooksWithFn(g, webhookConfiguration, func(webhook map[string]interface{}) {
+			caBundle, _, err := unstructured.NestedString(webhook, "clientConfig", "caBundle")
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(caBundle).To(Equal(base64.StdEncoding.EncodeToString(provided.CACert)))
+		})
+	}
+
+	// test the certificates of the valid secret are rendered
+	serverCertObj, webhookConfiguration := render()
+	assertCertificates(serverCertObj, webhookConfiguration)
+	g.Expect(r.Create(ctx, serverCertObj)).To(Succeed())
+
+	// test the current certificates are rendered again once the secret is
+	// invalid
+	unstructured.RemoveNestedField(secret.Object, "data", certs.CertName)
+	g.Expect(r.Update(ctx, secret)).To(Succeed())
+	_, err = r.getSecretCertificates(ctx, gatekeeper)
+	g.Expect(isInvalidSecretError(err)).To(BeTrue())
+	serverCertObj, webhookConfiguration = render()
+	assertCertificates(serverCertObj, webhookConfiguration)
+}
+
+func TestCertificateSecretPredicate(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	g.Expect(operatorv1alpha1.AddToScheme(scheme)).To(Succeed())
+	mode := operatorv1alpha1.CertificatesSecret
+	gatekeeper := &operatorv1alpha1.Gatekeeper{
+		ObjectMeta: metav1.ObjectMeta{Name: defaultGatekeeperCrName},
+		Spec: operatorv1alpha1.GatekeeperSpec{
+			Webhook: &operatorv1alpha1.WebhookConfig{
+				Certificates: &operatorv1alpha1.CertificatesConfig{
+					Mode:      &mode,
+					SecretRef: &corev1.SecretReference{Name: "webhook-cert"},
+				},
+			},
+		},
+	}
+	r := &GatekeeperReconciler{
+		Namespace: namespace,
+		cache:     fake.NewFakeClientWithScheme(scheme, gatekeeper),
+	}
+	p := r.certificateSecretPredicate()
+	updated := func(namespace, name string) bool {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+		return p.Update(event.UpdateEvent{MetaOld: secret, ObjectOld: secret, MetaNew: secret, ObjectNew: secret})
+	}
+
+	// test only the configured Secret is kept, in the Gatekeeper namespace by default
+	g.Expect(updated(namespace, "webhook-cert")).To(BeTrue())
+	g.Expect(updated("other", "webhook-cert")).To(BeFalse())
+	g.Expect(updated(namespace, "other")).To(BeFalse())
+
+	// test the Secret is ignored in the other certificates modes
+	operatorMode := operatorv1alpha1.CertificatesOperator
+	gatekeeper.Spec.Webhook.Certificates.Mode = &operatorMode
+	r.cache = fake.NewFakeClientWithScheme(scheme, gatekeeper)
+	g.Expect(updated(namespace, "webhook-cert")).To(BeFalse())
+}
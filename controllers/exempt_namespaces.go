@@ -0,0 +1,237 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	operatorv1alpha1 "github.com/gatekeeper/gatekeeper-operator/api/v1alpha1"
+)
+
+const (
+	IgnoreNamespaceLabel = "admission.gatekeeper.sh/ignore"
+	// exemptNamespaceAnnotation marks the namespaces labeled by the operator
+	// so that the label is only removed from those.
+	exemptNamespaceAnnotation = "operator.gatekeeper.sh/exempt-namespace"
+	exemptNamespaceLabelValue = "exempt"
+	auditProcess              = "audit"
+)
+
+// getRequiredExemptNamespaces returns the namespaces that are always exempt
+// from Gatekeeper.
+func (r *GatekeeperReconciler) getRequiredExemptNamespaces() []string {
+	return uniqueNamespaces([]string{r.Namespace, r.OperatorNamespace})
+}
+
+func getWebhookExemptNamespaces(webhook *operatorv1alpha1.WebhookConfig) []string {
+	if webhook == nil {
+		return nil
+	}
+	return webhook.ExemptNamespaces
+}
+
+func getAuditExemptNamespaces(audit *operatorv1alpha1.AuditConfig) []string {
+	if audit == nil {
+		return nil
+	}
+	return audit.ExemptNamespaces
+}
+
+// uniqueNamespaces concatenates the namespace lists, dropping empty and
+// duplicate entries.
+func uniqueNamespaces(lists ...[]string) []string {
+	seen := make(map[string]bool)
+	namespaces := make([]string, 0)
+	for _, list := range lists {
+		for _, ns := range list {
+			if ns == "" || seen[ns] {
+				continue
+			}
+			seen[ns] = true
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces
+}
+
+// configManaged returns whether the operator manages the Gatekeeper Config
+// resource. Only spec.config opts in, so that a Config created by the user is
+// never taken over.
+func configManaged(spec operatorv1alpha1.GatekeeperSpec) bool {
+	return spec.Config != nil
+}
+
+// exemptNamespaceOverrides renders the exempt namespaces into the webhook
+// arguments and the audit exclusions of the Config resource.
+func exemptNamespaceOverrides(asset string, obj *unstructured.Unstructured, spec operatorv1alpha1.GatekeeperSpec, requiredNamespaces []string) error {
+	switch asset {
+	case WebhookFile:
+		namespaces := uniqueNamespaces(requiredNamespaces, getWebhookExemptNamespaces(spec.Webhook))
+		return setContainerArgs(obj, managerContainer, ExemptNamespaceArg, namespaces)
+	case ConfigFile:
+		auditNamespaces := getAuditExemptNamespaces(spec.Audit)
+		if !configManaged(spec) || len(auditNamespaces) == 0 {
+			return nil
+		}
+		return addConfigMatchEntry(obj, uniqueNamespaces(requiredNamespaces, auditNamespaces), auditProcess)
+	}
+	return nil
+}
+
+func addConfigMatchEntry(obj *unstructured.Unstructured, namespaces []string, processes ...string) error {
+	match, _, err := unstructured.NestedSlice(obj.Object, "spec", "match")
+	if err != nil {
+		return errors.Wrapf(err, "Failed to retrieve config match entries")
+	}
+	entry := map[string]interface{}{
+		"excludedNamespaces": toInterfaceSlice(namespaces),
+		"processes":          toInterfaceSlice(processes),
+	}
+	if err := unstructured.SetNestedSlice(obj.Object, append(match, entry), "spec", "match"); err != nil {
+		return errors.Wrapf(err, "Failed to set config match entries")
+	}
+	return nil
+}
+
+func toInterfaceSlice(values []string) []interface{} {
+	slice := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		slice = append(slice, v)
+	}
+	return slice
+}
+
+// reconcileExemptNamespaceLabels labels the exempt namespaces with
+// admission.gatekeeper.sh/ignore, which Gatekeeper only accepts on exempt
+// namespaces, and removes the label from the namespaces previously labeled
+// by the operator that are no longer exempt.
+func (r *GatekeeperReconciler) reconcileExemptNamespaceLabels(ctx context.Context, namespaces []string) error {
+	exempt := make(map[string]bool, len(namespaces))
+	for _, name := range namespaces {
+		exempt[name] = true
+
+		ns := &corev1.Namespace{}
+		err := r.uncachedReader().Get(ctx, types.NamespacedName{Name: name}, ns)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "Unable to get namespace %s", name)
+		}
+		if _, ok := ns.GetLabels()[IgnoreNamespaceLabel]; ok {
+			continue
+		}
+		setExemptNamespaceLabel(ns, true)
+		if err = r.Update(ctx, ns); err != nil {
+			return errors.Wrapf(err, "Unable to label exempt namespace %s", name)
+		}
+	}
+
+	labeled := &corev1.NamespaceList{}
+	if err := r.uncachedReader().List(ctx, labeled, client.HasLabels{IgnoreNamespaceLabel}); err != nil {
+		return errors.Wrapf(err, "Unable to list namespaces labeled %s", IgnoreNamespaceLabel)
+	}
+	for i := range labeled.Items {
+		ns := &labeled.Items[i]
+		if exempt[ns.GetName()] || ns.GetAnnotations()[exemptNamespaceAnnotation] != "true" {
+			continue
+		}
+		setExemptNamespaceLabel(ns, false)
+		if err := r.Update(ctx, ns); err != nil {
+			return errors.Wrapf(err, "Unable to unlabel namespace %s", ns.GetName())
+		}
+	}
+	return nil
+}
+
+func setExemptNamespaceLabel(ns *corev1.Namespace, exempt bool) {
+	labels := withoutKey(ns.GetLabels(), IgnoreNamespaceLabel)
+	annotations := withoutKey(ns.GetAnnotations(), exemptNamespaceAnnotation)
+	if exempt {
+		labels[IgnoreNamespaceLabel] = exemptNamespaceLabelValue
+		annotations[exemptNamespaceAnnotation] = "true"
+	}
+	ns.SetLabels(labels)
+	ns.SetAnnotations(annotations)
+}
+
+// withoutKey returns a copy of the map without the given key. The builtin
+// delete is shadowed by the crudOperation of the same name.
+func withoutKey(m map[string]string, key string) map[string]string {
+	copied := make(map[string]string, len(m))
+	for k, v := range m {
+		if k != key {
+			copied[k] = v
+		}
+	}
+	return copied
+}
+
+// watchNamespaces watches the namespaces so that exempt namespaces are
+// labeled once created, as well as the Gatekeeper namespace deployed by the
+// operator on OpenShift. Only the metadata of the namespaces is cached.
+func (r *GatekeeperReconciler) watchNamespaces() error {
+	src, err := r.watchMetadata(corev1.SchemeGroupVersion.WithResource("namespaces"), "")
+	if err != nil {
+		return err
+	}
+	err = r.controller.Watch(src, &handler.EnqueueRequestForOwner{
+		OwnerType:    &operatorv1alpha1.Gatekeeper{},
+		IsController: true,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "Unable to watch namespaces")
+	}
+	err = r.controller.Watch(src, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(gatekeeperRequests),
+	}, r.exemptNamespacePredicate())
+	if err != nil {
+		return errors.Wrapf(err, "Unable to watch namespaces")
+	}
+	return nil
+}
+
+// exemptNamespacePredicate keeps the events of the namespaces that carry
+// the exempt label or need it, when the operator has to add or remove the
+// label, e.g. once an exempt namespace is created.
+func (r *GatekeeperReconciler) exemptNamespacePredicate() predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(meta metav1.Object, _ runtime.Object) bool {
+		gatekeeper, ok := r.getCachedGatekeeper()
+		if !ok {
+			return false
+		}
+		exempt := false
+		for _, ns := range uniqueNamespaces(r.getRequiredExemptNamespaces(), getWebhookExemptNamespaces(gatekeeper.Spec.Webhook)) {
+			if ns == meta.GetName() {
+				exempt = true
+			}
+		}
+		if _, labeled := meta.GetLabels()[IgnoreNamespaceLabel]; labeled {
+			return !exempt && meta.GetAnnotations()[exemptNamespaceAnnotation] == "true"
+		}
+		return exempt
+	})
+}
@@ -0,0 +1,272 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	operatorv1alpha1 "github.com/gatekeeper/gatekeeper-operator/api/v1alpha1"
+	"github.com/gatekeeper/gatekeeper-operator/pkg/util"
+)
+
+func TestWebhookExemptNamespaces(t *testing.T) {
+	g := NewWithT(t)
+	spec := operatorv1alpha1.GatekeeperSpec{}
+	required := []string{namespace, "operatorns"}
+
+	webhookObj, err := util.GetManifestObject(WebhookFile)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// test default
+	obj := webhookObj.DeepCopy()
+	err = exemptNamespaceOverrides(WebhookFile, obj, spec, required)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(getExemptNamespaceArgs(g, obj)).To(Equal([]string{namespace, "operatorns"}))
+
+	// test override, duplicates are dropped
+	spec.Webhook = &operatorv1alpha1.WebhookConfig{
+		ExemptNamespaces: []string{"kube-system", namespace, "monitoring"},
+	}
+	obj = webhookObj.DeepCopy()
+	err = exemptNamespaceOverrides(WebhookFile, obj, spec, required)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(getExemptNamespaceArgs(g, obj)).To(Equal([]string{namespace, "operatorns", "kube-system", "monitoring"}))
+}
+
+func getExemptNamespaceArgs(g *WithT, obj *unstructured.Unstructured) []string {
+	containers, found, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(found).To(BeTrue())
+
+	namespaces := make([]string, 0)
+	for _, c := range containers {
+		args, _, err := unstructured.NestedStringSlice(c.(map[string]interface{}), "args")
+		g.Expect(err).ToNot(HaveOccurred())
+		for _, arg := range args {
+			if key, value := util.FromArg(arg); key == ExemptNamespaceArg {
+				namespaces = append(namespaces, value)
+			}
+		}
+	}
+	return namespaces
+}
+
+func TestAuditExemptNamespaces(t *testing.T) {
+	g := NewWithT(t)
+	gatekeeper := &operatorv1alpha1.Gatekeeper{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+	}
+	required := []string{namespace, "operatorns"}
+
+	configObj, err := util.GetManifestObject(ConfigFile)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// test default, the Config is left unmanaged
+	g.Expect(configManaged(gatekeeper.Spec)).To(BeFalse())
+	obj := configObj.DeepCopy()
+	err = exemptNamespaceOverrides(ConfigFile, obj, gatekeeper.Spec, required)
+	g.Expect(err).ToNot(HaveOccurred())
+	_, found, err := unstructured.NestedSlice(obj.Object, "spec", "match")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(found).To(BeFalse())
+
+	// test override, appended to the match entries of the Config
+	gatekeeper.Spec.Audit = &operatorv1alpha1.AuditConfig{
+		ExemptNamespaces: []string{"kube-system"},
+	}
+	gatekeeper.Spec.Config = &operatorv1alpha1.ConfigSpec{
+		Match: []operatorv1alpha1.MatchEntry{
+			{
+				ExcludedNamespaces: []string{"monitoring"},
+				Processes:          []string{"sync"},
+			},
+		},
+	}
+	g.Expect(configManaged(gatekeeper.Spec)).To(BeTrue())
+	_, applyAssets := getStaticAssets(gatekeeper)
+	g.Expect(applyAssets).To(ContainElement(ConfigFile))
+
+	obj = configObj.DeepCopy()
+	err = crOverrides(gatekeeper, ConfigFile, obj, namespace, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	err = exemptNamespaceOverrides(ConfigFile, obj, gatekeeper.Spec, required)
+	g.Expect(err).ToNot(HaveOccurred())
+	match, found, err := unstructured.NestedSlice(obj.Object, "spec", "match")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(found).To(BeTrue())
+	g.Expect(match).To(Equal([]interface{}{
+		map[string]interface{}{
+			"excludedNamespaces": []interface{}{"monitoring"},
+			"processes":          []interface{}{"sync"},
+		},
+		map[string]interface{}{
+			"excludedNamespaces": []interface{}{namespace, "operatorns", "kube-system"},
+			"processes":          []interface{}{auditProcess},
+		},
+	}))
+
+	// test audit exemptions alone leave the Config unmanaged
+	gatekeeper.Spec.Config = nil
+	g.Expect(configManaged(gatekeeper.Spec)).To(BeFalse())
+	_, applyAssets = getStaticAssets(gatekeeper)
+	g.Expect(applyAssets).ToNot(ContainElement(ConfigFile))
+	obj = configObj.DeepCopy()
+	err = exemptNamespaceOverrides(ConfigFile, obj, gatekeeper.Spec, required)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(obj).To(Equal(configObj))
+}
+
+func TestUserConfigLeftUntouched(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	userConfig, err := util.GetManifestObject(ConfigFile)
+	g.Expect(err).ToNot(HaveOccurred())
+	userConfig.SetNamespace(namespace)
+	userMatch := []interface{}{
+		map[string]interface{}{
+			"excludedNamespaces": []interface{}{"monitoring"},
+			"processes":          []interface{}{"*"},
+		},
+	}
+	g.Expect(unstructured.SetNestedSlice(userConfig.Object, userMatch, "spec", "match")).To(Succeed())
+	r := &GatekeeperReconciler{
+		Client:    fake.NewFakeClient(userConfig.DeepCopy()),
+		Log:       ctrl.Log.WithName("test"),
+		Namespace: namespace,
+	}
+	gatekeeper := &operatorv1alpha1.Gatekeeper{}
+	gatekeeper.Spec.Audit = &operatorv1alpha1.AuditConfig{
+		ExemptNamespaces: []string{"kube-system"},
+	}
+
+	// test the Config is neither applied nor removed
+	_, applyAssets := getStaticAssets(gatekeeper)
+	g.Expect(applyAssets).ToNot(ContainElement(ConfigFile))
+	g.Expect(r.orphanAssets(ctx, gatekeeper, []string{ConfigFile})).To(Succeed())
+	for _, stage := range getUninstallStages(gatekeeper, false) {
+		_, err := r.deleteAssets(ctx, gatekeeper, stage)
+		g.Expect(err).ToNot(HaveOccurred())
+	}
+
+	clusterObj, err := r.getClusterObject(ctx, gatekeeper, ConfigFile)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(clusterObj).ToNot(BeNil())
+	match, _, err := unstructured.NestedSlice(clusterObj.Object, "spec", "match")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(match).To(Equal(userMatch))
+}
+
+func TestReconcileExemptNamespaceLabels(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	r := &GatekeeperReconciler{
+		Client: fake.NewFakeClient(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "exempt"}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:   "labeled",
+				Labels: map[string]string{IgnoreNamespaceLabel: "user"},
+			}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:        "stale",
+				Labels:      map[string]string{IgnoreNamespaceLabel: exemptNamespaceLabelValue},
+				Annotations: map[string]string{exemptNamespaceAnnotation: "true"},
+			}},
+		),
+	}
+
+	err := r.reconcileExemptNamespaceLabels(ctx, []string{"exempt", "labeled", "missing"})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	ns := &corev1.Namespace{}
+	g.Expect(r.Get(ctx, types.NamespacedName{Name: "exempt"}, ns)).To(Succeed())
+	g.Expect(ns.GetLabels()).To(HaveKeyWithValue(IgnoreNamespaceLabel, exemptNamespaceLabelValue))
+	g.Expect(ns.GetAnnotations()).To(HaveKeyWithValue(exemptNamespaceAnnotation, "true"))
+
+	// labels set outside of the operator are kept as is
+	ns = &corev1.Namespace{}
+	g.Expect(r.Get(ctx, types.NamespacedName{Name: "labeled"}, ns)).To(Succeed())
+	g.Expect(ns.GetLabels()).To(HaveKeyWithValue(IgnoreNamespaceLabel, "user"))
+	g.Expect(ns.GetAnnotations()).NotTo(HaveKey(exemptNamespaceAnnotation))
+
+	// labels set by the operator are removed once no longer exempt
+	ns = &corev1.Namespace{}
+	g.Expect(r.Get(ctx, types.NamespacedName{Name: "stale"}, ns)).To(Succeed())
+	g.Expect(ns.GetLabels()).NotTo(HaveKey(IgnoreNamespaceLabel))
+	g.Expect(ns.GetAnnotations()).NotTo(HaveKey(exemptNamespaceAnnotation))
+
+	err = r.reconcileExemptNamespaceLabels(ctx, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	ns = &corev1.Namespace{}
+	g.Expect(r.Get(ctx, types.NamespacedName{Name: "exempt"}, ns)).To(Succeed())
+	g.Expect(ns.GetLabels()).NotTo(HaveKey(IgnoreNamespaceLabel))
+	ns = &corev1.Namespace{}
+	g.Expect(r.Get(ctx, types.NamespacedName{Name: "labeled"}, ns)).To(Succeed())
+	g.Expect(ns.GetLabels()).To(HaveKey(IgnoreNamespaceLabel))
+}
+
+func TestExemptNamespacePredicate(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	g.Expect(operatorv1alpha1.AddToScheme(scheme)).To(Succeed())
+	gatekeeper := &operatorv1alpha1.Gatekeeper{
+		ObjectMeta: metav1.ObjectMeta{Name: defaultGatekeeperCrName},
+		Spec: operatorv1alpha1.GatekeeperSpec{
+			Webhook: &operatorv1alpha1.WebhookConfig{ExemptNamespaces: []string{"exempt"}},
+		},
+	}
+	r := &GatekeeperReconciler{
+		Namespace: namespace,
+		cache:     fake.NewFakeClientWithScheme(scheme, gatekeeper),
+	}
+	p := r.exemptNamespacePredicate()
+	created := func(ns *corev1.Namespace) bool {
+		return p.Create(event.CreateEvent{Meta: ns, Object: ns})
+	}
+
+	// test the namespaces that need the label are kept
+	g.Expect(created(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "exempt"}})).To(BeTrue())
+	g.Expect(created(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}})).To(BeTrue())
+	g.Expect(created(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other"}})).To(BeFalse())
+
+	// test the namespaces that already carry the label are ignored
+	labeled := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "exempt",
+		Labels: map[string]string{IgnoreNamespaceLabel: exemptNamespaceLabelValue},
+	}}
+	g.Expect(created(labeled)).To(BeFalse())
+
+	// test only the namespaces labeled by the operator are kept once no longer exempt
+	labeled.SetName("stale")
+	g.Expect(created(labeled)).To(BeFalse())
+	labeled.SetAnnotations(map[string]string{exemptNamespaceAnnotation: "true"})
+	g.Expect(created(labeled)).To(BeTrue())
+
+	// test events are ignored without a Gatekeeper resource
+	r.cache = fake.NewFakeClientWithScheme(scheme)
+	g.Expect(created(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "exempt"}})).To(BeFalse())
+}
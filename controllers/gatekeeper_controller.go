@@ -27,68 +27,99 @@ import (
 	admregv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/metadata"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	operatorv1alpha1 "github.com/gatekeeper/gatekeeper-operator/api/v1alpha1"
 	"github.com/gatekeeper/gatekeeper-operator/controllers/merge"
+	"github.com/gatekeeper/gatekeeper-operator/pkg/certs"
 	"github.com/gatekeeper/gatekeeper-operator/pkg/util"
 )
 
 const (
-	defaultGatekeeperCrName        = "gatekeeper"
-	openshiftAssetsDir             = "openshift/"
-	NamespaceFile                  = "v1_namespace_gatekeeper-system.yaml"
-	AssignCRDFile                  = "apiextensions.k8s.io_v1beta1_customresourcedefinition_assign.mutations.gatekeeper.sh.yaml"
-	AssignMetadataCRDFile          = "apiextensions.k8s.io_v1beta1_customresourcedefinition_assignmetadata.mutations.gatekeeper.sh.yaml"
-	AuditFile                      = "apps_v1_deployment_gatekeeper-audit.yaml"
-	WebhookFile                    = "apps_v1_deployment_gatekeeper-controller-manager.yaml"
-	ClusterRoleFile                = "rbac.authorization.k8s.io_v1_clusterrole_gatekeeper-manager-role.yaml"
-	ClusterRoleBindingFile         = "rbac.authorization.k8s.io_v1_clusterrolebinding_gatekeeper-manager-rolebinding.yaml"
-	RoleFile                       = "rbac.authorization.k8s.io_v1_role_gatekeeper-manager-role.yaml"
-	RoleBindingFile                = "rbac.authorization.k8s.io_v1_rolebinding_gatekeeper-manager-rolebinding.yaml"
-	ServerCertFile                 = "v1_secret_gatekeeper-webhook-server-cert.yaml"
-	ValidatingWebhookConfiguration = "admissionregistration.k8s.io_v1beta1_validatingwebhookconfiguration_gatekeeper-validating-webhook-configuration.yaml"
-	MutatingWebhookConfiguration   = "admissionregistration.k8s.io_v1beta1_mutatingwebhookconfiguration_gatekeeper-mutating-webhook-configuration.yaml"
-	ValidationGatekeeperWebhook    = "validation.gatekeeper.sh"
-	MutationGatekeeperWebhook      = "mutation.gatekeeper.sh"
-	managerContainer               = "manager"
-	LogLevelArg                    = "--log-level"
-	AuditIntervalArg               = "--audit-interval"
-	ConstraintViolationLimitArg    = "--constraint-violations-limit"
-	AuditFromCacheArg              = "--audit-from-cache"
-	AuditChunkSizeArg              = "--audit-chunk-size"
-	EmitAuditEventsArg             = "--emit-audit-events"
-	EmitAdmissionEventsArg         = "--emit-admission-events"
-	ExemptNamespaceArg             = "--exempt-namespace"
-	EnableMutationArg              = "--enable-mutation"
+	defaultGatekeeperCrName            = operatorv1alpha1.GatekeeperName
+	certManagerAssetsDir               = "certmanager/"
+	NamespaceFile                      = "v1_namespace_gatekeeper-system.yaml"
+	ConfigCRDFile                      = "apiextensions.k8s.io_v1beta1_customresourcedefinition_configs.config.gatekeeper.sh.yaml"
+	ConstraintTemplateCRDFile          = "apiextensions.k8s.io_v1beta1_customresourcedefinition_constrainttemplates.templates.gatekeeper.sh.yaml"
+	ConstraintTemplatePodStatusCRDFile = "apiextensions.k8s.io_v1beta1_customresourcedefinition_constrainttemplatepodstatuses.status.gatekeeper.sh.yaml"
+	ConstraintPodStatusCRDFile         = "apiextensions.k8s.io_v1beta1_customresourcedefinition_constraintpodstatuses.status.gatekeeper.sh.yaml"
+	AssignCRDFile                      = "apiextensions.k8s.io_v1beta1_customresourcedefinition_assign.mutations.gatekeeper.sh.yaml"
+	AssignMetadataCRDFile              = "apiextensions.k8s.io_v1beta1_customresourcedefinition_assignmetadata.mutations.gatekeeper.sh.yaml"
+	AuditFile                          = "apps_v1_deployment_gatekeeper-audit.yaml"
+	WebhookFile                        = "apps_v1_deployment_gatekeeper-controller-manager.yaml"
+	ClusterRoleFile                    = "rbac.authorization.k8s.io_v1_clusterrole_gatekeeper-manager-role.yaml"
+	ClusterRoleBindingFile             = "rbac.authorization.k8s.io_v1_clusterrolebinding_gatekeeper-manager-rolebinding.yaml"
+	RoleFile                           = "rbac.authorization.k8s.io_v1_role_gatekeeper-manager-role.yaml"
+	RoleBindingFile                    = "rbac.authorization.k8s.io_v1_rolebinding_gatekeeper-manager-rolebinding.yaml"
+	ServerCertFile                     = "v1_secret_gatekeeper-webhook-server-cert.yaml"
+	ServiceAccountFile                 = "v1_serviceaccount_gatekeeper-admin.yaml"
+	PodSecurityPolicyFile              = "policy_v1beta1_podsecuritypolicy_gatekeeper-admin.yaml"
+	AuditPodDisruptionBudgetFile       = "policy_v1beta1_poddisruptionbudget_gatekeeper-audit.yaml"
+	WebhookPodDisruptionBudgetFile     = "policy_v1beta1_poddisruptionbudget_gatekeeper-controller-manager.yaml"
+	ServiceFile                        = "v1_service_gatekeeper-webhook-service.yaml"
+	ValidatingWebhookConfiguration     = "admissionregistration.k8s.io_v1beta1_validatingwebhookconfiguration_gatekeeper-validating-webhook-configuration.yaml"
+	MutatingWebhookConfiguration       = "admissionregistration.k8s.io_v1beta1_mutatingwebhookconfiguration_gatekeeper-mutating-webhook-configuration.yaml"
+	ConfigFile                         = "config.gatekeeper.sh_v1alpha1_config_config.yaml"
+	IssuerFile                         = certManagerAssetsDir + "cert-manager.io_v1_issuer_gatekeeper-selfsigned-issuer.yaml"
+	CertificateFile                    = certManagerAssetsDir + "cert-manager.io_v1_certificate_gatekeeper-serving-cert.yaml"
+	ValidationGatekeeperWebhook        = "validation.gatekeeper.sh"
+	MutationGatekeeperWebhook          = "mutation.gatekeeper.sh"
+	CheckIgnoreLabelGatekeeperWebhook  = "check-ignore-label.gatekeeper.sh"
+	fieldManager                       = "gatekeeper-operator"
+	managerContainer                   = "manager"
+	LogLevelArg                        = "--log-level"
+	AuditIntervalArg                   = "--audit-interval"
+	ConstraintViolationLimitArg        = "--constraint-violations-limit"
+	AuditFromCacheArg                  = "--audit-from-cache"
+	AuditChunkSizeArg                  = "--audit-chunk-size"
+	EmitAuditEventsArg                 = "--emit-audit-events"
+	EmitAdmissionEventsArg             = "--emit-admission-events"
+	ExemptNamespaceArg                 = "--exempt-namespace"
+	EnableMutationArg                  = "--enable-mutation"
+	DisableCertRotationArg             = "--disable-cert-rotation"
 )
 
 var (
 	orderedStaticAssets = []string{
 		NamespaceFile,
-		"apiextensions.k8s.io_v1beta1_customresourcedefinition_configs.config.gatekeeper.sh.yaml",
-		"apiextensions.k8s.io_v1beta1_customresourcedefinition_constrainttemplates.templates.gatekeeper.sh.yaml",
-		"apiextensions.k8s.io_v1beta1_customresourcedefinition_constrainttemplatepodstatuses.status.gatekeeper.sh.yaml",
-		"apiextensions.k8s.io_v1beta1_customresourcedefinition_constraintpodstatuses.status.gatekeeper.sh.yaml",
+		ConfigCRDFile,
+		ConstraintTemplateCRDFile,
+		ConstraintTemplatePodStatusCRDFile,
+		ConstraintPodStatusCRDFile,
 		AssignCRDFile,
 		AssignMetadataCRDFile,
 		ServerCertFile,
-		"v1_serviceaccount_gatekeeper-admin.yaml",
-		"policy_v1beta1_podsecuritypolicy_gatekeeper-admin.yaml",
+		ServiceAccountFile,
+		PodSecurityPolicyFile,
 		ClusterRoleFile,
 		ClusterRoleBindingFile,
 		RoleFile,
 		RoleBindingFile,
 		AuditFile,
 		WebhookFile,
-		"v1_service_gatekeeper-webhook-service.yaml",
+		AuditPodDisruptionBudgetFile,
+		WebhookPodDisruptionBudgetFile,
+		ServiceFile,
 		ValidatingWebhookConfiguration,
 		MutatingWebhookConfiguration,
 	}
@@ -97,15 +128,45 @@ var (
 		AssignMetadataCRDFile,
 		MutatingWebhookConfiguration,
 	}
+	mutatingCRDStaticAssets = []string{
+		AssignCRDFile,
+		AssignMetadataCRDFile,
+	}
+	certManagerStaticAssets = []string{
+		IssuerFile,
+		CertificateFile,
+	}
+	podDisruptionBudgetStaticAssets = []string{
+		AuditPodDisruptionBudgetFile,
+		WebhookPodDisruptionBudgetFile,
+	}
 )
 
 // GatekeeperReconciler reconciles a Gatekeeper object
 type GatekeeperReconciler struct {
 	client.Client
-	Log          logr.Logger
-	Scheme       *runtime.Scheme
-	Namespace    string
-	PlatformName util.PlatformType
+	APIReader         client.Reader
+	Log               logr.Logger
+	Scheme            *runtime.Scheme
+	Namespace         string
+	OperatorNamespace string
+	PlatformName      util.PlatformType
+	APIVersions       APIVersions
+	Recorder          record.EventRecorder
+	// DefaultImage replaces the image of the manifests of the default
+	// Gatekeeper version, e.g. with the mirrored image set by OLM in
+	// RELATED_IMAGE_GATEKEEPER.
+	DefaultImage string
+
+	controller     controller.Controller
+	mgr            ctrl.Manager
+	metadataClient metadata.Interface
+	configWatched  bool
+	// secretWatches records the namespaces whose Secrets are watched.
+	secretWatches map[string]bool
+	// cache reads the Gatekeeper resource from the informer cache when
+	// filtering the events of the watched resources.
+	cache client.Reader
 }
 
 type crudOperation uint32
@@ -126,12 +187,14 @@ const (
 
 // Cluster Scoped
 // +kubebuilder:rbac:groups=*,resources=*,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=update;patch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=config.gatekeeper.sh,resources=configs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=config.gatekeeper.sh,resources=configs/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=constraints.gatekeeper.sh,resources=*,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=mutations.gatekeeper.sh,resources=*,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=policy,resources=podsecuritypolicies,verbs=create;delete;update;use
+// +kubebuilder:rbac:groups=policy,resources=podsecuritypolicies,verbs=create;delete;patch;update;use
 // +kubebuilder:rbac:groups=status.gatekeeper.sh,resources=*,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=templates.gatekeeper.sh,resources=constrainttemplates,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=templates.gatekeeper.sh,resources=constrainttemplates/finalizers,verbs=get;update;patch;delete
@@ -144,6 +207,9 @@ const (
 // +kubebuilder:rbac:groups=core,namespace="system",resources=secrets;serviceaccounts;services,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,namespace="system",resources=roles;rolebindings,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps,namespace="system",resources=deployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps,namespace="system",resources=replicasets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=policy,namespace="system",resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=cert-manager.io,namespace="system",resources=issuers;certificates,verbs=get;list;watch;create;update;patch;delete
 
 func (r *GatekeeperReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	ctx := context.Background()
@@ -168,45 +234,289 @@ func (r *GatekeeperReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 		return ctrl.Result{}, err
 	}
 
-	if err = r.deployGatekeeperResources(gatekeeper); err != nil {
+	if !gatekeeper.GetDeletionTimestamp().IsZero() {
+		return r.uninstall(ctx, gatekeeper, logger)
+	}
+
+	if !controllerutil.ContainsFinalizer(gatekeeper, gatekeeperFinalizer) {
+		controllerutil.AddFinalizer(gatekeeper, gatekeeperFinalizer)
+		if err = r.Update(ctx, gatekeeper); err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "Unable to add finalizer to Gatekeeper")
+		}
+	}
+
+	switch getManagementState(gatekeeper.Spec) {
+	case operatorv1alpha1.ManagementStateUnmanaged:
+		return r.reconcileUnmanaged(ctx, gatekeeper, logger)
+	case operatorv1alpha1.ManagementStateRemoved:
+		return r.reconcileRemoved(ctx, gatekeeper, logger)
+	}
+
+	version := getGatekeeperVersion(gatekeeper.Spec)
+	supported, versions, err := isSupportedVersion(version)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !supported {
+		logger.Info("Unsupported Gatekeeper version", "version", version, "supportedVersions", versions)
+		// Return success to avoid requeue until the version is changed.
+		return ctrl.Result{}, r.updateUnsupportedVersionStatus(ctx, gatekeeper, version, versions)
+	}
+
+	certificates, rotateAt, err := r.reconcileCertificates(ctx, gatekeeper)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	r.startUpgrade(gatekeeper)
+	written, err := r.deployGatekeeperResources(gatekeeper, certificates)
+	if err != nil {
 		return ctrl.Result{}, errors.Wrap(err, "Unable to deploy Gatekeeper resources")
 	}
+	upgrading, err := r.progressUpgrade(ctx, gatekeeper, written)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	pinning, err := r.updateImageDigests(ctx, gatekeeper)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
 
-	return ctrl.Result{}, nil
+	if err = r.reconcilePodSecurityLabels(ctx, getPodSecurityLevel(gatekeeper.Spec)); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	exemptNamespaces := uniqueNamespaces(r.getRequiredExemptNamespaces(), getWebhookExemptNamespaces(gatekeeper.Spec.Webhook))
+	if err = r.reconcileExemptNamespaceLabels(ctx, exemptNamespaces); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	gatekeeper.Status.Certificates = getCertificatesStatus(gatekeeper, certificates)
+	gatekeeper.Status.APIVersions = r.APIVersions.list()
+	gatekeeper.Status.Version = getGatekeeperVersion(getRenderedGatekeeper(gatekeeper, WebhookFile).Spec)
+	ready, err := r.updateStatus(ctx, gatekeeper)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	result := ctrl.Result{}
+	if !ready {
+		logger.Info("Gatekeeper is not ready yet, checking status again later")
+		result.RequeueAfter = notReadyRequeueInterval
+	} else if upgrading {
+		logger.Info("Gatekeeper is being upgraded, checking upgrade again later")
+		result.RequeueAfter = notReadyRequeueInterval
+	} else if pinning {
+		logger.Info("Gatekeeper image digests recorded, pinning images")
+		result.RequeueAfter = notReadyRequeueInterval
+	}
+	if !rotateAt.IsZero() {
+		// Reconcile again when the webhook certificates are due for
+		// rotation.
+		if untilRotation := time.Until(rotateAt); result.RequeueAfter == 0 || untilRotation < result.RequeueAfter {
+			result.RequeueAfter = untilRotation
+		}
+	}
+
+	return result, nil
 }
 
 func (r *GatekeeperReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&operatorv1alpha1.Gatekeeper{}).
-		WithEventFilter(predicate.Funcs{
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&operatorv1alpha1.Gatekeeper{}, builder.WithPredicates(predicate.Funcs{
 			UpdateFunc: func(e event.UpdateEvent) bool {
 				oldGeneration := e.MetaOld.GetGeneration()
 				newGeneration := e.MetaNew.GetGeneration()
+				deleted := e.MetaOld.GetDeletionTimestamp().IsZero() &&
+					!e.MetaNew.GetDeletionTimestamp().IsZero()
 
-				return oldGeneration != newGeneration
+				return oldGeneration != newGeneration || deleted
 			},
 			DeleteFunc: func(e event.DeleteEvent) bool {
 
 				return false
 			},
-		}).
-		Complete(r)
+		}))
+
+	ownedTypes, err := r.getOwnedTypes()
+	if err != nil {
+		return err
+	}
+	// Watch every resource deployed by the operator so that any drift from
+	// the desired state triggers a reconcile that restores it. The Secrets
+	// and namespaces are watched by watchSecrets and watchNamespaces.
+	for _, t := range ownedTypes {
+		switch t.GetObjectKind().GroupVersionKind().Kind {
+		case util.SecretKind, util.NamespaceKind:
+			continue
+		}
+		b = b.Owns(t)
+	}
+
+	r.cache = mgr.GetCache()
+	r.mgr = mgr
+	r.metadataClient, err = metadata.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return errors.Wrapf(err, "Unable to create metadata client")
+	}
+
+	r.controller, err = b.Build(r)
+	if err != nil {
+		return err
+	}
+	if err = r.watchNamespaces(); err != nil {
+		return err
+	}
+	return r.watchSecrets(r.Namespace)
 }
 
-func (r *GatekeeperReconciler) deployGatekeeperResources(gatekeeper *operatorv1alpha1.Gatekeeper) error {
+// gatekeeperRequests maps the events of a watched resource to a reconcile
+// request for the Gatekeeper resource.
+func gatekeeperRequests(handler.MapObject) []reconcile.Request {
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: defaultGatekeeperCrName}}}
+}
+
+// getCachedGatekeeper returns the Gatekeeper resource from the informer
+// cache, so that filtering watch events does not query the API server.
+func (r *GatekeeperReconciler) getCachedGatekeeper() (*operatorv1alpha1.Gatekeeper, bool) {
+	gatekeeper := &operatorv1alpha1.Gatekeeper{}
+	err := r.cache.Get(context.Background(), types.NamespacedName{Name: defaultGatekeeperCrName}, gatekeeper)
+	return gatekeeper, err == nil
+}
+
+// watchConfig watches the Gatekeeper Config resources of the given kind so
+// that drift from the desired state triggers a reconcile. The watch can only
+// be started once the operator has installed the Config CRD.
+func (r *GatekeeperReconciler) watchConfig(gvk schema.GroupVersionKind) error {
+	if r.controller == nil || r.configWatched {
+		return nil
+	}
+	config := &unstructured.Unstructured{}
+	config.SetGroupVersionKind(gvk)
+	err := r.controller.Watch(&source.Kind{Type: config}, &handler.EnqueueRequestForOwner{
+		OwnerType:    &operatorv1alpha1.Gatekeeper{},
+		IsController: true,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "Unable to watch %s resources", gvk.Kind)
+	}
+	r.configWatched = true
+	return nil
+}
+
+// watchMetadata returns a source of the events of the given resource in the
+// given namespace, or in all namespaces when empty. Only the metadata of the
+// objects is cached, e.g. not the data of Secrets. Its informer is run by the
+// manager.
+func (r *GatekeeperReconciler) watchMetadata(gvr schema.GroupVersionResource, namespace string) (source.Source, error) {
+	resource := r.metadataClient.Resource(gvr).Namespace(namespace)
+	informer := toolscache.NewSharedIndexInformer(&toolscache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			return resource.List(context.Background(), opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			return resource.Watch(context.Background(), opts)
+		},
+	}, &metav1.PartialObjectMetadata{}, 0, toolscache.Indexers{})
+	err := r.mgr.Add(manager.RunnableFunc(func(stop <-chan struct{}) error {
+		informer.Run(stop)
+		return nil
+	}))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Unable to watch %s", gvr.Resource)
+	}
+	return &source.Informer{Informer: informer}, nil
+}
+
+// getOwnedTypes returns an object for each distinct kind of static asset
+// deployed by the operator in any of the bundled Gatekeeper versions.
+func (r *GatekeeperReconciler) getOwnedTypes() ([]runtime.Object, error) {
+	versions, err := util.GetGatekeeperVersions()
+	if err != nil {
+		return nil, err
+	}
+	assets := r.getServedAssets(orderedStaticAssets)
+	seen := make(map[schema.GroupVersionKind]bool)
+	ownedTypes := make([]runtime.Object, 0)
+	for _, version := range versions {
+		for _, a := range assets {
+			obj, err := r.getVersionedManifestObject(version, a)
+			if err != nil {
+				return nil, err
+			}
+			gvk := obj.GroupVersionKind()
+			if seen[gvk] {
+				continue
+			}
+			seen[gvk] = true
+
+			owned := &unstructured.Unstructured{}
+			owned.SetGroupVersionKind(gvk)
+			ownedTypes = append(ownedTypes, owned)
+		}
+	}
+	return ownedTypes, nil
+}
+
+// deployGatekeeperResources renders and applies the Gatekeeper resources. It
+// returns the assets whose cluster objects were written.
+func (r *GatekeeperReconciler) deployGatekeeperResources(gatekeeper *operatorv1alpha1.Gatekeeper, certificates *certs.Certificates) (map[string]bool, error) {
 	deleteAssets, applyAssets := getStaticAssets(gatekeeper)
+	applyAssets = r.getServedAssets(applyAssets)
+	// Conflicts are recorded again as the resources are applied.
+	gatekeeper.Status.Conflicts = nil
+
+	disabledBudgets, degraded, err := r.getDisabledPodDisruptionBudgets(context.Background(), gatekeeper)
+	if err != nil {
+		return nil, err
+	}
+	deleteAssets = append(deleteAssets, disabledBudgets...)
+	applyAssets = getSubsetOfAssets(applyAssets, disabledBudgets...)
+	if degraded == nil {
+		gatekeeper.Status.DegradedConditions = nil
+	} else {
+		r.Log.Info("Skipping PodDisruptionBudgets that would block every eviction", "message", degraded.Message)
+		gatekeeper.Status.DegradedConditions = setStatusCondition(gatekeeper.Status.DegradedConditions, *degraded, metav1.Now())
+	}
+
+	if getCertificatesMode(gatekeeper.Spec.Webhook) == operatorv1alpha1.CertificatesCertManager {
+		installed, err := r.isCertManagerInstalled(context.Background(), gatekeeper)
+		if err != nil {
+			return nil, err
+		}
+		if !installed {
+			// Deploy the rest of Gatekeeper and report the missing
+			// cert-manager CRDs in the webhook status instead.
+			r.Log.Info("cert-manager is not installed, skipping the webhook certificate resources")
+			applyAssets = getSubsetOfAssets(applyAssets, certManagerStaticAssets...)
+		}
+	}
 
 	for _, d := range deleteAssets {
-		obj, err := util.GetManifestObject(d)
+		obj, err := r.getVersionedManifestObject(getManifestVersion(gatekeeper, d), d)
 		if err != nil {
-			return nil
+			return nil, err
+		}
+		if obj.GetNamespace() != "" {
+			obj.SetNamespace(r.Namespace)
 		}
 
-		if err = r.crudResource(obj, gatekeeper, delete); err != nil {
-			return err
+		if _, err = r.crudResource(obj, gatekeeper, delete); err != nil {
+			return nil, err
+		}
+	}
+
+	if !configManaged(gatekeeper.Spec) {
+		// Stop owning a Config resource previously rendered by the operator
+		// so that it is left untouched, e.g. when the Gatekeeper resource is
+		// deleted.
+		err := r.orphanAssets(context.Background(), gatekeeper, []string{ConfigFile})
+		if err != nil && !meta.IsNoMatchError(errors.Cause(err)) {
+			return nil, err
 		}
 	}
 
+	written := make(map[string]bool)
 	for _, a := range applyAssets {
 		// Handle special cases in switch below.
 		switch {
@@ -215,23 +525,68 @@ func (r *GatekeeperReconciler) deployGatekeeperResources(gatekeeper *operatorv1a
 			// the same namespace as the operator, which by definition is
 			// already created as a result of executing this code.
 			continue
-		case a == RoleFile && r.isOpenShift():
-			a = openshiftAssetsDir + a
 		}
 
-		obj, err := util.GetManifestObject(a)
+		// The assets are rendered from the spec they are at in the current
+		// upgrade.
+		rendered := getRenderedGatekeeper(gatekeeper, a)
+		version := getGatekeeperVersion(rendered.Spec)
+		obj, err := r.getVersionedManifestObject(version, a)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		if err = crOverrides(gatekeeper, a, obj, r.Namespace, r.isOpenShift()); err != nil {
-			return err
+		if err = r.setDefaultImage(obj, version, a); err != nil {
+			return nil, err
+		}
+		if err = crOverrides(rendered, a, obj, r.Namespace, r.isOpenShift()); err != nil {
+			return nil, err
+		}
+		if err = setStatusImages(gatekeeper, a, obj); err != nil {
+			return nil, err
+		}
+		if err = certificateOverrides(getCertificatesMode(gatekeeper.Spec.Webhook), version, a, obj, r.Namespace, certificates); err != nil {
+			return nil, err
+		}
+		if err = exemptNamespaceOverrides(a, obj, gatekeeper.Spec, r.getRequiredExemptNamespaces()); err != nil {
+			return nil, err
+		}
+		if a == RoleFile && r.isOpenShift() {
+			scc, err := r.getSecurityContextConstraints(context.Background(), getPodSecurityLevel(rendered.Spec))
+			if err != nil {
+				return nil, err
+			}
+			if err = setSecurityContextConstraints(obj, scc); err != nil {
+				return nil, err
+			}
+		}
+		if a == ClusterRoleFile && r.APIVersions.PodSecurityPolicyUnserved {
+			if err = removeRBACRule(obj, matchPodSecurityPolicyRBACRule); err != nil {
+				return nil, err
+			}
 		}
 
-		if err = r.crudResource(obj, gatekeeper, apply); err != nil {
-			return err
+		written[a], err = r.crudResource(obj, gatekeeper, apply, getReleasedFields(a, rendered.Spec)...)
+		if err != nil {
+			return nil, err
+		}
+		if a == ConfigFile {
+			if err = r.watchConfig(obj.GroupVersionKind()); err != nil {
+				return nil, err
+			}
 		}
 	}
-	return nil
+
+	applied := 0
+	for _, w := range written {
+		if w {
+			applied++
+		}
+	}
+	skipped := len(written) - applied
+	appliedResources.Add(float64(applied))
+	skippedResources.Add(float64(skipped))
+	r.Log.Info("Applied Gatekeeper resources", "Applied", applied, "Skipped", skipped)
+	return written, nil
 }
 
 func getStaticAssets(gatekeeper *operatorv1alpha1.Gatekeeper) (deleteAssets, applyAssets []string) {
@@ -250,10 +605,37 @@ func getStaticAssets(gatekeeper *operatorv1alpha1.Gatekeeper) (deleteAssets, app
 		applyAssets = getSubsetOfAssets(applyAssets, ValidatingWebhookConfiguration)
 	}
 
+	switch getCertificatesMode(gatekeeper.Spec.Webhook) {
+	case operatorv1alpha1.CertificatesServiceCA:
+		// The webhook server certificate secret is created by the service CA
+		applyAssets = getSubsetOfAssets(applyAssets, ServerCertFile)
+	case operatorv1alpha1.CertificatesCertManager:
+		// The webhook server certificate secret is created by cert-manager
+		// from the Issuer and Certificate resources.
+		applyAssets = replaceAsset(applyAssets, ServerCertFile, certManagerStaticAssets...)
+	default:
+		// Only look for the cert-manager resources when switching away from
+		// cert-manager, as their CRDs are usually not installed.
+		if gatekeeper.Status.Certificates != nil &&
+			gatekeeper.Status.Certificates.Mode == operatorv1alpha1.CertificatesCertManager {
+			deleteAssets = append(deleteAssets, certManagerStaticAssets...)
+		}
+	}
+
+	if configManaged(gatekeeper.Spec) {
+		// Applied last so that the Config CRD is installed by then.
+		applyAssets = append(applyAssets, ConfigFile)
+	}
+
 	if !mutatingWebhookEnabled {
-		// Remove mutating resources
-		deleteAssets = append(deleteAssets, mutatingStaticAssets...)
+		// Remove MutatingWebhookConfiguration resource and stop managing the
+		// mutating CRDs. The CRDs are only removed on request as doing so
+		// deletes all Assign and AssignMetadata resources.
+		deleteAssets = append(deleteAssets, MutatingWebhookConfiguration)
 		applyAssets = getSubsetOfAssets(applyAssets, mutatingStaticAssets...)
+		if mutationCRDsDeleteEnabled(gatekeeper.Spec.MutationCRDsPolicy) {
+			deleteAssets = append(deleteAssets, mutatingCRDStaticAssets...)
+		}
 	}
 
 	return
@@ -263,6 +645,10 @@ func mutatingWebhookEnabled(mode *operatorv1alpha1.WebhookMode) bool {
 	return mode != nil && *mode == operatorv1alpha1.WebhookEnabled
 }
 
+func mutationCRDsDeleteEnabled(policy *operatorv1alpha1.MutationCRDsPolicy) bool {
+	return policy != nil && *policy == operatorv1alpha1.MutationCRDsDelete
+}
+
 func getSubsetOfAssets(inputAssets []string, assetsToRemove ...string) []string {
 	outputAssets := make([]string, 0)
 	for _, i := range inputAssets {
@@ -279,7 +665,26 @@ func getSubsetOfAssets(inputAssets []string, assetsToRemove ...string) []string
 	return outputAssets
 }
 
-func (r *GatekeeperReconciler) crudResource(obj *unstructured.Unstructured, gatekeeper *operatorv1alpha1.Gatekeeper, operation crudOperation) error {
+// replaceAsset returns a copy of the given assets with the asset to replace
+// substituted in place by the replacement assets.
+func replaceAsset(inputAssets []string, assetToReplace string, replacementAssets ...string) []string {
+	outputAssets := make([]string, 0)
+	for _, i := range inputAssets {
+		if i == assetToReplace {
+			outputAssets = append(outputAssets, replacementAssets...)
+			continue
+		}
+		outputAssets = append(outputAssets, i)
+	}
+	return outputAssets
+}
+
+// crudResource applies or deletes the given Gatekeeper resource and returns
+// whether it was written. Resources that are up to date are not applied. The
+// released fields are left out of the applied resource while another field
+// manager manages them.
+func (r *GatekeeperReconciler) crudResource(obj *unstructured.Unstructured, gatekeeper *operatorv1alpha1.Gatekeeper, operation crudOperation,
+	releasedFields ...[]string) (bool, error) {
 	var err error
 	ctx := context.Background()
 	clusterObj := &unstructured.Unstructured{}
@@ -295,7 +700,12 @@ func (r *GatekeeperReconciler) crudResource(obj *unstructured.Unstructured, gate
 
 	err = ctrl.SetControllerReference(gatekeeper, obj, r.Scheme)
 	if err != nil {
-		return errors.Wrapf(err, "Unable to set controller reference for %s", namespacedName)
+		return false, errors.Wrapf(err, "Unable to set controller reference for %s", namespacedName)
+	}
+	if operation == apply {
+		if err = setRenderedHash(obj); err != nil {
+			return false, err
+		}
 	}
 
 	err = r.Get(ctx, namespacedName, clusterObj)
@@ -303,36 +713,104 @@ func (r *GatekeeperReconciler) crudResource(obj *unstructured.Unstructured, gate
 	switch {
 	case err == nil:
 		if operation == apply {
-			err = merge.RetainClusterObjectFields(obj, clusterObj)
+			err = merge.ReleaseForeignFields(obj, clusterObj, fieldManager, releasedFields...)
 			if err != nil {
-				return errors.Wrapf(err, "Unable to retain cluster object fields from %s", namespacedName)
+				return false, errors.Wrapf(err, "Unable to release fields of %s", namespacedName)
+			}
+
+			if isUpToDate(obj, clusterObj) {
+				logger.V(1).Info("Skipped up to date Gatekeeper resource")
+				return false, nil
+			}
+
+			if err = r.applyResource(ctx, obj, gatekeeper); err != nil {
+				return false, errors.Wrapf(err, "Error attempting to update resource %s", namespacedName)
 			}
 
-			if err = r.Update(ctx, obj); err != nil {
-				return errors.Wrapf(err, "Error attempting to update resource %s", namespacedName)
+			// Applying a resource that is up to date leaves its resource
+			// version unchanged.
+			if obj.GetResourceVersion() == clusterObj.GetResourceVersion() {
+				logger.V(1).Info("Applied unchanged Gatekeeper resource")
+				return false, nil
 			}
 
 			logger.Info(fmt.Sprintf("Updated Gatekeeper resource"))
+			r.recordDriftCorrection(gatekeeper, obj, logger)
 		} else if operation == delete {
 			if err = r.Delete(ctx, obj); err != nil {
-				return errors.Wrapf(err, "Error attempting to delete resource %s", namespacedName)
+				return false, errors.Wrapf(err, "Error attempting to delete resource %s", namespacedName)
 			}
 			logger.Info(fmt.Sprintf("Deleted Gatekeeper resource"))
 		}
+		return true, nil
 
-	case apierrors.IsNotFound(err):
+	case apierrors.IsNotFound(err) || (operation == delete && meta.IsNoMatchError(err)):
+		// There is nothing to delete when the resource kind is not installed.
 		if operation == apply {
-			if err = r.Create(ctx, obj); err != nil {
-				return errors.Wrapf(err, "Error attempting to create resource %s", namespacedName)
+			if err = r.applyResource(ctx, obj, gatekeeper); err != nil {
+				return false, errors.Wrapf(err, "Error attempting to create resource %s", namespacedName)
 			}
 			logger.Info(fmt.Sprintf("Created Gatekeeper resource"))
+			return true, nil
 		}
 
 	case err != nil:
-		return errors.Wrapf(err, "Error attempting to get resource %s", namespacedName)
+		return false, errors.Wrapf(err, "Error attempting to get resource %s", namespacedName)
 	}
 
-	return nil
+	return false, nil
+}
+
+// applyResource applies the given Gatekeeper resource with server-side apply
+// so that only the fields rendered by the operator are owned by it, leaving
+// fields set by other controllers untouched. Rendered fields managed by
+// another field manager are taken over and the conflict is recorded in the
+// Gatekeeper status.
+func (r *GatekeeperReconciler) applyResource(ctx context.Context, obj *unstructured.Unstructured, gatekeeper *operatorv1alpha1.Gatekeeper) error {
+	// The status is not part of the desired state of a resource.
+	unstructured.RemoveNestedField(obj.Object, "status")
+
+	err := r.Patch(ctx, obj, client.Apply, client.FieldOwner(fieldManager))
+	if !apierrors.IsConflict(err) {
+		return err
+	}
+
+	r.Log.Info("Taking over conflicting fields of Gatekeeper resource", "Kind", obj.GetKind(),
+		"Namespace", obj.GetNamespace(), "Name", obj.GetName(), "Conflict", err.Error())
+	gatekeeper.Status.Conflicts = append(gatekeeper.Status.Conflicts, operatorv1alpha1.ApplyConflict{
+		Kind:      obj.GetKind(),
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		Message:   err.Error(),
+	})
+	return r.Patch(ctx, obj, client.Apply, client.FieldOwner(fieldManager), client.ForceOwnership)
+}
+
+// getReleasedFields returns the fields of the resource rendered from the
+// given asset that are released to other field managers, as the Gatekeeper
+// spec does not set them, e.g. the replicas of a Deployment scaled by a
+// HorizontalPodAutoscaler. The operator keeps applying the value of the
+// manifests until another field manager takes a field over, so that unsetting
+// the field in the Gatekeeper spec restores the value of the manifests.
+func getReleasedFields(asset string, spec operatorv1alpha1.GatekeeperSpec) [][]string {
+	switch {
+	case asset == AuditFile && (spec.Audit == nil || spec.Audit.Replicas == nil),
+		asset == WebhookFile && (spec.Webhook == nil || spec.Webhook.Replicas == nil):
+		return [][]string{{"spec", "replicas"}}
+	default:
+		return nil
+	}
+}
+
+// recordDriftCorrection counts a write to a Gatekeeper resource as a drift
+// correction when the current Gatekeeper spec had already been applied, i.e.
+// the resource was changed or deleted outside of the operator.
+func (r *GatekeeperReconciler) recordDriftCorrection(gatekeeper *operatorv1alpha1.Gatekeeper, obj *unstructured.Unstructured, logger logr.Logger) {
+	if gatekeeper.Status.ObservedGeneration != gatekeeper.GetGeneration() {
+		return
+	}
+	driftCorrections.WithLabelValues(obj.GetKind()).Inc()
+	logger.Info("Corrected drift of Gatekeeper resource")
 }
 
 func (r *GatekeeperReconciler) isOpenShift() bool {
@@ -340,14 +818,11 @@ func (r *GatekeeperReconciler) isOpenShift() bool {
 }
 
 var commonSpecOverridesFn = []func(*unstructured.Unstructured, operatorv1alpha1.GatekeeperSpec) error{
-	setAffinity,
-	setNodeSelector,
-	setPodAnnotations,
-	setTolerations,
+	setImagePullSecrets,
 	containerOverrides,
 }
 var commonContainerOverridesFn = []func(map[string]interface{}, operatorv1alpha1.GatekeeperSpec) error{
-	setImage,
+	setImagePullPolicy,
 }
 
 // crOverrides
@@ -369,6 +844,24 @@ func crOverrides(gatekeeper *operatorv1alpha1.Gatekeeper, asset string, obj *uns
 		if err := auditOverrides(obj, gatekeeper.Spec.Audit); err != nil {
 			return err
 		}
+		if err := setScheduling(obj, getScheduling(gatekeeper.Spec, getAuditScheduling(gatekeeper.Spec.Audit))); err != nil {
+			return err
+		}
+		if err := setPodAnnotations(obj, getPodAnnotations(gatekeeper.Spec, getAuditPodAnnotations(gatekeeper.Spec.Audit))); err != nil {
+			return err
+		}
+		if err := setComponentImage(obj, gatekeeper.Spec, asset); err != nil {
+			return err
+		}
+		if err := setRegistryOverride(obj, gatekeeper.Spec.Image); err != nil {
+			return err
+		}
+		if err := pinComponentImage(obj, gatekeeper); err != nil {
+			return err
+		}
+		if err := setPodSecurityContext(obj, getPodSecurityLevel(gatekeeper.Spec), isOpenshift); err != nil {
+			return err
+		}
 		if isOpenshift {
 			if err := removeAnnotations(obj); err != nil {
 				return err
@@ -382,6 +875,24 @@ func crOverrides(gatekeeper *operatorv1alpha1.Gatekeeper, asset string, obj *uns
 		if err := webhookOverrides(obj, gatekeeper.Spec.Webhook); err != nil {
 			return err
 		}
+		if err := setScheduling(obj, getScheduling(gatekeeper.Spec, getWebhookScheduling(gatekeeper.Spec.Webhook))); err != nil {
+			return err
+		}
+		if err := setPodAnnotations(obj, getPodAnnotations(gatekeeper.Spec, getWebhookPodAnnotations(gatekeeper.Spec.Webhook))); err != nil {
+			return err
+		}
+		if err := setComponentImage(obj, gatekeeper.Spec, asset); err != nil {
+			return err
+		}
+		if err := setRegistryOverride(obj, gatekeeper.Spec.Image); err != nil {
+			return err
+		}
+		if err := pinComponentImage(obj, gatekeeper); err != nil {
+			return err
+		}
+		if err := setPodSecurityContext(obj, getPodSecurityLevel(gatekeeper.Spec), isOpenshift); err != nil {
+			return err
+		}
 		if isOpenshift {
 			if err := removeAnnotations(obj); err != nil {
 				return err
@@ -392,6 +903,16 @@ func crOverrides(gatekeeper *operatorv1alpha1.Gatekeeper, asset string, obj *uns
 				return err
 			}
 		}
+		if getCertificatesMode(gatekeeper.Spec.Webhook) != operatorv1alpha1.CertificatesGatekeeper {
+			if err := setDisableCertRotation(obj); err != nil {
+				return err
+			}
+		}
+	// PodDisruptionBudget overrides
+	case AuditPodDisruptionBudgetFile, WebhookPodDisruptionBudgetFile:
+		if err := setPodDisruptionBudget(obj, getPodDisruptionBudget(gatekeeper.Spec, asset)); err != nil {
+			return err
+		}
 	// ValidatingWebhookConfiguration overrides
 	case ValidatingWebhookConfiguration:
 		if err := webhookConfigurationOverrides(obj, gatekeeper.Spec.Webhook, ValidationGatekeeperWebhook); err != nil {
@@ -399,7 +920,24 @@ func crOverrides(gatekeeper *operatorv1alpha1.Gatekeeper, asset string, obj *uns
 		}
 	// MutatingWebhookConfiguration overrides
 	case MutatingWebhookConfiguration:
-		if err := webhookConfigurationOverrides(obj, gatekeeper.Spec.Webhook, MutationGatekeeperWebhook); err != nil {
+		mutating := operatorv1alpha1.GetMutatingWebhookConfig(&gatekeeper.Spec)
+		if err := mutatingWebhookConfigurationOverrides(obj, &mutating); err != nil {
+			return err
+		}
+		// The match policy cannot be set for the mutating webhook only.
+		if gatekeeper.Spec.Webhook != nil {
+			if err := setMatchPolicy(obj, gatekeeper.Spec.Webhook.MatchPolicy, MutationGatekeeperWebhook); err != nil {
+				return err
+			}
+		}
+	// cert-manager Certificate overrides
+	case CertificateFile:
+		if err := setCertManagerCertificate(obj, getGatekeeperVersion(gatekeeper.Spec), gatekeeper.Spec.Webhook, namespace); err != nil {
+			return err
+		}
+	// Config overrides
+	case ConfigFile:
+		if err := setConfigSpec(obj, gatekeeper.Spec.Config); err != nil {
 			return err
 		}
 	// ClusterRole overrides
@@ -478,6 +1016,42 @@ func webhookConfigurationOverrides(obj *unstructured.Unstructured, webhook *oper
 		if err := setNamespaceSelector(obj, webhook.NamespaceSelector, webhookName); err != nil {
 			return err
 		}
+		if err := setObjectSelector(obj, webhook.ObjectSelector, webhookName); err != nil {
+			return err
+		}
+		if err := setWebhookRules(obj, webhook.Rules, webhookName); err != nil {
+			return err
+		}
+		if err := setTimeoutSeconds(obj, webhook.TimeoutSeconds, webhookName); err != nil {
+			return err
+		}
+		if err := setMatchPolicy(obj, webhook.MatchPolicy, webhookName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func mutatingWebhookConfigurationOverrides(obj *unstructured.Unstructured, webhook *operatorv1alpha1.MutatingWebhookConfig) error {
+	if webhook != nil {
+		if err := setFailurePolicy(obj, webhook.FailurePolicy, MutationGatekeeperWebhook); err != nil {
+			return err
+		}
+		if err := setNamespaceSelector(obj, webhook.NamespaceSelector, MutationGatekeeperWebhook); err != nil {
+			return err
+		}
+		if err := setObjectSelector(obj, webhook.ObjectSelector, MutationGatekeeperWebhook); err != nil {
+			return err
+		}
+		if err := setTimeoutSeconds(obj, webhook.TimeoutSeconds, MutationGatekeeperWebhook); err != nil {
+			return err
+		}
+		if err := setReinvocationPolicy(obj, webhook.ReinvocationPolicy, MutationGatekeeperWebhook); err != nil {
+			return err
+		}
+		if err := setWebhookRules(obj, webhook.Rules, MutationGatekeeperWebhook); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -631,6 +1205,10 @@ func setEnableMutation(obj *unstructured.Unstructured) error {
 	return setContainerArg(obj, managerContainer, EnableMutationArg, "true")
 }
 
+func setDisableCertRotation(obj *unstructured.Unstructured) error {
+	return setContainerArg(obj, managerContainer, DisableCertRotationArg, "true")
+}
+
 func setWebhookConfigurationWithFn(obj *unstructured.Unstructured, webhookName string, webhookFn func(map[string]interface{}) error) error {
 	webhooks, found, err := unstructured.NestedSlice(obj.Object, "webhooks")
 	if err != nil || !found {
@@ -665,6 +1243,16 @@ func setFailurePolicy(obj *unstructured.Unstructured, failurePolicy *admregv1.Fa
 	return setWebhookConfigurationWithFn(obj, webhookName, setFailurePolicyFn)
 }
 
+func setConfigSpec(obj *unstructured.Unstructured, config *operatorv1alpha1.ConfigSpec) error {
+	if config == nil {
+		return nil
+	}
+	if err := unstructured.SetNestedField(obj.Object, util.ToMap(config), "spec"); err != nil {
+		return errors.Wrapf(err, "Failed to set config spec")
+	}
+	return nil
+}
+
 func setNamespaceSelector(obj *unstructured.Unstructured, namespaceSelector *metav1.LabelSelector, webhookName string) error {
 	if namespaceSelector == nil {
 		return nil
@@ -680,48 +1268,255 @@ func setNamespaceSelector(obj *unstructured.Unstructured, namespaceSelector *met
 	return setWebhookConfigurationWithFn(obj, webhookName, setNamespaceSelectorFn)
 }
 
+func setObjectSelector(obj *unstructured.Unstructured, objectSelector *metav1.LabelSelector, webhookName string) error {
+	if objectSelector == nil {
+		return nil
+	}
+
+	setObjectSelectorFn := func(webhook map[string]interface{}) error {
+		if err := unstructured.SetNestedField(webhook, util.ToMap(objectSelector), "objectSelector"); err != nil {
+			return errors.Wrapf(err, "Failed to set webhook object selector")
+		}
+		return nil
+	}
+
+	return setWebhookConfigurationWithFn(obj, webhookName, setObjectSelectorFn)
+}
+
+func setWebhookRules(obj *unstructured.Unstructured, rules []operatorv1alpha1.WebhookRule, webhookName string) error {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	webhookRules := make([]interface{}, 0, len(rules))
+	for _, r := range rules {
+		operations := make([]string, 0, len(r.Operations))
+		for _, o := range r.Operations {
+			operations = append(operations, string(o))
+		}
+		webhookRules = append(webhookRules, map[string]interface{}{
+			"apiGroups":   toInterfaceSlice(valuesOrAll(r.APIGroups)),
+			"apiVersions": toInterfaceSlice(valuesOrAll(r.APIVersions)),
+			"operations":  toInterfaceSlice(operations),
+			"resources":   toInterfaceSlice(valuesOrAll(r.Resources)),
+		})
+	}
+
+	setWebhookRulesFn := func(webhook map[string]interface{}) error {
+		if err := unstructured.SetNestedSlice(webhook, webhookRules, "rules"); err != nil {
+			return errors.Wrapf(err, "Failed to set webhook rules")
+		}
+		return nil
+	}
+
+	return setWebhookConfigurationWithFn(obj, webhookName, setWebhookRulesFn)
+}
+
+// valuesOrAll returns the wildcard matching all values when none are given.
+func valuesOrAll(values []string) []string {
+	if len(values) == 0 {
+		return []string{"*"}
+	}
+	return values
+}
+
+func setTimeoutSeconds(obj *unstructured.Unstructured, timeoutSeconds *int32, webhookName string) error {
+	if timeoutSeconds == nil {
+		return nil
+	}
+
+	setTimeoutSecondsFn := func(webhook map[string]interface{}) error {
+		if err := unstructured.SetNestedField(webhook, int64(*timeoutSeconds), "timeoutSeconds"); err != nil {
+			return errors.Wrapf(err, "Failed to set webhook timeout")
+		}
+		return nil
+	}
+
+	return setWebhookConfigurationWithFn(obj, webhookName, setTimeoutSecondsFn)
+}
+
+func setMatchPolicy(obj *unstructured.Unstructured, matchPolicy *admregv1.MatchPolicyType, webhookName string) error {
+	if matchPolicy == nil {
+		return nil
+	}
+
+	setMatchPolicyFn := func(webhook map[string]interface{}) error {
+		if err := unstructured.SetNestedField(webhook, string(*matchPolicy), "matchPolicy"); err != nil {
+			return errors.Wrapf(err, "Failed to set webhook match policy")
+		}
+		return nil
+	}
+
+	return setWebhookConfigurationWithFn(obj, webhookName, setMatchPolicyFn)
+}
+
+func setReinvocationPolicy(obj *unstructured.Unstructured, reinvocationPolicy *admregv1.ReinvocationPolicyType, webhookName string) error {
+	if reinvocationPolicy == nil {
+		return nil
+	}
+
+	setReinvocationPolicyFn := func(webhook map[string]interface{}) error {
+		if err := unstructured.SetNestedField(webhook, string(*reinvocationPolicy), "reinvocationPolicy"); err != nil {
+			return errors.Wrapf(err, "Failed to set webhook reinvocation policy")
+		}
+		return nil
+	}
+
+	return setWebhookConfigurationWithFn(obj, webhookName, setReinvocationPolicyFn)
+}
+
 // Generic setters
 
-func setAffinity(obj *unstructured.Unstructured, spec operatorv1alpha1.GatekeeperSpec) error {
-	if spec.Affinity != nil {
-		if err := unstructured.SetNestedField(obj.Object, util.ToMap(spec.Affinity), "spec", "template", "spec", "affinity"); err != nil {
-			return errors.Wrapf(err, "Failed to set affinity value")
+func getAuditScheduling(audit *operatorv1alpha1.AuditConfig) *operatorv1alpha1.SchedulingConfig {
+	if audit == nil {
+		return nil
+	}
+	return audit.Scheduling
+}
+
+func getWebhookScheduling(webhook *operatorv1alpha1.WebhookConfig) *operatorv1alpha1.SchedulingConfig {
+	if webhook == nil {
+		return nil
+	}
+	return webhook.Scheduling
+}
+
+// getScheduling returns the effective scheduling of a component, whose
+// fields take precedence over the same fields of the spec.
+func getScheduling(spec operatorv1alpha1.GatekeeperSpec, component *operatorv1alpha1.SchedulingConfig) operatorv1alpha1.SchedulingConfig {
+	scheduling := operatorv1alpha1.SchedulingConfig{
+		NodeSelector: spec.NodeSelector,
+		Affinity:     spec.Affinity,
+		Tolerations:  spec.Tolerations,
+	}
+	if component == nil {
+		return scheduling
+	}
+	if component.NodeSelector != nil {
+		scheduling.NodeSelector = component.NodeSelector
+	}
+	if component.Affinity != nil {
+		scheduling.Affinity = component.Affinity
+	}
+	if component.Tolerations != nil {
+		scheduling.Tolerations = component.Tolerations
+	}
+	scheduling.TopologySpreadConstraints = component.TopologySpreadConstraints
+	return scheduling
+}
+
+func setScheduling(obj *unstructured.Unstructured, scheduling operatorv1alpha1.SchedulingConfig) error {
+	if err := setAffinity(obj, scheduling.Affinity); err != nil {
+		return err
+	}
+	if err := setNodeSelector(obj, scheduling.NodeSelector); err != nil {
+		return err
+	}
+	if err := setTolerations(obj, scheduling.Tolerations); err != nil {
+		return err
+	}
+	return setTopologySpreadConstraints(obj, scheduling.TopologySpreadConstraints)
+}
+
+// setAffinity sets each kind of affinity separately so that the kinds not
+// set keep the values of the Gatekeeper manifests.
+func setAffinity(obj *unstructured.Unstructured, affinity *corev1.Affinity) error {
+	if affinity == nil {
+		return nil
+	}
+	affinityMap := util.ToMap(affinity)
+	for _, kind := range []string{"nodeAffinity", "podAffinity", "podAntiAffinity"} {
+		value, ok := affinityMap[kind]
+		if !ok {
+			continue
+		}
+		if err := unstructured.SetNestedField(obj.Object, value, "spec", "template", "spec", "affinity", kind); err != nil {
+			return errors.Wrapf(err, "Failed to set %s value", kind)
 		}
 	}
 	return nil
 }
 
-func setNodeSelector(obj *unstructured.Unstructured, spec operatorv1alpha1.GatekeeperSpec) error {
-	if spec.NodeSelector != nil {
-		if err := unstructured.SetNestedStringMap(obj.Object, spec.NodeSelector, "spec", "template", "spec", "nodeSelector"); err != nil {
+func setNodeSelector(obj *unstructured.Unstructured, nodeSelector map[string]string) error {
+	if nodeSelector != nil {
+		if err := unstructured.SetNestedStringMap(obj.Object, nodeSelector, "spec", "template", "spec", "nodeSelector"); err != nil {
 			return errors.Wrapf(err, "Failed to set nodeSelector value")
 		}
 	}
 	return nil
 }
 
-func setPodAnnotations(obj *unstructured.Unstructured, spec operatorv1alpha1.GatekeeperSpec) error {
-	if spec.PodAnnotations != nil {
-		if err := unstructured.SetNestedStringMap(obj.Object, spec.PodAnnotations, "spec", "template", "metadata", "annotations"); err != nil {
+func getAuditPodAnnotations(audit *operatorv1alpha1.AuditConfig) map[string]string {
+	if audit == nil {
+		return nil
+	}
+	return audit.PodAnnotations
+}
+
+func getWebhookPodAnnotations(webhook *operatorv1alpha1.WebhookConfig) map[string]string {
+	if webhook == nil {
+		return nil
+	}
+	return webhook.PodAnnotations
+}
+
+// getPodAnnotations returns the effective pod annotations of a component,
+// which replace the pod annotations of the spec when set.
+func getPodAnnotations(spec operatorv1alpha1.GatekeeperSpec, component map[string]string) map[string]string {
+	if component != nil {
+		return component
+	}
+	return spec.PodAnnotations
+}
+
+func setPodAnnotations(obj *unstructured.Unstructured, podAnnotations map[string]string) error {
+	if podAnnotations != nil {
+		if err := unstructured.SetNestedStringMap(obj.Object, podAnnotations, "spec", "template", "metadata", "annotations"); err != nil {
 			return errors.Wrapf(err, "Failed to set podAnnotations")
 		}
 	}
 	return nil
 }
 
-func setTolerations(obj *unstructured.Unstructured, spec operatorv1alpha1.GatekeeperSpec) error {
-	if spec.Tolerations != nil {
-		tolerations := make([]interface{}, len(spec.Tolerations))
-		for i, t := range spec.Tolerations {
-			tolerations[i] = util.ToMap(t)
+func setImagePullSecrets(obj *unstructured.Unstructured, spec operatorv1alpha1.GatekeeperSpec) error {
+	if spec.Image != nil && spec.Image.PullSecrets != nil {
+		secrets := make([]interface{}, len(spec.Image.PullSecrets))
+		for i, s := range spec.Image.PullSecrets {
+			secrets[i] = util.ToMap(s)
+		}
+		if err := unstructured.SetNestedSlice(obj.Object, secrets, "spec", "template", "spec", "imagePullSecrets"); err != nil {
+			return errors.Wrapf(err, "Failed to set imagePullSecrets")
 		}
-		if err := unstructured.SetNestedSlice(obj.Object, tolerations, "spec", "template", "spec", "tolerations"); err != nil {
+	}
+	return nil
+}
+
+func setTolerations(obj *unstructured.Unstructured, tolerations []corev1.Toleration) error {
+	if tolerations != nil {
+		values := make([]interface{}, len(tolerations))
+		for i, t := range tolerations {
+			values[i] = util.ToMap(t)
+		}
+		if err := unstructured.SetNestedSlice(obj.Object, values, "spec", "template", "spec", "tolerations"); err != nil {
 			return errors.Wrapf(err, "Failed to set container tolerations")
 		}
 	}
 	return nil
 }
 
+func setTopologySpreadConstraints(obj *unstructured.Unstructured, constraints []corev1.TopologySpreadConstraint) error {
+	if constraints != nil {
+		values := make([]interface{}, len(constraints))
+		for i, c := range constraints {
+			values[i] = util.ToMap(c)
+		}
+		if err := unstructured.SetNestedSlice(obj.Object, values, "spec", "template", "spec", "topologySpreadConstraints"); err != nil {
+			return errors.Wrapf(err, "Failed to set topologySpreadConstraints")
+		}
+	}
+	return nil
+}
+
 // Container specific setters
 
 func setResources(obj *unstructured.Unstructured, resources *corev1.ResourceRequirements) error {
@@ -736,15 +1531,10 @@ func setResources(obj *unstructured.Unstructured, resources *corev1.ResourceRequ
 	return nil
 }
 
-func setImage(container map[string]interface{}, spec operatorv1alpha1.GatekeeperSpec) error {
+func setImagePullPolicy(container map[string]interface{}, spec operatorv1alpha1.GatekeeperSpec) error {
 	if spec.Image == nil {
 		return nil
 	}
-	if spec.Image.Image != nil {
-		if err := unstructured.SetNestedField(container, *spec.Image.Image, "image"); err != nil {
-			return errors.Wrapf(err, "Failed to set container image")
-		}
-	}
 	if spec.Image.ImagePullPolicy != nil {
 		if err := unstructured.SetNestedField(container, string(*spec.Image.ImagePullPolicy), "imagePullPolicy"); err != nil {
 			return errors.Wrapf(err, "Failed to set container image pull policy")
@@ -795,6 +1585,27 @@ func setContainerArg(obj *unstructured.Unstructured, containerName, argName stri
 	})
 }
 
+// setContainerArgs replaces every occurrence of the given argument with one
+// argument per value.
+func setContainerArgs(obj *unstructured.Unstructured, containerName, argName string, argValues []string) error {
+	return setContainerAttrWithFn(obj, containerName, func(container map[string]interface{}) error {
+		args, found, err := unstructured.NestedStringSlice(container, "args")
+		if !found || err != nil {
+			return errors.Wrapf(err, "Unable to retrieve container arguments for: %s", containerName)
+		}
+		newArgs := make([]string, 0, len(args)+len(argValues))
+		for _, arg := range args {
+			if n, _ := util.FromArg(arg); n != argName {
+				newArgs = append(newArgs, arg)
+			}
+		}
+		for _, v := range argValues {
+			newArgs = append(newArgs, util.ToArg(argName, v))
+		}
+		return unstructured.SetNestedStringSlice(container, newArgs, "args")
+	})
+}
+
 func setNamespace(obj *unstructured.Unstructured, asset, namespace string) error {
 	if obj.GetNamespace() != "" {
 		obj.SetNamespace(namespace)
@@ -15,15 +15,24 @@ limitations under the License.
 package controllers
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	. "github.com/onsi/gomega"
 	admregv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	operatorv1alpha1 "github.com/gatekeeper/gatekeeper-operator/api/v1alpha1"
 	"github.com/gatekeeper/gatekeeper-operator/pkg/util"
@@ -46,7 +55,8 @@ func TestDeployWebhookConfigs(t *testing.T) {
 	g.Expect(applyAssets).To(ContainElement(ValidatingWebhookConfiguration))
 	g.Expect(applyAssets).NotTo(ContainElements(mutatingStaticAssets))
 	g.Expect(deleteAssets).NotTo(ContainElement(ValidatingWebhookConfiguration))
-	g.Expect(deleteAssets).To(ContainElements(mutatingStaticAssets))
+	g.Expect(deleteAssets).To(ContainElement(MutatingWebhookConfiguration))
+	g.Expect(deleteAssets).NotTo(ContainElements(mutatingCRDStaticAssets))
 
 	webhookEnabled := operatorv1alpha1.WebhookEnabled
 	webhookDisabled := operatorv1alpha1.WebhookDisabled
@@ -69,7 +79,8 @@ func TestDeployWebhookConfigs(t *testing.T) {
 	g.Expect(applyAssets).To(ContainElement(ValidatingWebhookConfiguration))
 	g.Expect(applyAssets).NotTo(ContainElements(mutatingStaticAssets))
 	g.Expect(deleteAssets).NotTo(ContainElement(ValidatingWebhookConfiguration))
-	g.Expect(deleteAssets).To(ContainElements(mutatingStaticAssets))
+	g.Expect(deleteAssets).To(ContainElement(MutatingWebhookConfiguration))
+	g.Expect(deleteAssets).NotTo(ContainElements(mutatingCRDStaticAssets))
 
 	// ValidatingWebhookConfiguration disabled
 	// MutatingWebhookConfiguration enabled
@@ -89,7 +100,33 @@ func TestDeployWebhookConfigs(t *testing.T) {
 	g.Expect(applyAssets).NotTo(ContainElement(ValidatingWebhookConfiguration))
 	g.Expect(applyAssets).NotTo(ContainElements(mutatingStaticAssets))
 	g.Expect(deleteAssets).To(ContainElement(ValidatingWebhookConfiguration))
+	g.Expect(deleteAssets).To(ContainElement(MutatingWebhookConfiguration))
+	g.Expect(deleteAssets).NotTo(ContainElements(mutatingCRDStaticAssets))
+
+	mutationCRDsRetain := operatorv1alpha1.MutationCRDsRetain
+	mutationCRDsDelete := operatorv1alpha1.MutationCRDsDelete
+
+	// MutatingWebhookConfiguration disabled
+	// Mutation CRDs retained
+	gatekeeper.Spec.MutationCRDsPolicy = &mutationCRDsRetain
+	deleteAssets, applyAssets = getStaticAssets(gatekeeper)
+	g.Expect(applyAssets).NotTo(ContainElements(mutatingStaticAssets))
+	g.Expect(deleteAssets).To(ContainElement(MutatingWebhookConfiguration))
+	g.Expect(deleteAssets).NotTo(ContainElements(mutatingCRDStaticAssets))
+
+	// MutatingWebhookConfiguration disabled
+	// Mutation CRDs deleted
+	gatekeeper.Spec.MutationCRDsPolicy = &mutationCRDsDelete
+	deleteAssets, applyAssets = getStaticAssets(gatekeeper)
+	g.Expect(applyAssets).NotTo(ContainElements(mutatingStaticAssets))
 	g.Expect(deleteAssets).To(ContainElements(mutatingStaticAssets))
+
+	// MutatingWebhookConfiguration enabled
+	// Mutation CRDs delete policy ignored
+	gatekeeper.Spec.MutatingWebhook = &webhookEnabled
+	deleteAssets, applyAssets = getStaticAssets(gatekeeper)
+	g.Expect(applyAssets).To(ContainElements(mutatingStaticAssets))
+	g.Expect(deleteAssets).NotTo(ContainElements(mutatingStaticAssets))
 }
 
 func TestGetSubsetOfAssets(t *testing.T) {
@@ -99,6 +136,34 @@ func TestGetSubsetOfAssets(t *testing.T) {
 	g.Expect(getSubsetOfAssets(orderedStaticAssets, mutatingStaticAssets...)).To(HaveLen(len(orderedStaticAssets) - len(mutatingStaticAssets)))
 }
 
+func TestGetOwnedTypes(t *testing.T) {
+	g := NewWithT(t)
+	r := &GatekeeperReconciler{}
+	ownedTypes, err := r.getOwnedTypes()
+	g.Expect(err).ToNot(HaveOccurred())
+
+	kinds := make([]string, 0, len(ownedTypes))
+	for _, o := range ownedTypes {
+		kinds = append(kinds, o.GetObjectKind().GroupVersionKind().Kind)
+	}
+	g.Expect(kinds).To(ConsistOf(
+		"ClusterRole",
+		"ClusterRoleBinding",
+		"CustomResourceDefinition",
+		"Deployment",
+		"Namespace",
+		"PodDisruptionBudget",
+		"PodSecurityPolicy",
+		"Role",
+		"RoleBinding",
+		util.SecretKind,
+		util.ServiceKind,
+		"ServiceAccount",
+		util.MutatingWebhookConfigurationKind,
+		util.ValidatingWebhookConfigurationKind,
+	))
+}
+
 func TestCustomNamespace(t *testing.T) {
 	g := NewWithT(t)
 	gatekeeper := &operatorv1alpha1.Gatekeeper{
@@ -245,7 +310,82 @@ func TestAffinity(t *testing.T) {
 	assertAuditAffinity(g, auditObj, affinity)
 	err = crOverrides(gatekeeper, WebhookFile, webhookObj, namespace, false)
 	g.Expect(err).ToNot(HaveOccurred())
-	assertWebhookAffinity(g, webhookObj, affinity)
+	expected := test.DefaultDeployment.Affinity.DeepCopy()
+	expected.PodAffinity = affinity.PodAffinity
+	assertWebhookAffinity(g, webhookObj, expected)
+
+	// test podAntiAffinity override removes the default
+	gatekeeper.Spec.Affinity = &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{}}
+	err = crOverrides(gatekeeper, WebhookFile, webhookObj, namespace, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	expected.PodAntiAffinity = &corev1.PodAntiAffinity{}
+	assertWebhookAffinity(g, webhookObj, expected)
+}
+
+func TestScheduling(t *testing.T) {
+	g := NewWithT(t)
+	gatekeeper := &operatorv1alpha1.Gatekeeper{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+		Spec: operatorv1alpha1.GatekeeperSpec{
+			NodeSelector: map[string]string{"region": "emea"},
+			Tolerations: []corev1.Toleration{
+				{
+					Key:      "example",
+					Operator: corev1.TolerationOpExists,
+				},
+			},
+		},
+	}
+	auditScheduling := &operatorv1alpha1.SchedulingConfig{
+		NodeSelector: map[string]string{"node-role.kubernetes.io/infra": ""},
+	}
+	webhookScheduling := &operatorv1alpha1.SchedulingConfig{
+		TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+			{
+				MaxSkew:           1,
+				TopologyKey:       "topology.kubernetes.io/zone",
+				WhenUnsatisfiable: corev1.ScheduleAnyway,
+				LabelSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"control-plane": "controller-manager"},
+				},
+			},
+		},
+	}
+	gatekeeper.Spec.Audit = &operatorv1alpha1.AuditConfig{Scheduling: auditScheduling}
+	gatekeeper.Spec.Webhook = &operatorv1alpha1.WebhookConfig{Scheduling: webhookScheduling}
+
+	// test the component scheduling takes precedence over the spec
+	auditObj, err := util.GetManifestObject(AuditFile)
+	g.Expect(err).ToNot(HaveOccurred())
+	err = crOverrides(gatekeeper, AuditFile, auditObj, namespace, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	assertNodeSelector(g, auditObj, auditScheduling.NodeSelector)
+	assertTolerations(g, auditObj, gatekeeper.Spec.Tolerations)
+	assertTopologySpreadConstraints(g, auditObj, nil)
+
+	webhookObj, err := util.GetManifestObject(WebhookFile)
+	g.Expect(err).ToNot(HaveOccurred())
+	err = crOverrides(gatekeeper, WebhookFile, webhookObj, namespace, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	assertNodeSelector(g, webhookObj, gatekeeper.Spec.NodeSelector)
+	assertTolerations(g, webhookObj, gatekeeper.Spec.Tolerations)
+	assertTopologySpreadConstraints(g, webhookObj, webhookScheduling.TopologySpreadConstraints)
+}
+
+func assertTopologySpreadConstraints(g *WithT, obj *unstructured.Unstructured, expected []corev1.TopologySpreadConstraint) {
+	g.Expect(obj).NotTo(BeNil())
+	current, found, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "topologySpreadConstraints")
+	g.Expect(err).ToNot(HaveOccurred())
+	if expected == nil {
+		g.Expect(found).To(BeFalse())
+	} else {
+		g.Expect(found).To(BeTrue())
+		for i := range expected {
+			g.Expect(util.ToMap(expected[i])).To(BeEquivalentTo(current[i]))
+		}
+	}
 }
 
 func assertAuditAffinity(g *WithT, obj *unstructured.Unstructured, expected *corev1.Affinity) {
@@ -362,6 +502,16 @@ func TestPodAnnotations(t *testing.T) {
 	err = crOverrides(gatekeeper, WebhookFile, webhookObj, namespace, false)
 	g.Expect(err).ToNot(HaveOccurred())
 	assertPodAnnotations(g, webhookObj, podAnnotations)
+
+	// test component podAnnotations replace the shared ones
+	auditAnnotations := map[string]string{"my.annotation/foo": "audit"}
+	gatekeeper.Spec.Audit = &operatorv1alpha1.AuditConfig{PodAnnotations: auditAnnotations}
+	err = crOverrides(gatekeeper, AuditFile, auditObj, namespace, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	assertPodAnnotations(g, auditObj, auditAnnotations)
+	err = crOverrides(gatekeeper, WebhookFile, webhookObj, namespace, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	assertPodAnnotations(g, webhookObj, podAnnotations)
 }
 
 func assertPodAnnotations(g *WithT, obj *unstructured.Unstructured, expected map[string]string) {
@@ -557,6 +707,47 @@ func TestImage(t *testing.T) {
 	err = crOverrides(gatekeeper, WebhookFile, webhookObj, namespace, false)
 	g.Expect(err).ToNot(HaveOccurred())
 	assertImage(g, webhookObj, imageConfig)
+
+	// test component image override
+	auditImage := "mycustom-image/the-gatekeeper:v1.0.1"
+	gatekeeper.Spec.Audit = &operatorv1alpha1.AuditConfig{Image: &auditImage}
+	err = crOverrides(gatekeeper, AuditFile, auditObj, namespace, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	assertImage(g, auditObj, &operatorv1alpha1.ImageConfig{Image: &auditImage, ImagePullPolicy: &imagePullPolicy})
+	err = crOverrides(gatekeeper, WebhookFile, webhookObj, namespace, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	assertImage(g, webhookObj, imageConfig)
+}
+
+func TestImagePullSecrets(t *testing.T) {
+	g := NewWithT(t)
+	gatekeeper := &operatorv1alpha1.Gatekeeper{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+	}
+	for _, asset := range []string{AuditFile, WebhookFile} {
+		// test default imagePullSecrets
+		obj, err := util.GetManifestObject(asset)
+		g.Expect(err).ToNot(HaveOccurred())
+		err = crOverrides(gatekeeper, asset, obj, namespace, false)
+		g.Expect(err).ToNot(HaveOccurred())
+		_, found, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "imagePullSecrets")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(found).To(BeFalse())
+
+		// test imagePullSecrets override
+		gatekeeper.Spec.Image = &operatorv1alpha1.ImageConfig{
+			PullSecrets: []corev1.LocalObjectReference{{Name: "registry"}},
+		}
+		err = crOverrides(gatekeeper, asset, obj, namespace, false)
+		g.Expect(err).ToNot(HaveOccurred())
+		current, found, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "imagePullSecrets")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(found).To(BeTrue())
+		g.Expect(current).To(Equal([]interface{}{map[string]interface{}{"name": "registry"}}))
+		gatekeeper.Spec.Image = nil
+	}
 }
 
 func assertImage(g *WithT, obj *unstructured.Unstructured, expected *operatorv1alpha1.ImageConfig) {
@@ -735,6 +926,386 @@ func assertNamespaceSelector(g *WithT, obj *unstructured.Unstructured, webhookNa
 	})
 }
 
+func TestObjectSelector(t *testing.T) {
+	g := NewWithT(t)
+
+	objectSelector := metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{
+				Key:      "gatekeeper.sh/skip",
+				Operator: metav1.LabelSelectorOpDoesNotExist,
+			},
+		},
+	}
+	webhook := operatorv1alpha1.WebhookConfig{
+		ObjectSelector: &objectSelector,
+	}
+	mutatingWebhook := operatorv1alpha1.WebhookEnabled
+	gatekeeper := &operatorv1alpha1.Gatekeeper{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+		Spec: operatorv1alpha1.GatekeeperSpec{
+			MutatingWebhook: &mutatingWebhook,
+		},
+	}
+	// test default objectSelector
+	valObj, err := util.GetManifestObject(ValidatingWebhookConfiguration)
+	g.Expect(err).ToNot(HaveOccurred())
+	assertWebhookField(g, valObj, ValidationGatekeeperWebhook, "objectSelector", nil)
+	mutObj, err := util.GetManifestObject(MutatingWebhookConfiguration)
+	g.Expect(err).ToNot(HaveOccurred())
+	assertWebhookField(g, mutObj, MutationGatekeeperWebhook, "objectSelector", nil)
+
+	// test nil objectSelector
+	err = crOverrides(gatekeeper, ValidatingWebhookConfiguration, valObj, namespace, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	assertWebhookField(g, valObj, ValidationGatekeeperWebhook, "objectSelector", nil)
+	err = crOverrides(gatekeeper, MutatingWebhookConfiguration, mutObj, namespace, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	assertWebhookField(g, mutObj, MutationGatekeeperWebhook, "objectSelector", nil)
+
+	// test objectSelector override
+	gatekeeper.Spec.Webhook = &webhook
+	err = crOverrides(gatekeeper, ValidatingWebhookConfiguration, valObj, namespace, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	assertWebhookField(g, valObj, ValidationGatekeeperWebhook, "objectSelector", util.ToMap(objectSelector))
+	assertWebhookField(g, valObj, CheckIgnoreLabelGatekeeperWebhook, "objectSelector", nil)
+	err = crOverrides(gatekeeper, MutatingWebhookConfiguration, mutObj, namespace, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	assertWebhookField(g, mutObj, MutationGatekeeperWebhook, "objectSelector", util.ToMap(objectSelector))
+}
+
+func TestWebhookRules(t *testing.T) {
+	g := NewWithT(t)
+
+	defaultRules := []interface{}{
+		map[string]interface{}{
+			"apiGroups":   []interface{}{"*"},
+			"apiVersions": []interface{}{"*"},
+			"operations":  []interface{}{"CREATE", "UPDATE"},
+			"resources":   []interface{}{"*"},
+		},
+	}
+	webhook := operatorv1alpha1.WebhookConfig{
+		Rules: []operatorv1alpha1.WebhookRule{
+			{
+				Operations: []operatorv1alpha1.WebhookOperation{
+					operatorv1alpha1.WebhookOperationCreate,
+					operatorv1alpha1.WebhookOperationUpdate,
+					operatorv1alpha1.WebhookOperationDelete,
+				},
+				APIGroups: []string{"apps"},
+				Resources: []string{"deployments"},
+			},
+			{
+				Operations: []operatorv1alpha1.WebhookOperation{
+					operatorv1alpha1.WebhookOperationConnect,
+				},
+				APIGroups:   []string{""},
+				APIVersions: []string{"v1"},
+				Resources:   []string{"pods/exec"},
+			},
+		},
+	}
+	expectedRules := []interface{}{
+		map[string]interface{}{
+			"apiGroups":   []interface{}{"apps"},
+			"apiVersions": []interface{}{"*"},
+			"operations":  []interface{}{"CREATE", "UPDATE", "DELETE"},
+			"resources":   []interface{}{"deployments"},
+		},
+		map[string]interface{}{
+			"apiGroups":   []interface{}{""},
+			"apiVersions": []interface{}{"v1"},
+			"operations":  []interface{}{"CONNECT"},
+			"resources":   []interface{}{"pods/exec"},
+		},
+	}
+	mutatingWebhook := operatorv1alpha1.WebhookEnabled
+	gatekeeper := &operatorv1alpha1.Gatekeeper{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+		Spec: operatorv1alpha1.GatekeeperSpec{
+			MutatingWebhook: &mutatingWebhook,
+		},
+	}
+	// test default rules
+	valObj, err := util.GetManifestObject(ValidatingWebhookConfiguration)
+	g.Expect(err).ToNot(HaveOccurred())
+	assertWebhookField(g, valObj, ValidationGatekeeperWebhook, "rules", defaultRules)
+	mutObj, err := util.GetManifestObject(MutatingWebhookConfiguration)
+	g.Expect(err).ToNot(HaveOccurred())
+	assertWebhookField(g, mutObj, MutationGatekeeperWebhook, "rules", defaultRules)
+
+	// test nil rules
+	err = crOverrides(gatekeeper, ValidatingWebhookConfiguration, valObj, namespace, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	assertWebhookField(g, valObj, ValidationGatekeeperWebhook, "rules", defaultRules)
+	err = crOverrides(gatekeeper, MutatingWebhookConfiguration, mutObj, namespace, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	assertWebhookField(g, mutObj, MutationGatekeeperWebhook, "rules", defaultRules)
+
+	// test rules override
+	checkIgnoreLabelRules := getWebhookField(g, valObj, CheckIgnoreLabelGatekeeperWebhook, "rules")
+	gatekeeper.Spec.Webhook = &webhook
+	err = crOverrides(gatekeeper, ValidatingWebhookConfiguration, valObj, namespace, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	assertWebhookField(g, valObj, ValidationGatekeeperWebhook, "rules", expectedRules)
+	assertWebhookField(g, valObj, CheckIgnoreLabelGatekeeperWebhook, "rules", checkIgnoreLabelRules)
+	err = crOverrides(gatekeeper, MutatingWebhookConfiguration, mutObj, namespace, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	assertWebhookField(g, mutObj, MutationGatekeeperWebhook, "rules", expectedRules)
+}
+
+func TestTimeoutSeconds(t *testing.T) {
+	g := NewWithT(t)
+
+	timeoutSeconds := int32(10)
+	webhook := operatorv1alpha1.WebhookConfig{
+		TimeoutSeconds: &timeoutSeconds,
+	}
+	mutatingWebhook := operatorv1alpha1.WebhookEnabled
+	gatekeeper := &operatorv1alpha1.Gatekeeper{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+		Spec: operatorv1alpha1.GatekeeperSpec{
+			MutatingWebhook: &mutatingWebhook,
+		},
+	}
+	defaultTimeoutSeconds := int64(test.DefaultDeployment.TimeoutSeconds)
+	// test default timeoutSeconds
+	valObj, err := util.GetManifestObject(ValidatingWebhookConfiguration)
+	g.Expect(err).ToNot(HaveOccurred())
+	assertWebhookField(g, valObj, ValidationGatekeeperWebhook, "timeoutSeconds", defaultTimeoutSeconds)
+	mutObj, err := util.GetManifestObject(MutatingWebhookConfiguration)
+	g.Expect(err).ToNot(HaveOccurred())
+	// The mutating webhook leaves timeoutSeconds to the API server default.
+	assertWebhookField(g, mutObj, MutationGatekeeperWebhook, "timeoutSeconds", nil)
+
+	// test nil timeoutSeconds
+	err = crOverrides(gatekeeper, ValidatingWebhookConfiguration, valObj, namespace, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	assertWebhookField(g, valObj, ValidationGatekeeperWebhook, "timeoutSeconds", defaultTimeoutSeconds)
+	err = crOverrides(gatekeeper, MutatingWebhookConfiguration, mutObj, namespace, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	assertWebhookField(g, mutObj, MutationGatekeeperWebhook, "timeoutSeconds", nil)
+
+	// test timeoutSeconds override
+	gatekeeper.Spec.Webhook = &webhook
+	err = crOverrides(gatekeeper, ValidatingWebhookConfiguration, valObj, namespace, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	assertWebhookField(g, valObj, ValidationGatekeeperWebhook, "timeoutSeconds", int64(timeoutSeconds))
+	assertWebhookField(g, valObj, CheckIgnoreLabelGatekeeperWebhook, "timeoutSeconds", defaultTimeoutSeconds)
+	err = crOverrides(gatekeeper, MutatingWebhookConfiguration, mutObj, namespace, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	assertWebhookField(g, mutObj, MutationGatekeeperWebhook, "timeoutSeconds", int64(timeoutSeconds))
+}
+
+func TestMatchPolicy(t *testing.T) {
+	g := NewWithT(t)
+
+	matchPolicy := admregv1.Equivalent
+	webhook := operatorv1alpha1.WebhookConfig{
+		MatchPolicy: &matchPolicy,
+	}
+	mutatingWebhook := operatorv1alpha1.WebhookEnabled
+	gatekeeper := &operatorv1alpha1.Gatekeeper{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+		Spec: operatorv1alpha1.GatekeeperSpec{
+			MutatingWebhook: &mutatingWebhook,
+		},
+	}
+	// test default matchPolicy
+	valObj, err := util.GetManifestObject(ValidatingWebhookConfiguration)
+	g.Expect(err).ToNot(HaveOccurred())
+	assertWebhookField(g, valObj, ValidationGatekeeperWebhook, "matchPolicy", nil)
+	mutObj, err := util.GetManifestObject(MutatingWebhookConfiguration)
+	g.Expect(err).ToNot(HaveOccurred())
+	assertWebhookField(g, mutObj, MutationGatekeeperWebhook, "matchPolicy", nil)
+
+	// test nil matchPolicy
+	err = crOverrides(gatekeeper, ValidatingWebhookConfiguration, valObj, namespace, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	assertWebhookField(g, valObj, ValidationGatekeeperWebhook, "matchPolicy", nil)
+	err = crOverrides(gatekeeper, MutatingWebhookConfiguration, mutObj, namespace, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	assertWebhookField(g, mutObj, MutationGatekeeperWebhook, "matchPolicy", nil)
+
+	// test matchPolicy override
+	gatekeeper.Spec.Webhook = &webhook
+	err = crOverrides(gatekeeper, ValidatingWebhookConfiguration, valObj, namespace, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	assertWebhookField(g, valObj, ValidationGatekeeperWebhook, "matchPolicy", string(matchPolicy))
+	err = crOverrides(gatekeeper, MutatingWebhookConfiguration, mutObj, namespace, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	assertWebhookField(g, mutObj, MutationGatekeeperWebhook, "matchPolicy", string(matchPolicy))
+}
+
+func TestMutatingWebhookConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	failurePolicy := admregv1.Fail
+	namespaceSelector := metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{
+				Key:      "admission.gatekeeper.sh/enabled",
+				Operator: metav1.LabelSelectorOpExists,
+			},
+		},
+	}
+	mutationFailurePolicy := admregv1.Ignore
+	mutationNamespaceSelector := metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			"mutation.gatekeeper.sh/enabled": "true",
+		},
+	}
+	objectSelector := metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			"mutate": "true",
+		},
+	}
+	timeoutSeconds := int32(5)
+	reinvocationPolicy := admregv1.IfNeededReinvocationPolicy
+	mutatingWebhook := operatorv1alpha1.WebhookEnabled
+	gatekeeper := &operatorv1alpha1.Gatekeeper{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+		Spec: operatorv1alpha1.GatekeeperSpec{
+			MutatingWebhook: &mutatingWebhook,
+			Webhook: &operatorv1alpha1.WebhookConfig{
+				FailurePolicy:     &failurePolicy,
+				NamespaceSelector: &namespaceSelector,
+			},
+		},
+	}
+	valObj, err := util.GetManifestObject(ValidatingWebhookConfiguration)
+	g.Expect(err).ToNot(HaveOccurred())
+	mutObj, err := util.GetManifestObject(MutatingWebhookConfiguration)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// test default, the webhook config applies to both webhooks
+	obj := mutObj.DeepCopy()
+	err = crOverrides(gatekeeper, MutatingWebhookConfiguration, obj, namespace, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	assertWebhookField(g, obj, MutationGatekeeperWebhook, "failurePolicy", string(failurePolicy))
+	assertWebhookField(g, obj, MutationGatekeeperWebhook, "namespaceSelector", util.ToMap(namespaceSelector))
+	assertWebhookField(g, obj, MutationGatekeeperWebhook, "reinvocationPolicy", nil)
+
+	// test every webhook field left unset in mutatingWebhookConfig is
+	// inherited, the match policy always is
+	matchPolicy := admregv1.Exact
+	webhookTimeoutSeconds := int32(20)
+	gatekeeper.Spec.Webhook.ObjectSelector = &objectSelector
+	gatekeeper.Spec.Webhook.TimeoutSeconds = &webhookTimeoutSeconds
+	gatekeeper.Spec.Webhook.MatchPolicy = &matchPolicy
+	gatekeeper.Spec.Webhook.Rules = []operatorv1alpha1.WebhookRule{
+		{
+			Operations: []operatorv1alpha1.WebhookOperation{
+				operatorv1alpha1.WebhookOperationUpdate,
+			},
+			Resources: []string{"deployments"},
+		},
+	}
+	gatekeeper.Spec.MutatingWebhookConfig = &operatorv1alpha1.MutatingWebhookConfig{
+		FailurePolicy: &mutationFailurePolicy,
+	}
+	obj = mutObj.DeepCopy()
+	err = crOverrides(gatekeeper, MutatingWebhookConfiguration, obj, namespace, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	assertWebhookField(g, obj, MutationGatekeeperWebhook, "failurePolicy", string(mutationFailurePolicy))
+	assertWebhookField(g, obj, MutationGatekeeperWebhook, "namespaceSelector", util.ToMap(namespaceSelector))
+	assertWebhookField(g, obj, MutationGatekeeperWebhook, "objectSelector", util.ToMap(objectSelector))
+	assertWebhookField(g, obj, MutationGatekeeperWebhook, "timeoutSeconds", int64(webhookTimeoutSeconds))
+	assertWebhookField(g, obj, MutationGatekeeperWebhook, "matchPolicy", string(matchPolicy))
+	assertWebhookField(g, obj, MutationGatekeeperWebhook, "rules", []interface{}{
+		map[string]interface{}{
+			"apiGroups":   []interface{}{"*"},
+			"apiVersions": []interface{}{"*"},
+			"operations":  []interface{}{"UPDATE"},
+			"resources":   []interface{}{"deployments"},
+		},
+	})
+	gatekeeper.Spec.Webhook = &operatorv1alpha1.WebhookConfig{
+		FailurePolicy:     &failurePolicy,
+		NamespaceSelector: &namespaceSelector,
+	}
+
+	// test mutatingWebhookConfig override
+	gatekeeper.Spec.MutatingWebhookConfig = &operatorv1alpha1.MutatingWebhookConfig{
+		FailurePolicy:      &mutationFailurePolicy,
+		NamespaceSelector:  &mutationNamespaceSelector,
+		ObjectSelector:     &objectSelector,
+		TimeoutSeconds:     &timeoutSeconds,
+		ReinvocationPolicy: &reinvocationPolicy,
+		Rules: []operatorv1alpha1.WebhookRule{
+			{
+				Operations: []operatorv1alpha1.WebhookOperation{
+					operatorv1alpha1.WebhookOperationCreate,
+				},
+				Resources: []string{"pods"},
+			},
+		},
+	}
+	obj = mutObj.DeepCopy()
+	err = crOverrides(gatekeeper, MutatingWebhookConfiguration, obj, namespace, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	assertWebhookField(g, obj, MutationGatekeeperWebhook, "failurePolicy", string(mutationFailurePolicy))
+	assertWebhookField(g, obj, MutationGatekeeperWebhook, "namespaceSelector", util.ToMap(mutationNamespaceSelector))
+	assertWebhookField(g, obj, MutationGatekeeperWebhook, "objectSelector", util.ToMap(objectSelector))
+	assertWebhookField(g, obj, MutationGatekeeperWebhook, "timeoutSeconds", int64(timeoutSeconds))
+	assertWebhookField(g, obj, MutationGatekeeperWebhook, "reinvocationPolicy", string(reinvocationPolicy))
+	assertWebhookField(g, obj, MutationGatekeeperWebhook, "rules", []interface{}{
+		map[string]interface{}{
+			"apiGroups":   []interface{}{"*"},
+			"apiVersions": []interface{}{"*"},
+			"operations":  []interface{}{"CREATE"},
+			"resources":   []interface{}{"pods"},
+		},
+	})
+
+	// test the validating webhook is left alone
+	obj = valObj.DeepCopy()
+	err = crOverrides(gatekeeper, ValidatingWebhookConfiguration, obj, namespace, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	assertWebhookField(g, obj, ValidationGatekeeperWebhook, "failurePolicy", string(failurePolicy))
+	assertWebhookField(g, obj, ValidationGatekeeperWebhook, "namespaceSelector", util.ToMap(namespaceSelector))
+	assertWebhookField(g, obj, ValidationGatekeeperWebhook, "objectSelector", nil)
+	assertWebhookField(g, obj, ValidationGatekeeperWebhook, "timeoutSeconds", int64(test.DefaultDeployment.TimeoutSeconds))
+	assertWebhookField(g, obj, ValidationGatekeeperWebhook, "reinvocationPolicy", nil)
+}
+
+// getWebhookField returns a copy of the field of the named webhook or nil
+// when it is not set.
+func getWebhookField(g *WithT, obj *unstructured.Unstructured, webhookName, field string) interface{} {
+	var value interface{}
+	found := false
+	assertWebhooksWithFn(g, obj, func(webhook map[string]interface{}) {
+		if webhook["name"] == webhookName {
+			found = true
+			current, _, err := unstructured.NestedFieldCopy(webhook, field)
+			g.Expect(err).ToNot(HaveOccurred())
+			value = current
+		}
+	})
+	g.Expect(found).To(BeTrue())
+	return value
+}
+
+func assertWebhookField(g *WithT, obj *unstructured.Unstructured, webhookName, field string, expected interface{}) {
+	current := getWebhookField(g, obj, webhookName, field)
+	if expected == nil {
+		g.Expect(current).To(BeNil())
+	} else {
+		g.Expect(current).To(Equal(expected))
+	}
+}
+
 func assertWebhooksWithFn(g *WithT, obj *unstructured.Unstructured, webhookFn func(map[string]interface{})) {
 	g.Expect(obj).NotTo(BeNil())
 	webhooks, found, err := unstructured.NestedSlice(obj.Object, "webhooks")
@@ -1241,3 +1812,170 @@ func TestMutationRBACConfig(t *testing.T) {
 
 	g.Expect(matchCount).To(Equal(len(matchMutatingRBACRuleFns)))
 }
+
+func TestConfig(t *testing.T) {
+	g := NewWithT(t)
+	gatekeeper := &operatorv1alpha1.Gatekeeper{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+	}
+	// test default, the Config is left unmanaged
+	_, applyAssets := getStaticAssets(gatekeeper)
+	g.Expect(applyAssets).NotTo(ContainElement(ConfigFile))
+
+	// test nil
+	configObj, err := util.GetManifestObject(ConfigFile)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(configObj).ToNot(BeNil())
+	obj := configObj.DeepCopy()
+	err = crOverrides(gatekeeper, ConfigFile, obj, namespace, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(obj.GetNamespace()).To(Equal(namespace))
+	_, found, err := unstructured.NestedMap(obj.Object, "spec")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(found).To(BeFalse())
+
+	// test override
+	gatekeeper.Spec.Config = &operatorv1alpha1.ConfigSpec{
+		Sync: &operatorv1alpha1.SyncConfig{
+			SyncOnly: []operatorv1alpha1.GVK{
+				{Version: "v1", Kind: "Namespace"},
+			},
+		},
+		Match: []operatorv1alpha1.MatchEntry{
+			{
+				ExcludedNamespaces: []string{"kube-system"},
+				Processes:          []string{"*"},
+			},
+		},
+		Validation: &operatorv1alpha1.ValidationConfig{
+			Traces: []operatorv1alpha1.TraceEntry{
+				{
+					User: "user_to_trace@company.com",
+					Kind: operatorv1alpha1.GVK{Version: "v1", Kind: "Namespace"},
+					Dump: "All",
+				},
+			},
+		},
+		Readiness: &operatorv1alpha1.ReadinessConfig{
+			StatsEnabled: true,
+		},
+	}
+	_, applyAssets = getStaticAssets(gatekeeper)
+	g.Expect(applyAssets).To(ContainElement(ConfigFile))
+	g.Expect(applyAssets[len(applyAssets)-1]).To(Equal(ConfigFile))
+
+	obj = configObj.DeepCopy()
+	err = crOverrides(gatekeeper, ConfigFile, obj, namespace, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(obj.GetName()).To(Equal("config"))
+	g.Expect(obj.GetNamespace()).To(Equal(namespace))
+	g.Expect(obj.Object["spec"]).To(Equal(map[string]interface{}{
+		"sync": map[string]interface{}{
+			"syncOnly": []interface{}{
+				map[string]interface{}{"version": "v1", "kind": "Namespace"},
+			},
+		},
+		"match": []interface{}{
+			map[string]interface{}{
+				"excludedNamespaces": []interface{}{"kube-system"},
+				"processes":          []interface{}{"*"},
+			},
+		},
+		"validation": map[string]interface{}{
+			"traces": []interface{}{
+				map[string]interface{}{
+					"user": "user_to_trace@company.com",
+					"kind": map[string]interface{}{"version": "v1", "kind": "Namespace"},
+					"dump": "All",
+				},
+			},
+		},
+		"readiness": map[string]interface{}{
+			"statsEnabled": true,
+		},
+	}))
+}
+
+// applyClient records the server-side apply patches it receives and returns
+// a conflict for the apply patches that do not force ownership when
+// conflicting.
+type applyClient struct {
+	client.Client
+	conflicting bool
+	forced      []bool
+}
+
+func (c *applyClient) Patch(_ context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if patch.Type() != types.ApplyPatchType {
+		return fmt.Errorf("unexpected patch type %s", patch.Type())
+	}
+	patchOpts := &client.PatchOptions{}
+	patchOpts.ApplyOptions(opts)
+	if patchOpts.FieldManager != fieldManager {
+		return fmt.Errorf("unexpected field manager %s", patchOpts.FieldManager)
+	}
+	forced := patchOpts.Force != nil && *patchOpts.Force
+	c.forced = append(c.forced, forced)
+	if c.conflicting && !forced {
+		return apierrors.NewConflict(appsv1.Resource("deployments"), "gatekeeper-audit",
+			errors.New(`conflict with "kubectl": .spec.replicas`))
+	}
+	return nil
+}
+
+func TestApplyResource(t *testing.T) {
+	g := NewWithT(t)
+
+	for _, conflicting := range []bool{false, true} {
+		gatekeeper := &operatorv1alpha1.Gatekeeper{}
+		applyClient := &applyClient{conflicting: conflicting}
+		r := &GatekeeperReconciler{
+			Client: applyClient,
+			Log:    ctrl.Log.WithName("test"),
+		}
+		auditObj, err := util.GetManifestObject(AuditFile)
+		g.Expect(err).ToNot(HaveOccurred())
+		auditObj.SetNamespace(namespace)
+
+		err = r.applyResource(context.Background(), auditObj, gatekeeper)
+		g.Expect(err).ToNot(HaveOccurred())
+		_, found, err := unstructured.NestedFieldNoCopy(auditObj.Object, "status")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(found).To(BeFalse())
+
+		if !conflicting {
+			g.Expect(applyClient.forced).To(Equal([]bool{false}))
+			g.Expect(gatekeeper.Status.Conflicts).To(BeEmpty())
+			continue
+		}
+		// test conflicting fields are taken over and reported
+		g.Expect(applyClient.forced).To(Equal([]bool{false, true}))
+		g.Expect(gatekeeper.Status.Conflicts).To(HaveLen(1))
+		conflict := gatekeeper.Status.Conflicts[0]
+		g.Expect(conflict.Kind).To(Equal("Deployment"))
+		g.Expect(conflict.Namespace).To(Equal(namespace))
+		g.Expect(conflict.Name).To(Equal(auditObj.GetName()))
+		g.Expect(conflict.Message).To(ContainSubstring(".spec.replicas"))
+	}
+}
+
+func TestGetRetainedFields(t *testing.T) {
+	g := NewWithT(t)
+	replicas := []string{"spec", "replicas"}
+
+	// test default
+	spec := operatorv1alpha1.GatekeeperSpec{}
+	g.Expect(getReleasedFields(AuditFile, spec)).To(Equal([][]string{replicas}))
+	g.Expect(getReleasedFields(WebhookFile, spec)).To(Equal([][]string{replicas}))
+	g.Expect(getReleasedFields(ServiceFile, spec)).To(BeEmpty())
+
+	// test configured replicas
+	auditReplicas := int32(2)
+	webhookReplicas := int32(3)
+	spec.Audit = &operatorv1alpha1.AuditConfig{Replicas: &auditReplicas}
+	spec.Webhook = &operatorv1alpha1.WebhookConfig{Replicas: &webhookReplicas}
+	g.Expect(getReleasedFields(AuditFile, spec)).To(BeEmpty())
+	g.Expect(getReleasedFields(WebhookFile, spec)).To(BeEmpty())
+}
@@ -0,0 +1,390 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/gatekeeper/gatekeeper-operator/api/v1alpha1"
+	"github.com/gatekeeper/gatekeeper-operator/pkg/util"
+)
+
+const (
+	// dockerHubRegistry is the registry of the images without a registry host.
+	dockerHubRegistry = "docker.io"
+	// deploymentRevisionAnnotation is set by the Deployment controller on a
+	// Deployment and on its ReplicaSets to the revision of their pod
+	// template.
+	deploymentRevisionAnnotation = "deployment.kubernetes.io/revision"
+)
+
+// getImage returns the image configured for the Deployment rendered from the
+// given asset, or an empty string to keep the image of the manifests. The
+// image of a component takes precedence over spec.image.image.
+func getImage(spec operatorv1alpha1.GatekeeperSpec, asset string) string {
+	switch {
+	case asset == AuditFile && spec.Audit != nil && spec.Audit.Image != nil:
+		return *spec.Audit.Image
+	case asset == WebhookFile && spec.Webhook != nil && spec.Webhook.Image != nil:
+		return *spec.Webhook.Image
+	case spec.Image != nil && spec.Image.Image != nil:
+		return *spec.Image.Image
+	}
+	return ""
+}
+
+func digestPinningEnabled(image *operatorv1alpha1.ImageConfig) bool {
+	return image != nil && image.DigestPinning != nil && *image.DigestPinning == operatorv1alpha1.DigestPinningEnabled
+}
+
+// setComponentImage sets the image configured for the Deployment rendered
+// from the given asset.
+func setComponentImage(obj *unstructured.Unstructured, spec operatorv1alpha1.GatekeeperSpec, asset string) error {
+	image := getImage(spec, asset)
+	if image == "" {
+		return nil
+	}
+	return setContainerAttrWithFn(obj, managerContainer, func(container map[string]interface{}) error {
+		if err := unstructured.SetNestedField(container, image, "image"); err != nil {
+			return errors.Wrapf(err, "Failed to set container image")
+		}
+		return nil
+	})
+}
+
+// pinComponentImage pins the image of the manager container to the digest
+// recorded for it when digest pinning is enabled.
+func pinComponentImage(obj *unstructured.Unstructured, gatekeeper *operatorv1alpha1.Gatekeeper) error {
+	if !digestPinningEnabled(gatekeeper.Spec.Image) {
+		return nil
+	}
+	return setContainerAttrWithFn(obj, managerContainer, func(container map[string]interface{}) error {
+		image, _, err := unstructured.NestedString(container, "image")
+		if err != nil {
+			return errors.Wrapf(err, "Unable to retrieve container image")
+		}
+		if err := unstructured.SetNestedField(container, pinImage(image, gatekeeper.Status.ImageDigests), "image"); err != nil {
+			return errors.Wrapf(err, "Failed to set container image")
+		}
+		return nil
+	})
+}
+
+func getRegistryOverride(image *operatorv1alpha1.ImageConfig) string {
+	if image == nil || image.RegistryOverride == nil {
+		return ""
+	}
+	return *image.RegistryOverride
+}
+
+// setRegistryOverride replaces the registry host of the image of every
+// container of the given Deployment.
+func setRegistryOverride(obj *unstructured.Unstructured, image *operatorv1alpha1.ImageConfig) error {
+	registry := getRegistryOverride(image)
+	if registry == "" {
+		return nil
+	}
+	for _, field := range []string{"initContainers", "containers"} {
+		containers, found, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", field)
+		if err != nil {
+			return errors.Wrapf(err, "Unable to retrieve %s of %s", field, obj.GetName())
+		}
+		if !found {
+			continue
+		}
+		for _, c := range containers {
+			container, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if image, ok := container["image"].(string); ok {
+				container["image"] = rewriteRegistry(image, registry)
+			}
+		}
+		if err := unstructured.SetNestedSlice(obj.Object, containers, "spec", "template", "spec", field); err != nil {
+			return errors.Wrapf(err, "Failed to set the registry of %s", field)
+		}
+	}
+	return nil
+}
+
+// rewriteRegistry replaces the registry host of the given image with the
+// given registry. As with the container runtimes, the first component of the
+// image is a registry host only when it contains a dot or a port, or is
+// localhost, and images without one are on Docker Hub, where official images
+// are under library/.
+func rewriteRegistry(image, registry string) string {
+	if registry == "" {
+		return image
+	}
+	host, name := dockerHubRegistry, image
+	if i := strings.Index(image, "/"); i >= 0 {
+		if first := image[:i]; strings.ContainsAny(first, ".:") || first == "localhost" {
+			host, name = first, image[i+1:]
+		}
+	}
+	if host == dockerHubRegistry && !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+	return registry + "/" + name
+}
+
+// setDefaultImage replaces the image of the Deployments of the default
+// Gatekeeper version with the default image of the operator, when set.
+func (r *GatekeeperReconciler) setDefaultImage(obj *unstructured.Unstructured, version, asset string) error {
+	if r.DefaultImage == "" || version != util.DefaultGatekeeperVersion || (asset != AuditFile && asset != WebhookFile) {
+		return nil
+	}
+	return setContainerAttrWithFn(obj, managerContainer, func(container map[string]interface{}) error {
+		if err := unstructured.SetNestedField(container, r.DefaultImage, "image"); err != nil {
+			return errors.Wrapf(err, "Failed to set container image")
+		}
+		return nil
+	})
+}
+
+// setStatusImages records the image rendered for the Deployment of the
+// given asset in the status.
+func setStatusImages(gatekeeper *operatorv1alpha1.Gatekeeper, asset string, obj *unstructured.Unstructured) error {
+	if asset != AuditFile && asset != WebhookFile {
+		return nil
+	}
+	image, err := getManagerImage(obj)
+	if err != nil {
+		return err
+	}
+	if gatekeeper.Status.Images == nil {
+		gatekeeper.Status.Images = &operatorv1alpha1.ComponentImages{}
+	}
+	if asset == AuditFile {
+		gatekeeper.Status.Images.Audit = image
+	} else {
+		gatekeeper.Status.Images.Webhook = image
+	}
+	return nil
+}
+
+// pinImage replaces the tag of the given image with the digest recorded for
+// it. Images without a recorded digest, or already referenced by digest, are
+// returned as is.
+func pinImage(image string, digests []operatorv1alpha1.ImageDigest) string {
+	if hasDigest(image) {
+		return image
+	}
+	if digest := findImageDigest(digests, image); digest != "" {
+		return imageName(image) + "@" + digest
+	}
+	return image
+}
+
+func hasDigest(image string) bool {
+	return strings.Contains(image, "@")
+}
+
+// imageName returns the given image without its tag.
+func imageName(image string) string {
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[:i]
+	}
+	return image
+}
+
+// getManagerImage returns the image of the manager container of the given
+// Deployment.
+func getManagerImage(obj *unstructured.Unstructured) (string, error) {
+	containers, _, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+	if err != nil {
+		return "", errors.Wrapf(err, "Unable to retrieve containers of %s", obj.GetName())
+	}
+	for _, c := range containers {
+		container, ok := c.(map[string]interface{})
+		if !ok || container["name"] != managerContainer {
+			continue
+		}
+		image, _, err := unstructured.NestedString(container, "image")
+		return image, err
+	}
+	return "", nil
+}
+
+// getRenderedImage returns the unpinned image of the Deployment rendered
+// from the given asset.
+func (r *GatekeeperReconciler) getRenderedImage(gatekeeper *operatorv1alpha1.Gatekeeper, asset string) (string, error) {
+	rendered := getRenderedGatekeeper(gatekeeper, asset)
+	registry := getRegistryOverride(rendered.Spec.Image)
+	if image := getImage(rendered.Spec, asset); image != "" {
+		return rewriteRegistry(image, registry), nil
+	}
+	version := getGatekeeperVersion(rendered.Spec)
+	obj, err := r.getVersionedManifestObject(version, asset)
+	if err != nil {
+		return "", err
+	}
+	if err = r.setDefaultImage(obj, version, asset); err != nil {
+		return "", err
+	}
+	image, err := getManagerImage(obj)
+	if err != nil {
+		return "", err
+	}
+	return rewriteRegistry(image, registry), nil
+}
+
+// updateImageDigests records the digests the images of the audit and the
+// webhook resolved to when digest pinning is enabled. The digest of an image
+// is the one its pods were started with, as reported by the container
+// runtime, so that the image pull secrets of the pods are used to resolve it.
+// The digests of images no longer deployed are dropped. It returns whether a
+// digest was recorded that the Deployments are not pinned to yet.
+func (r *GatekeeperReconciler) updateImageDigests(ctx context.Context, gatekeeper *operatorv1alpha1.Gatekeeper) (bool, error) {
+	if !digestPinningEnabled(gatekeeper.Spec.Image) {
+		gatekeeper.Status.ImageDigests = nil
+		return false, nil
+	}
+
+	recorded := false
+
+	var digests []operatorv1alpha1.ImageDigest
+	for _, asset := range []string{AuditFile, WebhookFile} {
+		image, err := r.getRenderedImage(gatekeeper, asset)
+		if err != nil {
+			return false, err
+		}
+		if image == "" || hasDigest(image) || findImageDigest(digests, image) != "" {
+			continue
+		}
+		digest := findImageDigest(gatekeeper.Status.ImageDigests, image)
+		if digest == "" {
+			if digest, err = r.getPodImageDigest(ctx, gatekeeper, asset, image); err != nil {
+				return false, err
+			}
+			recorded = recorded || digest != ""
+		}
+		if digest != "" {
+			digests = append(digests, operatorv1alpha1.ImageDigest{Image: image, Digest: digest})
+		}
+	}
+	gatekeeper.Status.ImageDigests = digests
+	return recorded, nil
+}
+
+func findImageDigest(digests []operatorv1alpha1.ImageDigest, image string) string {
+	for _, d := range digests {
+		if d.Image == image {
+			return d.Digest
+		}
+	}
+	return ""
+}
+
+// getPodImageDigest returns the digest of the given image from the status of
+// the manager containers of the ready pods of the current ReplicaSet of the
+// Deployment rendered from the given asset, or an empty string when no such
+// pod was started with the image yet. Pods of older ReplicaSets are ignored
+// as they may still run an image pulled before the tag was moved.
+func (r *GatekeeperReconciler) getPodImageDigest(ctx context.Context, gatekeeper *operatorv1alpha1.Gatekeeper,
+	asset, image string) (string, error) {
+	pods, err := r.listCurrentReadyPods(ctx, gatekeeper, asset)
+	if err != nil {
+		return "", err
+	}
+	for _, pod := range pods {
+		var podImage string
+		for _, c := range pod.Spec.Containers {
+			if c.Name == managerContainer {
+				podImage = c.Image
+			}
+		}
+		if podImage != image {
+			continue
+		}
+		for _, cs := range pod.Status.ContainerStatuses {
+			// The image ID is prefixed with the transport by some
+			// container runtimes, e.g. docker-pullable://, and is the
+			// local image ID when the image was not pulled by digest.
+			if i := strings.LastIndex(cs.ImageID, "@"); cs.Name == managerContainer && i >= 0 {
+				return cs.ImageID[i+1:], nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// listCurrentReadyPods returns the ready pods of the current ReplicaSet of the
+// Deployment rendered from the given asset.
+func (r *GatekeeperReconciler) listCurrentReadyPods(ctx context.Context, gatekeeper *operatorv1alpha1.Gatekeeper,
+	asset string) ([]corev1.Pod, error) {
+	deployment, err := r.getDeployment(ctx, gatekeeper, asset)
+	if err != nil || deployment == nil || deployment.Spec.Selector == nil {
+		return nil, err
+	}
+	revision := deployment.Annotations[deploymentRevisionAnnotation]
+	if revision == "" {
+		return nil, nil
+	}
+
+	replicaSets := &appsv1.ReplicaSetList{}
+	err = r.uncachedReader().List(ctx, replicaSets,
+		client.InNamespace(deployment.Namespace),
+		client.MatchingLabels(deployment.Spec.Selector.MatchLabels),
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Unable to list replica sets for deployment %s", deployment.Name)
+	}
+	var podTemplateHash string
+	for i := range replicaSets.Items {
+		rs := &replicaSets.Items[i]
+		if metav1.IsControlledBy(rs, deployment) && rs.Annotations[deploymentRevisionAnnotation] == revision {
+			podTemplateHash = rs.Labels[appsv1.DefaultDeploymentUniqueLabelKey]
+			break
+		}
+	}
+	if podTemplateHash == "" {
+		return nil, nil
+	}
+
+	labels := map[string]string{appsv1.DefaultDeploymentUniqueLabelKey: podTemplateHash}
+	for k, v := range deployment.Spec.Selector.MatchLabels {
+		labels[k] = v
+	}
+	pods, err := r.listPods(ctx, deployment, labels)
+	if err != nil {
+		return nil, err
+	}
+	ready := make([]corev1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if isPodReady(pod) {
+			ready = append(ready, pod)
+		}
+	}
+	return ready, nil
+}
+
+func isPodReady(pod corev1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,224 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1alpha1 "github.com/gatekeeper/gatekeeper-operator/api/v1alpha1"
+	"github.com/gatekeeper/gatekeeper-operator/pkg/util"
+)
+
+func TestGetImage(t *testing.T) {
+	g := NewWithT(t)
+	image, auditImage := "gatekeeper:v1", "gatekeeper:v1-patched"
+	spec := operatorv1alpha1.GatekeeperSpec{}
+	g.Expect(getImage(spec, AuditFile)).To(BeEmpty())
+
+	spec.Image = &operatorv1alpha1.ImageConfig{Image: &image}
+	spec.Audit = &operatorv1alpha1.AuditConfig{Image: &auditImage}
+	g.Expect(getImage(spec, AuditFile)).To(Equal(auditImage))
+	g.Expect(getImage(spec, WebhookFile)).To(Equal(image))
+}
+
+func TestPinImage(t *testing.T) {
+	g := NewWithT(t)
+	digests := []operatorv1alpha1.ImageDigest{
+		{Image: "localhost:5000/gatekeeper:v1", Digest: "sha256:1234"},
+		{Image: "gatekeeper", Digest: "sha256:5678"},
+	}
+	g.Expect(pinImage("localhost:5000/gatekeeper:v1", digests)).To(Equal("localhost:5000/gatekeeper@sha256:1234"))
+	g.Expect(pinImage("gatekeeper", digests)).To(Equal("gatekeeper@sha256:5678"))
+	g.Expect(pinImage("gatekeeper:v2", digests)).To(Equal("gatekeeper:v2"))
+	g.Expect(pinImage("gatekeeper@sha256:abcd", digests)).To(Equal("gatekeeper@sha256:abcd"))
+}
+
+func TestImageDigestPinning(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	image := "gatekeeper:v1"
+	enabled := operatorv1alpha1.DigestPinningEnabled
+	gatekeeper := &operatorv1alpha1.Gatekeeper{}
+	gatekeeper.Spec.Image = &operatorv1alpha1.ImageConfig{Image: &image, DigestPinning: &enabled}
+
+	auditObj, err := util.GetManifestObject(AuditFile)
+	g.Expect(err).ToNot(HaveOccurred())
+	defaultImage, err := getManagerImage(auditObj)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	newDeployment := func(name string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   namespace,
+				UID:         types.UID(name),
+				Annotations: map[string]string{deploymentRevisionAnnotation: "2"},
+			},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}},
+			},
+		}
+	}
+	newReplicaSet := func(deployment, revision string) *appsv1.ReplicaSet {
+		controller := true
+		return &appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        deployment + "-" + revision,
+				Namespace:   namespace,
+				Labels:      map[string]string{"app": deployment, appsv1.DefaultDeploymentUniqueLabelKey: revision},
+				Annotations: map[string]string{deploymentRevisionAnnotation: revision},
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: "apps/v1",
+					Kind:       "Deployment",
+					Name:       deployment,
+					UID:        types.UID(deployment),
+					Controller: &controller,
+				}},
+			},
+		}
+	}
+	newPod := func(deployment, revision, image, imageID string, ready corev1.ConditionStatus) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      deployment + "-" + revision + "-" + string(ready),
+				Namespace: namespace,
+				Labels:    map[string]string{"app": deployment, appsv1.DefaultDeploymentUniqueLabelKey: revision},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: managerContainer, Image: image}},
+			},
+			Status: corev1.PodStatus{
+				Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+				ContainerStatuses: []corev1.ContainerStatus{{Name: managerContainer, ImageID: imageID}},
+			},
+		}
+	}
+	r := &GatekeeperReconciler{
+		Client: fake.NewFakeClient(
+			newDeployment("gatekeeper-audit"),
+			newDeployment("gatekeeper-controller-manager"),
+			newReplicaSet("gatekeeper-audit", "1"),
+			newReplicaSet("gatekeeper-audit", "2"),
+			newReplicaSet("gatekeeper-controller-manager", "2"),
+			newPod("gatekeeper-audit", "1", image, "docker-pullable://gatekeeper@sha256:0000", corev1.ConditionTrue),
+			newPod("gatekeeper-audit", "2", image, "docker-pullable://gatekeeper@sha256:9999", corev1.ConditionFalse),
+			newPod("gatekeeper-audit", "2", image, "docker-pullable://gatekeeper@sha256:1234", corev1.ConditionTrue),
+			newPod("gatekeeper-controller-manager", "2", defaultImage, "sha256:5678", corev1.ConditionTrue),
+		),
+		Log:       ctrl.Log.WithName("test"),
+		Namespace: namespace,
+	}
+
+	// test the digest is recorded from the ready pods of the current
+	// ReplicaSet started with the image
+	pinning, err := r.updateImageDigests(ctx, gatekeeper)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(pinning).To(BeTrue())
+	g.Expect(gatekeeper.Status.ImageDigests).To(Equal([]operatorv1alpha1.ImageDigest{
+		{Image: image, Digest: "sha256:1234"},
+	}))
+	pinning, err = r.updateImageDigests(ctx, gatekeeper)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(pinning).To(BeFalse())
+
+	// test the image is pinned to the recorded digest
+	err = crOverrides(gatekeeper, AuditFile, auditObj, namespace, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(getManagerImage(auditObj)).To(Equal("gatekeeper@sha256:1234"))
+
+	// test the webhook image without a digest reported by its pods is not
+	// pinned
+	gatekeeper.Spec.Image.Image = nil
+	pinning, err = r.updateImageDigests(ctx, gatekeeper)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(pinning).To(BeFalse())
+	g.Expect(gatekeeper.Status.ImageDigests).To(BeEmpty())
+	webhookObj, err := util.GetManifestObject(WebhookFile)
+	g.Expect(err).ToNot(HaveOccurred())
+	err = crOverrides(gatekeeper, WebhookFile, webhookObj, namespace, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(getManagerImage(webhookObj)).To(Equal(defaultImage))
+
+	// test the digests are dropped when digest pinning is disabled
+	gatekeeper.Status.ImageDigests = []operatorv1alpha1.ImageDigest{{Image: image, Digest: "sha256:1234"}}
+	gatekeeper.Spec.Image.DigestPinning = nil
+	pinning, err = r.updateImageDigests(ctx, gatekeeper)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(pinning).To(BeFalse())
+	g.Expect(gatekeeper.Status.ImageDigests).To(BeNil())
+}
+
+func TestRewriteRegistry(t *testing.T) {
+	g := NewWithT(t)
+	registry := "mirror.example.com:5000"
+	g.Expect(rewriteRegistry("openpolicyagent/gatekeeper:v3.3.0", "")).To(Equal("openpolicyagent/gatekeeper:v3.3.0"))
+	g.Expect(rewriteRegistry("openpolicyagent/gatekeeper:v3.3.0", registry)).To(Equal(registry + "/openpolicyagent/gatekeeper:v3.3.0"))
+	g.Expect(rewriteRegistry("docker.io/openpolicyagent/gatekeeper:v3.3.0", registry)).To(Equal(registry + "/openpolicyagent/gatekeeper:v3.3.0"))
+	g.Expect(rewriteRegistry("quay.io/gatekeeper/gatekeeper@sha256:1234", registry)).To(Equal(registry + "/gatekeeper/gatekeeper@sha256:1234"))
+	g.Expect(rewriteRegistry("localhost/gatekeeper", registry)).To(Equal(registry + "/gatekeeper"))
+	g.Expect(rewriteRegistry("busybox", registry)).To(Equal(registry + "/library/busybox"))
+	g.Expect(rewriteRegistry("docker.io/busybox", registry)).To(Equal(registry + "/library/busybox"))
+}
+
+func TestRegistryOverride(t *testing.T) {
+	g := NewWithT(t)
+	image := "quay.io/gatekeeper/gatekeeper:v3.3.0"
+	registry := "mirror.example.com"
+	enabled := operatorv1alpha1.DigestPinningEnabled
+	gatekeeper := &operatorv1alpha1.Gatekeeper{}
+	gatekeeper.Spec.Image = &operatorv1alpha1.ImageConfig{RegistryOverride: &registry}
+	r := &GatekeeperReconciler{DefaultImage: image}
+
+	// test the default image is used for the default version
+	for _, asset := range []string{AuditFile, WebhookFile} {
+		obj, err := util.GetManifestObject(asset)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(r.setDefaultImage(obj, "v3.2.0", asset)).To(Succeed())
+		g.Expect(getManagerImage(obj)).ToNot(Equal(image))
+		g.Expect(r.setDefaultImage(obj, util.DefaultGatekeeperVersion, asset)).To(Succeed())
+		g.Expect(getManagerImage(obj)).To(Equal(image))
+	}
+
+	// test the registry of the rendered image is rewritten
+	obj, err := util.GetManifestObject(AuditFile)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(r.setDefaultImage(obj, util.DefaultGatekeeperVersion, AuditFile)).To(Succeed())
+	g.Expect(crOverrides(gatekeeper, AuditFile, obj, namespace, false)).To(Succeed())
+	g.Expect(getManagerImage(obj)).To(Equal("mirror.example.com/gatekeeper/gatekeeper:v3.3.0"))
+	renderedImage, err := r.getRenderedImage(gatekeeper, AuditFile)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(renderedImage).To(Equal("mirror.example.com/gatekeeper/gatekeeper:v3.3.0"))
+
+	// test the rewritten image is pinned and reported in the status
+	gatekeeper.Spec.Image.DigestPinning = &enabled
+	gatekeeper.Status.ImageDigests = []operatorv1alpha1.ImageDigest{{Image: renderedImage, Digest: "sha256:1234"}}
+	obj, err = util.GetManifestObject(AuditFile)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(r.setDefaultImage(obj, util.DefaultGatekeeperVersion, AuditFile)).To(Succeed())
+	g.Expect(crOverrides(gatekeeper, AuditFile, obj, namespace, false)).To(Succeed())
+	g.Expect(setStatusImages(gatekeeper, AuditFile, obj)).To(Succeed())
+	g.Expect(gatekeeper.Status.Images).To(Equal(&operatorv1alpha1.ComponentImages{
+		Audit: "mirror.example.com/gatekeeper/gatekeeper@sha256:1234",
+	}))
+}
@@ -0,0 +1,84 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	operatorv1alpha1 "github.com/gatekeeper/gatekeeper-operator/api/v1alpha1"
+)
+
+const (
+	RemovedReason = "Removed"
+)
+
+func getManagementState(spec operatorv1alpha1.GatekeeperSpec) operatorv1alpha1.ManagementState {
+	if spec.ManagementState == nil {
+		return operatorv1alpha1.ManagementStateManaged
+	}
+	return *spec.ManagementState
+}
+
+// reconcileUnmanaged reports the status of the Gatekeeper resources without
+// writing any of them.
+func (r *GatekeeperReconciler) reconcileUnmanaged(ctx context.Context, gatekeeper *operatorv1alpha1.Gatekeeper, logger logr.Logger) (ctrl.Result, error) {
+	logger.Info("Gatekeeper is unmanaged, only reporting its status")
+	ready, err := r.updateStatus(ctx, gatekeeper)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !ready {
+		return ctrl.Result{RequeueAfter: notReadyRequeueInterval}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// reconcileRemoved removes the Gatekeeper resources as when the Gatekeeper
+// resource is deleted, while keeping the Gatekeeper resource and its
+// finalizer, and reports Gatekeeper as removed.
+func (r *GatekeeperReconciler) reconcileRemoved(ctx context.Context, gatekeeper *operatorv1alpha1.Gatekeeper, logger logr.Logger) (ctrl.Result, error) {
+	logger.Info("Removing Gatekeeper", "uninstallPolicy", getUninstallPolicy(gatekeeper))
+	removed, err := r.removeGatekeeperResources(ctx, gatekeeper, logger)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !removed {
+		return ctrl.Result{RequeueAfter: uninstallRequeueInterval}, nil
+	}
+
+	condition := notReadyCondition(RemovedReason, "Gatekeeper is removed by the Removed management state")
+	now := metav1.Now()
+	gatekeeper.Status.ObservedGeneration = gatekeeper.GetGeneration()
+	gatekeeper.Status.AuditConditions = setStatusCondition(gatekeeper.Status.AuditConditions, condition, now)
+	gatekeeper.Status.WebhookConditions = setStatusCondition(gatekeeper.Status.WebhookConditions, condition, now)
+	gatekeeper.Status.Certificates = nil
+	gatekeeper.Status.Conflicts = nil
+	gatekeeper.Status.DegradedConditions = nil
+	gatekeeper.Status.Version = ""
+	// Deploying Gatekeeper again is an install rather than an upgrade.
+	gatekeeper.Status.LastKnownGood = nil
+	gatekeeper.Status.Upgrade = nil
+
+	if err := r.Status().Update(ctx, gatekeeper); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "Unable to update Gatekeeper status")
+	}
+	logger.Info("Removed Gatekeeper")
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,239 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/gatekeeper/gatekeeper-operator/api/v1alpha1"
+	"github.com/gatekeeper/gatekeeper-operator/pkg/util"
+)
+
+// objectsClient serves the Gatekeeper resource along with the cluster objects
+// of the given kinds and names, owned by the Gatekeeper resource when owned is
+// set, and records the writes it receives.
+type objectsClient struct {
+	client.Client
+	gatekeeper *operatorv1alpha1.Gatekeeper
+	objects    map[string]bool
+	owned      bool
+	writes     []string
+	statuses   []operatorv1alpha1.GatekeeperStatus
+}
+
+func objectKey(kind, name string) string {
+	return kind + "/" + name
+}
+
+func (c *objectsClient) Get(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+	switch o := obj.(type) {
+	case *operatorv1alpha1.Gatekeeper:
+		c.gatekeeper.DeepCopyInto(o)
+		return nil
+	case *unstructured.Unstructured:
+		if c.objects[objectKey(o.GetKind(), key.Name)] {
+			o.SetName(key.Name)
+			o.SetNamespace(key.Namespace)
+			if c.owned {
+				o.SetOwnerReferences([]metav1.OwnerReference{{Name: c.gatekeeper.GetName(), UID: c.gatekeeper.GetUID()}})
+			}
+			return nil
+		}
+	}
+	return apierrors.NewNotFound(schema.GroupResource{}, key.Name)
+}
+
+func (c *objectsClient) List(context.Context, runtime.Object, ...client.ListOption) error {
+	return nil
+}
+
+func (c *objectsClient) record(operation string, obj runtime.Object) {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	name := ""
+	if o, ok := obj.(*unstructured.Unstructured); ok {
+		name = o.GetName()
+	}
+	c.writes = append(c.writes, fmt.Sprintf("%s %s", operation, objectKey(kind, name)))
+}
+
+func (c *objectsClient) Create(_ context.Context, obj runtime.Object, _ ...client.CreateOption) error {
+	c.record("create", obj)
+	return nil
+}
+
+func (c *objectsClient) Update(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+	c.record("update", obj)
+	if gatekeeper, ok := obj.(*operatorv1alpha1.Gatekeeper); ok {
+		c.gatekeeper = gatekeeper.DeepCopy()
+	}
+	return nil
+}
+
+func (c *objectsClient) Patch(_ context.Context, obj runtime.Object, _ client.Patch, _ ...client.PatchOption) error {
+	c.record("patch", obj)
+	return nil
+}
+
+func (c *objectsClient) Delete(_ context.Context, obj runtime.Object, _ ...client.DeleteOption) error {
+	c.record("delete", obj)
+	if o, ok := obj.(*unstructured.Unstructured); ok {
+		c.objects[objectKey(o.GetKind(), o.GetName())] = false
+	}
+	return nil
+}
+
+func (c *objectsClient) Status() client.StatusWriter {
+	return &objectsStatusWriter{c}
+}
+
+type objectsStatusWriter struct {
+	*objectsClient
+}
+
+func (w *objectsStatusWriter) Update(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+	w.statuses = append(w.statuses, obj.(*operatorv1alpha1.Gatekeeper).Status)
+	return nil
+}
+
+func newManagedGatekeeper(state operatorv1alpha1.ManagementState) *operatorv1alpha1.Gatekeeper {
+	gatekeeper := &operatorv1alpha1.Gatekeeper{}
+	gatekeeper.SetName(defaultGatekeeperCrName)
+	gatekeeper.SetFinalizers([]string{gatekeeperFinalizer})
+	gatekeeper.Spec.ManagementState = &state
+	return gatekeeper
+}
+
+func TestGetManagementState(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(getManagementState(operatorv1alpha1.GatekeeperSpec{})).To(Equal(operatorv1alpha1.ManagementStateManaged))
+	g.Expect(getManagementState(newManagedGatekeeper(operatorv1alpha1.ManagementStateRemoved).Spec)).
+		To(Equal(operatorv1alpha1.ManagementStateRemoved))
+}
+
+func TestReconcileUnmanaged(t *testing.T) {
+	g := NewWithT(t)
+	objectsClient := &objectsClient{
+		gatekeeper: newManagedGatekeeper(operatorv1alpha1.ManagementStateUnmanaged),
+		objects:    map[string]bool{},
+	}
+	r := &GatekeeperReconciler{
+		Client:    objectsClient,
+		Log:       ctrl.Log.WithName("test"),
+		Namespace: namespace,
+	}
+
+	result, err := r.Reconcile(ctrl.Request{NamespacedName: types.NamespacedName{Name: defaultGatekeeperCrName}})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(Equal(notReadyRequeueInterval))
+
+	// test nothing is written but the status
+	g.Expect(objectsClient.writes).To(BeEmpty())
+	g.Expect(objectsClient.statuses).To(HaveLen(1))
+	g.Expect(objectsClient.statuses[0].AuditConditions[0].Reason).To(Equal(DeploymentNotFoundReason))
+	g.Expect(objectsClient.statuses[0].WebhookConditions[0].Reason).To(Equal(DeploymentNotFoundReason))
+}
+
+func TestReconcileRemoved(t *testing.T) {
+	g := NewWithT(t)
+	webhookConfiguration, err := util.GetManifestObject(ValidatingWebhookConfiguration)
+	g.Expect(err).ToNot(HaveOccurred())
+	webhookConfigurationKey := objectKey(webhookConfiguration.GetKind(), webhookConfiguration.GetName())
+	auditKey := objectKey("Deployment", "gatekeeper-audit")
+	crdKey := objectKey("CustomResourceDefinition", "constrainttemplates.templates.gatekeeper.sh")
+	objectsClient := &objectsClient{
+		gatekeeper: newManagedGatekeeper(operatorv1alpha1.ManagementStateRemoved),
+		objects: map[string]bool{
+			webhookConfigurationKey: true,
+			auditKey:                true,
+			crdKey:                  true,
+		},
+	}
+	objectsClient.gatekeeper.Status.LastKnownGood = &operatorv1alpha1.GatekeeperRelease{Version: util.DefaultGatekeeperVersion}
+	r := &GatekeeperReconciler{
+		Client:    objectsClient,
+		Log:       ctrl.Log.WithName("test"),
+		Namespace: namespace,
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: defaultGatekeeperCrName}}
+	result, err := r.Reconcile(req)
+	for i := 0; err == nil && result.RequeueAfter > 0 && i < len(uninstallStages); i++ {
+		result, err = r.Reconcile(req)
+	}
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result).To(Equal(ctrl.Result{}))
+
+	// test resources are removed in order, the CRDs and finalizer are kept
+	g.Expect(objectsClient.writes).To(Equal([]string{
+		"delete " + webhookConfigurationKey,
+		"delete " + auditKey,
+	}))
+	g.Expect(objectsClient.objects).To(HaveKeyWithValue(crdKey, true))
+
+	// test Gatekeeper is reported as removed
+	g.Expect(objectsClient.statuses).To(HaveLen(1))
+	status := objectsClient.statuses[0]
+	g.Expect(status.AuditConditions[0].Reason).To(Equal(RemovedReason))
+	g.Expect(status.WebhookConditions[0].Reason).To(Equal(RemovedReason))
+	g.Expect(status.LastKnownGood).To(BeNil())
+}
+
+func TestUninstallUnmanaged(t *testing.T) {
+	g := NewWithT(t)
+	auditKey := objectKey("Deployment", "gatekeeper-audit")
+	crdKey := objectKey("CustomResourceDefinition", "constrainttemplates.templates.gatekeeper.sh")
+	objectsClient := &objectsClient{
+		gatekeeper: newManagedGatekeeper(operatorv1alpha1.ManagementStateUnmanaged),
+		objects: map[string]bool{
+			auditKey: true,
+			crdKey:   true,
+		},
+		owned: true,
+	}
+	objectsClient.gatekeeper.SetUID("gatekeeper-uid")
+	now := metav1.Now()
+	objectsClient.gatekeeper.SetDeletionTimestamp(&now)
+	deleteAll := operatorv1alpha1.UninstallPolicyDeleteAll
+	objectsClient.gatekeeper.Spec.UninstallPolicy = &deleteAll
+	r := &GatekeeperReconciler{
+		Client:    objectsClient,
+		Log:       ctrl.Log.WithName("test"),
+		Namespace: namespace,
+	}
+
+	result, err := r.Reconcile(ctrl.Request{NamespacedName: types.NamespacedName{Name: defaultGatekeeperCrName}})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result).To(Equal(ctrl.Result{}))
+
+	// test the resources are orphaned rather than deleted before the
+	// finalizer is released
+	g.Expect(objectsClient.writes).To(HaveLen(3))
+	g.Expect(objectsClient.writes[:2]).To(ConsistOf("update "+auditKey, "update "+crdKey))
+	g.Expect(objectsClient.gatekeeper.GetFinalizers()).To(BeEmpty())
+	g.Expect(objectsClient.objects).To(HaveKeyWithValue(auditKey, true))
+	g.Expect(objectsClient.objects).To(HaveKeyWithValue(crdKey, true))
+}
@@ -17,97 +17,50 @@ limitations under the License.
 package merge
 
 import (
-	"fmt"
+	"encoding/json"
 
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-
-	"github.com/gatekeeper/gatekeeper-operator/pkg/util"
 )
 
-// RetainClusterObjectFields updates the desired object with values retained
-// from the cluster object.
-func RetainClusterObjectFields(desiredObj, clusterObj *unstructured.Unstructured) error {
-	// Pass the same ResourceVersion as in the cluster object for update
-	// operation, otherwise operation will fail.
-	desiredObj.SetResourceVersion(clusterObj.GetResourceVersion())
-
-	switch desiredObj.GetKind() {
-	case util.ServiceKind:
-		return retainServiceFields(desiredObj, clusterObj)
-	case util.ValidatingWebhookConfigurationKind:
-		fallthrough
-	case util.MutatingWebhookConfigurationKind:
-		return retainWebhookConfigurationFields(desiredObj, clusterObj)
-	default:
-		return nil
-	}
-}
-func retainServiceFields(desiredObj, clusterObj *unstructured.Unstructured) error {
-	// ClusterIP is allocated to Service by cluster, so if it exists, retain it
-	// while updating.
-	clusterIP, ok, err := unstructured.NestedString(clusterObj.Object, "spec", "clusterIP")
-	if err != nil {
-		return errors.Wrap(err, "Error retrieving clusterIP from cluster service")
-	} else if ok && clusterIP != "" {
-		err := unstructured.SetNestedField(desiredObj.Object, clusterIP, "spec", "clusterIP")
+// ReleaseForeignFields removes the given fields from the desired object when
+// a field manager other than the given one manages them on the cluster
+// object. Applying the desired object with server-side apply then releases
+// the fields to the other field managers, e.g. a HorizontalPodAutoscaler
+// scaling a Deployment, instead of taking them over. Fields only managed by
+// the given field manager are kept so that their rendered value is applied.
+func ReleaseForeignFields(desiredObj, clusterObj *unstructured.Unstructured, manager string, fields ...[]string) error {
+	for _, field := range fields {
+		managed, err := isManagedByOthers(clusterObj, manager, field)
 		if err != nil {
-			return errors.Wrap(err, "Error setting clusterIP for service")
+			return err
 		}
-	} // !ok could indicate that a clusterIP was not assigned
-
+		if managed {
+			unstructured.RemoveNestedField(desiredObj.Object, field...)
+		}
+	}
 	return nil
 }
 
-func retainWebhookConfigurationFields(desiredObj, clusterObj *unstructured.Unstructured) error {
-	// Retain each webhook's CABundle
-	clusterWebhooks, ok, err := unstructured.NestedSlice(clusterObj.Object, "webhooks")
-	if err != nil {
-		return errors.Wrapf(err, "Error retrieving webhooks from cluster object %s", clusterObj.GetKind())
-	} else if ok && len(clusterWebhooks) == 0 {
-		err = unstructured.SetNestedSlice(desiredObj.Object, nil, "webhooks")
-		if err != nil {
-			return errors.Wrapf(err, "Error setting webhooks for desired object %s", desiredObj.GetKind())
+// isManagedByOthers returns whether a field manager other than the given
+// one manages the given field of the object.
+func isManagedByOthers(obj *unstructured.Unstructured, manager string, field []string) (bool, error) {
+	for _, entry := range obj.GetManagedFields() {
+		if entry.Manager == manager || entry.FieldsV1 == nil {
+			continue
 		}
-		return nil
-	} else if !ok {
-		return nil
-	}
-
-	desiredWebhooks, ok, err := unstructured.NestedSlice(desiredObj.Object, "webhooks")
-	if err != nil {
-		return errors.Wrapf(err, "Error retrieving webhooks from desired object %s", desiredObj.GetKind())
-	} else if !ok {
-		// Should never happen
-		return fmt.Errorf("webhooks field not found for desired object %s", desiredObj.GetKind())
-	}
-
-	for i := range desiredWebhooks {
-		for j := range clusterWebhooks {
-			desiredWebhook := desiredWebhooks[i].(map[string]interface{})
-			clusterWebhook := clusterWebhooks[j].(map[string]interface{})
-			if desiredWebhook["name"] != clusterWebhook["name"] {
-				continue
-			}
-
-			caBundle, ok, err := unstructured.NestedFieldNoCopy(clusterWebhook, "clientConfig", "caBundle")
-			if err != nil {
-				return errors.Wrapf(err, "Error retrieving webhooks[%d].clientConfig.caBundle from cluster object %s", j, clusterObj.GetKind())
-			} else if !ok {
-				return fmt.Errorf("webhooks[%d].clientConfig.caBundle field not found for cluster object %s", j, clusterObj.GetKind())
-			}
-
-			err = unstructured.SetNestedField(desiredWebhook, caBundle, "clientConfig", "caBundle")
-			if err != nil {
-				return errors.Wrapf(err, "Error setting webhooks[%d].clientConfig.caBundle for desired object %s", i, desiredObj.GetKind())
-			}
-			break
+		fieldSet := map[string]interface{}{}
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &fieldSet); err != nil {
+			return false, errors.Wrapf(err, "Error decoding the fields managed by %s on %s %s",
+				entry.Manager, obj.GetKind(), obj.GetName())
+		}
+		path := make([]string, 0, len(field))
+		for _, f := range field {
+			path = append(path, "f:"+f)
+		}
+		if _, found, _ := unstructured.NestedFieldNoCopy(fieldSet, path...); found {
+			return true, nil
 		}
 	}
-
-	err = unstructured.SetNestedSlice(desiredObj.Object, desiredWebhooks, "webhooks")
-	if err != nil {
-		return errors.Wrapf(err, "Error setting webhooks for desired object %s", desiredObj.GetKind())
-	}
-	return nil
+	return false, nil
 }
@@ -18,73 +18,70 @@ import (
 	"testing"
 
 	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-
-	"github.com/gatekeeper/gatekeeper-operator/pkg/util"
 )
 
-func TestRetainClusterObjectFields(t *testing.T) {
-	g := NewWithT(t)
+func TestReleaseForeignFields(t *testing.T) {
+	replicas := []string{"spec", "replicas"}
+	replicasFields := &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:replicas":{}}}`)}
+	templateFields := &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:template":{}}}`)}
 
 	testCases := map[string]struct {
-		desiredCABundle string
-		clusterCABundle string
+		managedFields []metav1.ManagedFieldsEntry
+		released      bool
 	}{
-		"cluster CABundle is empty": {
-			desiredCABundle: "ZGVzaXJlZCBkZWZhdWx0IHZhbHVlCg==",
-			clusterCABundle: "Cg==",
+		"replicas are not managed": {
+			managedFields: nil,
+			released:      false,
+		},
+		"replicas are managed by the operator": {
+			managedFields: []metav1.ManagedFieldsEntry{
+				{Manager: "gatekeeper-operator", FieldsV1: replicasFields},
+				{Manager: "kubectl", FieldsV1: templateFields},
+			},
+			released: false,
 		},
-		"cluster CABundle is set": {
-			desiredCABundle: "Cg==",
-			clusterCABundle: "Y2x1c3RlciBDQUJ1bmRsZSBpcyBzZXQK",
+		"replicas are managed by another field manager": {
+			managedFields: []metav1.ManagedFieldsEntry{
+				{Manager: "gatekeeper-operator", FieldsV1: templateFields},
+				{Manager: "kube-controller-manager", FieldsV1: replicasFields},
+			},
+			released: true,
 		},
 	}
 
-	webhookConfigKinds := []string{
-		util.ValidatingWebhookConfigurationKind,
-		util.MutatingWebhookConfigurationKind,
+	deployment := func() *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"kind": "Deployment",
+				"spec": map[string]interface{}{
+					"replicas": int64(3),
+					"template": map[string]interface{}{},
+				},
+			},
+		}
 	}
-	for testName, testCase := range testCases {
-		for _, kind := range webhookConfigKinds {
-			t.Run(testName, func(t *testing.T) {
-				desiredObj := &unstructured.Unstructured{
-					Object: map[string]interface{}{
-						"kind": kind,
-						"webhooks": []interface{}{
-							map[string]interface{}{
-								"clientConfig": map[string]interface{}{
-									"caBundle": testCase.desiredCABundle,
-								},
-							},
-						},
-					},
-				}
-				clusterObj := &unstructured.Unstructured{
-					Object: map[string]interface{}{
-						"kind": kind,
-						"webhooks": []interface{}{
-							map[string]interface{}{
-								"clientConfig": map[string]interface{}{
-									"caBundle": testCase.clusterCABundle,
-								},
-							},
-						},
-					},
-				}
 
-				err := RetainClusterObjectFields(desiredObj, clusterObj)
-				g.Expect(err).ToNot(HaveOccurred())
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			g := NewWithT(t)
+			desiredObj := deployment()
+			clusterObj := deployment()
+			clusterObj.SetManagedFields(testCase.managedFields)
 
-				desiredWebhooks, found, err := unstructured.NestedSlice(desiredObj.Object, "webhooks")
-				g.Expect(err).ToNot(HaveOccurred())
-				g.Expect(found).To(BeTrue())
-				g.Expect(desiredWebhooks).ToNot(BeNil())
+			err := ReleaseForeignFields(desiredObj, clusterObj, "gatekeeper-operator", replicas)
+			g.Expect(err).ToNot(HaveOccurred())
 
-				desiredCABundle, found, err := unstructured.NestedString(desiredWebhooks[0].(map[string]interface{}), "clientConfig", "caBundle")
-				g.Expect(err).ToNot(HaveOccurred())
-				g.Expect(found).To(BeTrue())
-				g.Expect(desiredCABundle).To(Equal(testCase.clusterCABundle))
-			})
-		}
+			desiredReplicas, found, err := unstructured.NestedFieldNoCopy(desiredObj.Object, replicas...)
+			g.Expect(err).ToNot(HaveOccurred())
+			if testCase.released {
+				g.Expect(found).To(BeFalse())
+			} else {
+				g.Expect(desiredReplicas).To(Equal(int64(3)))
+			}
+			// Other fields are left untouched.
+			g.Expect(desiredObj.Object["spec"]).To(HaveKey("template"))
+		})
 	}
 }
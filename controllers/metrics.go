@@ -0,0 +1,46 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	driftCorrections = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "gatekeeper_operator_drift_corrections_total",
+			Help: "Number of Gatekeeper resources restored to their desired state after being changed or deleted outside of the operator",
+		},
+		[]string{"kind"},
+	)
+	appliedResources = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "gatekeeper_operator_applied_resources_total",
+			Help: "Number of Gatekeeper resources applied because they were not up to date",
+		},
+	)
+	skippedResources = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "gatekeeper_operator_skipped_resources_total",
+			Help: "Number of Gatekeeper resources not applied because they were already up to date",
+		},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(driftCorrections, appliedResources, skippedResources)
+}
@@ -0,0 +1,176 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	operatorv1alpha1 "github.com/gatekeeper/gatekeeper-operator/api/v1alpha1"
+)
+
+func isPodDisruptionBudgetAsset(asset string) bool {
+	return asset == AuditPodDisruptionBudgetFile || asset == WebhookPodDisruptionBudgetFile
+}
+
+// getPodDisruptionBudget returns the PodDisruptionBudget configured for the
+// component of the given asset, or nil to keep the budget of the manifests.
+func getPodDisruptionBudget(spec operatorv1alpha1.GatekeeperSpec, asset string) *operatorv1alpha1.PodDisruptionBudgetConfig {
+	switch {
+	case asset == AuditPodDisruptionBudgetFile && spec.Audit != nil:
+		return spec.Audit.PodDisruptionBudget
+	case asset == WebhookPodDisruptionBudgetFile && spec.Webhook != nil:
+		return spec.Webhook.PodDisruptionBudget
+	}
+	return nil
+}
+
+// podDisruptionBudgetEnabled returns whether the PodDisruptionBudget of the
+// given asset is deployed for the given replicas. The audit only has one
+// when configured. The webhook has one unless it is left to the default
+// budget, which keeps one pod available, with at most one replica, as the
+// budget would then block every eviction and with it node drains.
+func podDisruptionBudgetEnabled(spec operatorv1alpha1.GatekeeperSpec, asset string, replicas int32) bool {
+	if getPodDisruptionBudget(spec, asset) != nil {
+		return true
+	}
+	return asset == WebhookPodDisruptionBudgetFile && replicas > 1
+}
+
+// getDisabledPodDisruptionBudgets returns the PodDisruptionBudget assets not
+// to deploy for the replicas the components run with. Configured budgets are
+// validated against the spec replicas when the Gatekeeper resource is
+// admitted, but the replicas can since have been scaled, e.g. by a
+// HorizontalPodAutoscaler. A configured budget that would then block every
+// eviction is left out as well and reported by the returned Degraded
+// condition.
+func (r *GatekeeperReconciler) getDisabledPodDisruptionBudgets(ctx context.Context,
+	gatekeeper *operatorv1alpha1.Gatekeeper) ([]string, *operatorv1alpha1.StatusCondition, error) {
+	disabled := make([]string, 0)
+	var messages []string
+	for _, a := range podDisruptionBudgetStaticAssets {
+		spec := getRenderedGatekeeper(gatekeeper, a).Spec
+		replicas, err := r.getComponentReplicas(ctx, gatekeeper, a)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !podDisruptionBudgetEnabled(spec, a, replicas) {
+			disabled = append(disabled, a)
+			continue
+		}
+
+		path := field.NewPath("spec", "webhook", "podDisruptionBudget")
+		if a == AuditPodDisruptionBudgetFile {
+			path = field.NewPath("spec", "audit", "podDisruptionBudget")
+		}
+		errs := operatorv1alpha1.ValidatePodDisruptionBudget(getPodDisruptionBudget(spec, a), replicas, path)
+		if len(errs) > 0 {
+			disabled = append(disabled, a)
+			messages = append(messages, errs.ToAggregate().Error())
+		}
+	}
+
+	if len(messages) == 0 {
+		return disabled, nil, nil
+	}
+	return disabled, &operatorv1alpha1.StatusCondition{
+		Type:    operatorv1alpha1.StatusDegraded,
+		Status:  corev1.ConditionTrue,
+		Reason:  PodDisruptionBudgetSkippedReason,
+		Message: strings.Join(messages, "; "),
+	}, nil
+}
+
+// getComponentReplicas returns the replicas of the component of the given
+// PodDisruptionBudget asset. When the spec leaves them unset, they are left
+// to any other field manager, e.g. a HorizontalPodAutoscaler, so the
+// replicas of the cluster Deployment are used.
+func (r *GatekeeperReconciler) getComponentReplicas(ctx context.Context, gatekeeper *operatorv1alpha1.Gatekeeper,
+	asset string) (int32, error) {
+	spec := getRenderedGatekeeper(gatekeeper, asset).Spec
+	deploymentAsset := WebhookFile
+	replicas := operatorv1alpha1.GetWebhookReplicas(&spec)
+	replicasSet := spec.Webhook != nil && spec.Webhook.Replicas != nil
+	if asset == AuditPodDisruptionBudgetFile {
+		deploymentAsset = AuditFile
+		replicas = operatorv1alpha1.GetAuditReplicas(&spec)
+		replicasSet = spec.Audit != nil && spec.Audit.Replicas != nil
+	}
+	if replicasSet {
+		return replicas, nil
+	}
+
+	obj, err := r.getVersionedManifestObject(getManifestVersion(gatekeeper, deploymentAsset), deploymentAsset)
+	if err != nil {
+		return 0, err
+	}
+	namespacedName := types.NamespacedName{
+		Namespace: r.Namespace,
+		Name:      obj.GetName(),
+	}
+	deployment := &appsv1.Deployment{}
+	if err := r.Get(ctx, namespacedName, deployment); err != nil {
+		if apierrors.IsNotFound(err) {
+			return replicas, nil
+		}
+		return 0, errors.Wrapf(err, "Unable to get deployment %s", namespacedName)
+	}
+	if deployment.Spec.Replicas != nil {
+		return *deployment.Spec.Replicas, nil
+	}
+	return replicas, nil
+}
+
+// setPodDisruptionBudget sets the minAvailable or the maxUnavailable of the
+// given PodDisruptionBudget, removing the other one.
+func setPodDisruptionBudget(obj *unstructured.Unstructured, pdb *operatorv1alpha1.PodDisruptionBudgetConfig) error {
+	if pdb == nil {
+		return nil
+	}
+	fields := []struct {
+		name  string
+		value *intstr.IntOrString
+	}{
+		{"minAvailable", pdb.MinAvailable},
+		{"maxUnavailable", pdb.MaxUnavailable},
+	}
+	for _, f := range fields {
+		if f.value == nil {
+			unstructured.RemoveNestedField(obj.Object, "spec", f.name)
+			continue
+		}
+		if err := unstructured.SetNestedField(obj.Object, intOrStringValue(f.value), "spec", f.name); err != nil {
+			return errors.Wrapf(err, "Failed to set PodDisruptionBudget %s", f.name)
+		}
+	}
+	return nil
+}
+
+// intOrStringValue returns the unstructured value of the given IntOrString.
+func intOrStringValue(v *intstr.IntOrString) interface{} {
+	if v.Type == intstr.String {
+		return v.StrVal
+	}
+	return int64(v.IntVal)
+}
@@ -0,0 +1,150 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1alpha1 "github.com/gatekeeper/gatekeeper-operator/api/v1alpha1"
+	"github.com/gatekeeper/gatekeeper-operator/pkg/util"
+)
+
+func TestPodDisruptionBudgetAssets(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	r := &GatekeeperReconciler{
+		Client:    fake.NewFakeClient(),
+		Log:       ctrl.Log.WithName("test"),
+		Namespace: namespace,
+	}
+	gatekeeper := &operatorv1alpha1.Gatekeeper{}
+
+	// test the default webhook budget is deployed with the default replicas
+	disabled, degraded, err := r.getDisabledPodDisruptionBudgets(ctx, gatekeeper)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(disabled).To(ConsistOf(AuditPodDisruptionBudgetFile))
+	g.Expect(degraded).To(BeNil())
+
+	// test the default webhook budget is removed with a single replica
+	replicas := int32(1)
+	gatekeeper.Spec.Webhook = &operatorv1alpha1.WebhookConfig{Replicas: &replicas}
+	disabled, _, err = r.getDisabledPodDisruptionBudgets(ctx, gatekeeper)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(disabled).To(ConsistOf(AuditPodDisruptionBudgetFile, WebhookPodDisruptionBudgetFile))
+
+	// test configured budgets are deployed
+	maxUnavailable := intstr.FromInt(1)
+	pdb := &operatorv1alpha1.PodDisruptionBudgetConfig{MaxUnavailable: &maxUnavailable}
+	gatekeeper.Spec.Webhook.PodDisruptionBudget = pdb
+	gatekeeper.Spec.Audit = &operatorv1alpha1.AuditConfig{PodDisruptionBudget: pdb}
+	disabled, degraded, err = r.getDisabledPodDisruptionBudgets(ctx, gatekeeper)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(disabled).To(BeEmpty())
+	g.Expect(degraded).To(BeNil())
+}
+
+func TestPodDisruptionBudgetScaledReplicas(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	replicas := int32(1)
+	r := &GatekeeperReconciler{
+		Client: fake.NewFakeClient(&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "gatekeeper-controller-manager", Namespace: namespace},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		}),
+		Log:       ctrl.Log.WithName("test"),
+		Namespace: namespace,
+	}
+	gatekeeper := &operatorv1alpha1.Gatekeeper{}
+
+	// test the replicas scaled by another field manager are used when unset
+	replicas, err := r.getComponentReplicas(ctx, gatekeeper, WebhookPodDisruptionBudgetFile)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(replicas).To(Equal(int32(1)))
+	replicas, err = r.getComponentReplicas(ctx, gatekeeper, AuditPodDisruptionBudgetFile)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(replicas).To(BeEquivalentTo(operatorv1alpha1.DefaultAuditReplicas))
+	disabled, degraded, err := r.getDisabledPodDisruptionBudgets(ctx, gatekeeper)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(disabled).To(ConsistOf(AuditPodDisruptionBudgetFile, WebhookPodDisruptionBudgetFile))
+	g.Expect(degraded).To(BeNil())
+
+	// test a configured budget blocking every eviction is skipped
+	minAvailable := intstr.FromInt(1)
+	gatekeeper.Spec.Webhook = &operatorv1alpha1.WebhookConfig{
+		PodDisruptionBudget: &operatorv1alpha1.PodDisruptionBudgetConfig{MinAvailable: &minAvailable},
+	}
+	disabled, degraded, err = r.getDisabledPodDisruptionBudgets(ctx, gatekeeper)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(disabled).To(ContainElement(WebhookPodDisruptionBudgetFile))
+	g.Expect(degraded).ToNot(BeNil())
+	g.Expect(degraded.Type).To(Equal(operatorv1alpha1.StatusDegraded))
+	g.Expect(degraded.Reason).To(Equal(PodDisruptionBudgetSkippedReason))
+	g.Expect(degraded.Message).To(ContainSubstring("spec.webhook.podDisruptionBudget.minAvailable"))
+
+	// test the spec replicas take precedence
+	specReplicas := int32(3)
+	gatekeeper.Spec.Webhook.Replicas = &specReplicas
+	disabled, degraded, err = r.getDisabledPodDisruptionBudgets(ctx, gatekeeper)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(disabled).ToNot(ContainElement(WebhookPodDisruptionBudgetFile))
+	g.Expect(degraded).To(BeNil())
+}
+
+func TestPodDisruptionBudget(t *testing.T) {
+	g := NewWithT(t)
+	gatekeeper := &operatorv1alpha1.Gatekeeper{}
+
+	// test the default webhook budget
+	obj, err := util.GetManifestObject(WebhookPodDisruptionBudgetFile)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(crOverrides(gatekeeper, WebhookPodDisruptionBudgetFile, obj, namespace, false)).To(Succeed())
+	g.Expect(obj.GetNamespace()).To(Equal(namespace))
+	minAvailable, found, err := unstructured.NestedInt64(obj.Object, "spec", "minAvailable")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(found).To(BeTrue())
+	g.Expect(minAvailable).To(Equal(int64(1)))
+
+	// test maxUnavailable replaces minAvailable
+	maxUnavailable := intstr.FromString("34%")
+	gatekeeper.Spec.Webhook = &operatorv1alpha1.WebhookConfig{
+		PodDisruptionBudget: &operatorv1alpha1.PodDisruptionBudgetConfig{MaxUnavailable: &maxUnavailable},
+	}
+	g.Expect(crOverrides(gatekeeper, WebhookPodDisruptionBudgetFile, obj, namespace, false)).To(Succeed())
+	g.Expect(obj.Object["spec"]).ToNot(HaveKey("minAvailable"))
+	g.Expect(obj.Object["spec"]).To(HaveKeyWithValue("maxUnavailable", "34%"))
+
+	// test the audit budget
+	minAvailableValue := intstr.FromInt(2)
+	replicas := int32(3)
+	gatekeeper.Spec.Audit = &operatorv1alpha1.AuditConfig{
+		Replicas:            &replicas,
+		PodDisruptionBudget: &operatorv1alpha1.PodDisruptionBudgetConfig{MinAvailable: &minAvailableValue},
+	}
+	obj, err = util.GetManifestObject(AuditPodDisruptionBudgetFile)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(crOverrides(gatekeeper, AuditPodDisruptionBudgetFile, obj, namespace, false)).To(Succeed())
+	g.Expect(obj.Object["spec"]).ToNot(HaveKey("maxUnavailable"))
+	g.Expect(obj.Object["spec"]).To(HaveKeyWithValue("minAvailable", int64(2)))
+}
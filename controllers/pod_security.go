@@ -0,0 +1,217 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1alpha1 "github.com/gatekeeper/gatekeeper-operator/api/v1alpha1"
+)
+
+const (
+	PodSecurityEnforceLabel = "pod-security.kubernetes.io/enforce"
+	PodSecurityAuditLabel   = "pod-security.kubernetes.io/audit"
+	PodSecurityWarnLabel    = "pod-security.kubernetes.io/warn"
+	// podSecurityLabelSyncLabel disables the OpenShift controller that
+	// otherwise overwrites the Pod Security Admission labels of a namespace
+	// from the security context constraints available to its pods.
+	podSecurityLabelSyncLabel = "security.openshift.io/scc.podSecurityLabelSync"
+	// podSecurityAnnotation marks the namespace labeled by the operator so
+	// that the labels are only removed from it.
+	podSecurityAnnotation = "operator.gatekeeper.sh/pod-security"
+)
+
+var podSecurityLabels = []string{
+	PodSecurityEnforceLabel,
+	PodSecurityAuditLabel,
+	PodSecurityWarnLabel,
+	podSecurityLabelSyncLabel,
+}
+
+const (
+	privilegedSecurityContextConstraints = "privileged"
+	restrictedSecurityContextConstraints = "restricted"
+	// restrictedV2SecurityContextConstraints replaces restricted as of
+	// OpenShift 4.11.
+	restrictedV2SecurityContextConstraints = "restricted-v2"
+)
+
+var securityContextConstraintsGVK = schema.GroupVersionKind{
+	Group:   "security.openshift.io",
+	Version: "v1",
+	Kind:    "SecurityContextConstraints",
+}
+
+// getPodSecurityLevel returns the Pod Security Standard level of the given
+// spec, or an empty level when the Pod Security Admission labels are left
+// untouched.
+func getPodSecurityLevel(spec operatorv1alpha1.GatekeeperSpec) operatorv1alpha1.PodSecurityLevel {
+	if spec.PodSecurityLevel == nil {
+		return ""
+	}
+	return *spec.PodSecurityLevel
+}
+
+// getSecurityContextConstraints returns the OpenShift security context
+// constraints admitting the Gatekeeper pods at the given level. The privileged
+// constraints are only used when the privileged level is requested, the
+// others use restricted-v2, or restricted on clusters that predate it.
+func (r *GatekeeperReconciler) getSecurityContextConstraints(ctx context.Context,
+	level operatorv1alpha1.PodSecurityLevel) (string, error) {
+	if level == operatorv1alpha1.PodSecurityPrivileged {
+		return privilegedSecurityContextConstraints, nil
+	}
+	scc := &unstructured.Unstructured{}
+	scc.SetGroupVersionKind(securityContextConstraintsGVK)
+	err := r.uncachedReader().Get(ctx, types.NamespacedName{Name: restrictedV2SecurityContextConstraints}, scc)
+	if apierrors.IsNotFound(err) {
+		return restrictedSecurityContextConstraints, nil
+	}
+	if err != nil {
+		return "", errors.Wrapf(err, "Unable to get security context constraints %s", restrictedV2SecurityContextConstraints)
+	}
+	return restrictedV2SecurityContextConstraints, nil
+}
+
+// reconcilePodSecurityLabels sets the Pod Security Admission labels of the
+// Gatekeeper namespace to the given level. The labels set by the operator are
+// removed when the level is empty.
+func (r *GatekeeperReconciler) reconcilePodSecurityLabels(ctx context.Context, level operatorv1alpha1.PodSecurityLevel) error {
+	ns := &corev1.Namespace{}
+	err := r.uncachedReader().Get(ctx, types.NamespacedName{Name: r.Namespace}, ns)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "Unable to get namespace %s", r.Namespace)
+	}
+	if level == "" && ns.GetAnnotations()[podSecurityAnnotation] != "true" {
+		return nil
+	}
+
+	labels, annotations := ns.GetLabels(), ns.GetAnnotations()
+	setPodSecurityLabels(ns, level, r.isOpenShift())
+	if reflect.DeepEqual(labels, ns.GetLabels()) && reflect.DeepEqual(annotations, ns.GetAnnotations()) {
+		return nil
+	}
+	if err = r.Update(ctx, ns); err != nil {
+		return errors.Wrapf(err, "Unable to set Pod Security Admission labels of namespace %s", r.Namespace)
+	}
+	return nil
+}
+
+func setPodSecurityLabels(ns *corev1.Namespace, level operatorv1alpha1.PodSecurityLevel, isOpenshift bool) {
+	labels := ns.GetLabels()
+	for _, l := range podSecurityLabels {
+		labels = withoutKey(labels, l)
+	}
+	annotations := withoutKey(ns.GetAnnotations(), podSecurityAnnotation)
+	if level != "" {
+		labels[PodSecurityEnforceLabel] = string(level)
+		labels[PodSecurityAuditLabel] = string(level)
+		labels[PodSecurityWarnLabel] = string(level)
+		if isOpenshift {
+			labels[podSecurityLabelSyncLabel] = "false"
+		}
+		annotations[podSecurityAnnotation] = "true"
+	}
+	ns.SetLabels(labels)
+	ns.SetAnnotations(annotations)
+}
+
+// setSecurityContextConstraints grants the Gatekeeper service account the use
+// of the given OpenShift security context constraints, adding the rule to the
+// upstream Gatekeeper Role or replacing the one it has.
+func setSecurityContextConstraints(obj *unstructured.Unstructured, scc string) error {
+	rules, found, err := unstructured.NestedSlice(obj.Object, "rules")
+	if err != nil || !found {
+		return errors.Wrapf(err, "Failed to retrieve rules from role")
+	}
+
+	sccRule := map[string]interface{}{
+		"apiGroups":     []interface{}{securityContextConstraintsGVK.Group},
+		"resources":     []interface{}{"securitycontextconstraints"},
+		"resourceNames": []interface{}{scc},
+		"verbs":         []interface{}{"use"},
+	}
+	updated := false
+	for i, rule := range rules {
+		r := rule.(map[string]interface{})
+		resources, _, err := unstructured.NestedStringSlice(r, "resources")
+		if err != nil {
+			return errors.Wrapf(err, "Failed to retrieve resources from rule")
+		}
+		if len(resources) == 0 || resources[0] != "securitycontextconstraints" {
+			continue
+		}
+		rules[i] = sccRule
+		updated = true
+	}
+	if !updated {
+		rules = append(rules, sccRule)
+	}
+
+	if err := unstructured.SetNestedSlice(obj.Object, rules, "rules"); err != nil {
+		return errors.Wrapf(err, "Failed to set rules in role")
+	}
+	return nil
+}
+
+func matchPodSecurityPolicyRBACRule(rule map[string]interface{}) (bool, error) {
+	resources, found, err := unstructured.NestedStringSlice(rule, "resources")
+	if !found || err != nil {
+		return false, errors.Wrapf(err, "Failed to retrieve resources from rule")
+	}
+	return len(resources) > 0 && resources[0] == "podsecuritypolicies", nil
+}
+
+// setPodSecurityContext makes the Gatekeeper containers comply with the given
+// Pod Security Standard level. The restricted level requires the
+// RuntimeDefault seccomp profile and dropping all capabilities. On OpenShift,
+// the user and group are left to the restricted security context
+// constraints, which assign them from the range of the namespace, unless the
+// privileged level is requested.
+func setPodSecurityContext(obj *unstructured.Unstructured, level operatorv1alpha1.PodSecurityLevel, isOpenshift bool) error {
+	restricted := level == operatorv1alpha1.PodSecurityRestricted
+	assignedUser := isOpenshift && level != operatorv1alpha1.PodSecurityPrivileged
+	if !restricted && !assignedUser {
+		return nil
+	}
+	return setContainerAttrWithFn(obj, managerContainer, func(container map[string]interface{}) error {
+		if restricted {
+			err := unstructured.SetNestedField(container, "RuntimeDefault", "securityContext", "seccompProfile", "type")
+			if err != nil {
+				return errors.Wrapf(err, "Failed to set container seccomp profile")
+			}
+			err = unstructured.SetNestedStringSlice(container, []string{"ALL"}, "securityContext", "capabilities", "drop")
+			if err != nil {
+				return errors.Wrapf(err, "Failed to set container dropped capabilities")
+			}
+		}
+		if assignedUser {
+			unstructured.RemoveNestedField(container, "securityContext", "runAsUser")
+			unstructured.RemoveNestedField(container, "securityContext", "runAsGroup")
+		}
+		return nil
+	})
+}
@@ -0,0 +1,239 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1alpha1 "github.com/gatekeeper/gatekeeper-operator/api/v1alpha1"
+	"github.com/gatekeeper/gatekeeper-operator/pkg/util"
+)
+
+func TestReconcilePodSecurityLabels(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	r := &GatekeeperReconciler{
+		Client: fake.NewFakeClient(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:   namespace,
+				Labels: map[string]string{"other": "label"},
+			}},
+		),
+		Namespace:    namespace,
+		PlatformName: util.Kubernetes,
+	}
+
+	// test the namespace is left untouched by default
+	level := getPodSecurityLevel(operatorv1alpha1.GatekeeperSpec{})
+	g.Expect(level).To(BeEmpty())
+	err := r.reconcilePodSecurityLabels(ctx, level)
+	g.Expect(err).ToNot(HaveOccurred())
+	ns := &corev1.Namespace{}
+	g.Expect(r.Get(ctx, types.NamespacedName{Name: namespace}, ns)).To(Succeed())
+	g.Expect(ns.GetLabels()).To(Equal(map[string]string{"other": "label"}))
+	g.Expect(ns.GetAnnotations()).To(BeEmpty())
+
+	// test baseline
+	level = operatorv1alpha1.PodSecurityBaseline
+	err = r.reconcilePodSecurityLabels(ctx, level)
+	g.Expect(err).ToNot(HaveOccurred())
+	ns = &corev1.Namespace{}
+	g.Expect(r.Get(ctx, types.NamespacedName{Name: namespace}, ns)).To(Succeed())
+	g.Expect(ns.GetLabels()).To(Equal(map[string]string{
+		"other":                 "label",
+		PodSecurityEnforceLabel: "baseline",
+		PodSecurityAuditLabel:   "baseline",
+		PodSecurityWarnLabel:    "baseline",
+	}))
+	g.Expect(ns.GetAnnotations()).To(HaveKeyWithValue(podSecurityAnnotation, "true"))
+
+	// test OpenShift
+	r.PlatformName = util.OpenShift
+	err = r.reconcilePodSecurityLabels(ctx, operatorv1alpha1.PodSecurityRestricted)
+	g.Expect(err).ToNot(HaveOccurred())
+	ns = &corev1.Namespace{}
+	g.Expect(r.Get(ctx, types.NamespacedName{Name: namespace}, ns)).To(Succeed())
+	g.Expect(ns.GetLabels()).To(HaveKeyWithValue(PodSecurityEnforceLabel, "restricted"))
+	g.Expect(ns.GetLabels()).To(HaveKeyWithValue(podSecurityLabelSyncLabel, "false"))
+
+	// test removal
+	err = r.reconcilePodSecurityLabels(ctx, "")
+	g.Expect(err).ToNot(HaveOccurred())
+	ns = &corev1.Namespace{}
+	g.Expect(r.Get(ctx, types.NamespacedName{Name: namespace}, ns)).To(Succeed())
+	g.Expect(ns.GetLabels()).To(Equal(map[string]string{"other": "label"}))
+	g.Expect(ns.GetAnnotations()).ToNot(HaveKey(podSecurityAnnotation))
+
+	// test missing namespace
+	r.Namespace = "missing"
+	err = r.reconcilePodSecurityLabels(ctx, level)
+	g.Expect(err).ToNot(HaveOccurred())
+}
+
+func TestSecurityContextConstraints(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	r := &GatekeeperReconciler{
+		Client:       fake.NewFakeClient(),
+		Namespace:    namespace,
+		PlatformName: util.OpenShift,
+	}
+
+	getSCCs := func(obj *unstructured.Unstructured) []string {
+		rules, _, err := unstructured.NestedSlice(obj.Object, "rules")
+		g.Expect(err).ToNot(HaveOccurred())
+		for _, rule := range rules {
+			r := rule.(map[string]interface{})
+			resources, _, err := unstructured.NestedStringSlice(r, "resources")
+			g.Expect(err).ToNot(HaveOccurred())
+			if resources[0] == "securitycontextconstraints" {
+				resourceNames, _, err := unstructured.NestedStringSlice(r, "resourceNames")
+				g.Expect(err).ToNot(HaveOccurred())
+				return resourceNames
+			}
+		}
+		return nil
+	}
+
+	// test restricted is used by default on clusters without restricted-v2
+	for _, level := range []operatorv1alpha1.PodSecurityLevel{"", operatorv1alpha1.PodSecurityBaseline, operatorv1alpha1.PodSecurityRestricted} {
+		scc, err := r.getSecurityContextConstraints(ctx, level)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(scc).To(Equal(restrictedSecurityContextConstraints))
+	}
+
+	// test restricted-v2 is used when the cluster has it
+	restrictedV2 := &unstructured.Unstructured{}
+	restrictedV2.SetGroupVersionKind(securityContextConstraintsGVK)
+	restrictedV2.SetName(restrictedV2SecurityContextConstraints)
+	r.Client = fake.NewFakeClient(restrictedV2)
+	for _, level := range []operatorv1alpha1.PodSecurityLevel{"", operatorv1alpha1.PodSecurityBaseline, operatorv1alpha1.PodSecurityRestricted} {
+		scc, err := r.getSecurityContextConstraints(ctx, level)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(scc).To(Equal(restrictedV2SecurityContextConstraints))
+	}
+
+	// test privileged is only used when requested
+	scc, err := r.getSecurityContextConstraints(ctx, operatorv1alpha1.PodSecurityPrivileged)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(scc).To(Equal(privilegedSecurityContextConstraints))
+
+	// test the rule is added to the upstream role and then replaced
+	roleObj, err := util.GetManifestObject(RoleFile)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(getSCCs(roleObj)).To(BeNil())
+	g.Expect(setSecurityContextConstraints(roleObj, restrictedSecurityContextConstraints)).To(Succeed())
+	g.Expect(getSCCs(roleObj)).To(Equal([]string{restrictedSecurityContextConstraints}))
+	g.Expect(setSecurityContextConstraints(roleObj, scc)).To(Succeed())
+	g.Expect(getSCCs(roleObj)).To(Equal([]string{privilegedSecurityContextConstraints}))
+	rules, _, err := unstructured.NestedSlice(roleObj.Object, "rules")
+	g.Expect(err).ToNot(HaveOccurred())
+	upstreamRoleObj, err := util.GetManifestObject(RoleFile)
+	g.Expect(err).ToNot(HaveOccurred())
+	upstreamRules, _, err := unstructured.NestedSlice(upstreamRoleObj.Object, "rules")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(rules).To(HaveLen(len(upstreamRules) + 1))
+}
+
+func TestPodSecurityContext(t *testing.T) {
+	g := NewWithT(t)
+	gatekeeper := &operatorv1alpha1.Gatekeeper{}
+
+	getSecurityContext := func(obj *unstructured.Unstructured) map[string]interface{} {
+		containers, _, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+		g.Expect(err).ToNot(HaveOccurred())
+		securityContext, _, err := unstructured.NestedMap(containers[0].(map[string]interface{}), "securityContext")
+		g.Expect(err).ToNot(HaveOccurred())
+		return securityContext
+	}
+
+	for _, asset := range []string{AuditFile, WebhookFile} {
+		// test default
+		obj, err := util.GetManifestObject(asset)
+		g.Expect(err).ToNot(HaveOccurred())
+		gatekeeper.Spec.PodSecurityLevel = nil
+		err = crOverrides(gatekeeper, asset, obj, namespace, false)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(getSecurityContext(obj)).ToNot(HaveKey("seccompProfile"))
+
+		// test restricted
+		level := operatorv1alpha1.PodSecurityRestricted
+		gatekeeper.Spec.PodSecurityLevel = &level
+		obj, err = util.GetManifestObject(asset)
+		g.Expect(err).ToNot(HaveOccurred())
+		err = crOverrides(gatekeeper, asset, obj, namespace, false)
+		g.Expect(err).ToNot(HaveOccurred())
+		securityContext := getSecurityContext(obj)
+		g.Expect(securityContext).To(HaveKeyWithValue("seccompProfile", map[string]interface{}{"type": "RuntimeDefault"}))
+		g.Expect(securityContext).To(HaveKeyWithValue("capabilities", map[string]interface{}{"drop": []interface{}{"ALL"}}))
+		g.Expect(securityContext).To(HaveKey("runAsUser"))
+
+		// test restricted on OpenShift
+		obj, err = util.GetManifestObject(asset)
+		g.Expect(err).ToNot(HaveOccurred())
+		err = crOverrides(gatekeeper, asset, obj, namespace, true)
+		g.Expect(err).ToNot(HaveOccurred())
+		securityContext = getSecurityContext(obj)
+		g.Expect(securityContext).ToNot(HaveKey("runAsUser"))
+		g.Expect(securityContext).ToNot(HaveKey("runAsGroup"))
+
+		// test the user is left to the restricted security context
+		// constraints on OpenShift by default
+		gatekeeper.Spec.PodSecurityLevel = nil
+		obj, err = util.GetManifestObject(asset)
+		g.Expect(err).ToNot(HaveOccurred())
+		err = crOverrides(gatekeeper, asset, obj, namespace, true)
+		g.Expect(err).ToNot(HaveOccurred())
+		securityContext = getSecurityContext(obj)
+		g.Expect(securityContext).ToNot(HaveKey("seccompProfile"))
+		g.Expect(securityContext).ToNot(HaveKey("runAsUser"))
+
+		// test privileged on OpenShift
+		level = operatorv1alpha1.PodSecurityPrivileged
+		gatekeeper.Spec.PodSecurityLevel = &level
+		obj, err = util.GetManifestObject(asset)
+		g.Expect(err).ToNot(HaveOccurred())
+		err = crOverrides(gatekeeper, asset, obj, namespace, true)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(getSecurityContext(obj)).To(HaveKey("runAsUser"))
+	}
+}
+
+func TestPodSecurityPolicyRBACRule(t *testing.T) {
+	g := NewWithT(t)
+	clusterRoleObj, err := util.GetManifestObject(ClusterRoleFile)
+	g.Expect(err).ToNot(HaveOccurred())
+	rules, _, err := unstructured.NestedSlice(clusterRoleObj.Object, "rules")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	err = removeRBACRule(clusterRoleObj, matchPodSecurityPolicyRBACRule)
+	g.Expect(err).ToNot(HaveOccurred())
+	remaining, _, err := unstructured.NestedSlice(clusterRoleObj.Object, "rules")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(remaining).To(HaveLen(len(rules) - 1))
+	for _, rule := range remaining {
+		found, err := matchPodSecurityPolicyRBACRule(rule.(map[string]interface{}))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(found).To(BeFalse())
+	}
+}
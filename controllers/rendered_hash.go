@@ -0,0 +1,161 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// RenderedHashAnnotation is set on every Gatekeeper resource to the hash of
+// the object rendered by the operator.
+const RenderedHashAnnotation = "operator.gatekeeper.sh/rendered-hash"
+
+// setRenderedHash computes the hash of the given rendered object and stores
+// it in the rendered hash annotation of the object.
+func setRenderedHash(obj *unstructured.Unstructured) error {
+	// The hash covers the rendered object without its previous hash.
+	annotations := withoutKey(obj.GetAnnotations(), RenderedHashAnnotation)
+	obj.SetAnnotations(annotations)
+
+	// Maps are encoded with sorted keys, so the same rendered object always
+	// results in the same hash.
+	data, err := json.Marshal(obj.Object)
+	if err != nil {
+		return errors.Wrapf(err, "Unable to encode %s %s", obj.GetKind(), obj.GetName())
+	}
+	sum := sha256.Sum256(data)
+
+	annotations[RenderedHashAnnotation] = hex.EncodeToString(sum[:])
+	obj.SetAnnotations(annotations)
+	return nil
+}
+
+// isUpToDate returns whether the cluster object was last written by the
+// operator from the same rendered object, i.e. its rendered hash matches, and
+// whether the fields rendered by the operator, the fields it owns, still have
+// their rendered value. Fields only set by other field managers or defaulted
+// by the API server are ignored, while changes made outside of the operator
+// to the rendered fields are corrected on the next reconcile.
+func isUpToDate(obj, clusterObj *unstructured.Unstructured) bool {
+	hash, ok := obj.GetAnnotations()[RenderedHashAnnotation]
+	if !ok || clusterObj.GetAnnotations()[RenderedHashAnnotation] != hash {
+		return false
+	}
+	// The status is not applied by the operator.
+	rendered := make(map[string]interface{}, len(obj.Object))
+	for k, v := range obj.Object {
+		if k != "status" {
+			rendered[k] = v
+		}
+	}
+	return containsFields(clusterObj.Object, rendered)
+}
+
+// containsFields returns whether the given cluster value has all the fields
+// of the given rendered value. The elements of a rendered list of objects
+// must each be contained in an element of the cluster list, as other field
+// managers may add elements to it, e.g. injected sidecar containers. Other
+// lists must be equal. Quantities match their canonical form, as the API
+// server stores e.g. a cpu of 1000m as 1.
+func containsFields(clusterValue, renderedValue interface{}) bool {
+	switch rendered := renderedValue.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		cluster, ok := clusterValue.(map[string]interface{})
+		if !ok {
+			return clusterValue == nil && len(rendered) == 0
+		}
+		for k, v := range rendered {
+			if !containsFields(cluster[k], v) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		cluster, ok := clusterValue.([]interface{})
+		if !ok {
+			return clusterValue == nil && len(rendered) == 0
+		}
+		for i, r := range rendered {
+			if _, isMap := r.(map[string]interface{}); !isMap {
+				if len(cluster) != len(rendered) || !containsFields(cluster[i], r) {
+					return false
+				}
+				continue
+			}
+			found := false
+			for _, c := range cluster {
+				if containsFields(c, r) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	default:
+		if clusterNumber, ok := toFloat(clusterValue); ok {
+			renderedNumber, ok := toFloat(renderedValue)
+			return ok && clusterNumber == renderedNumber
+		}
+		if reflect.DeepEqual(clusterValue, renderedValue) {
+			return true
+		}
+		clusterString, ok := clusterValue.(string)
+		if !ok {
+			return false
+		}
+		quantity, ok := toQuantity(renderedValue)
+		return ok && quantity.String() == clusterString
+	}
+}
+
+// toQuantity returns the given string or number as a quantity.
+func toQuantity(value interface{}) (resource.Quantity, bool) {
+	switch v := value.(type) {
+	case string:
+		quantity, err := resource.ParseQuantity(v)
+		return quantity, err == nil
+	case int64:
+		return *resource.NewQuantity(v, resource.DecimalSI), true
+	case float64:
+		quantity, err := resource.ParseQuantity(strconv.FormatFloat(v, 'f', -1, 64))
+		return quantity, err == nil
+	}
+	return resource.Quantity{}, false
+}
+
+// toFloat returns the given number as a float, as the numbers of decoded
+// objects are either int64 or float64.
+func toFloat(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
@@ -0,0 +1,240 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/gatekeeper/gatekeeper-operator/api/v1alpha1"
+	"github.com/gatekeeper/gatekeeper-operator/pkg/util"
+)
+
+func TestSetRenderedHash(t *testing.T) {
+	g := NewWithT(t)
+	auditObj, err := util.GetManifestObject(AuditFile)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	err = setRenderedHash(auditObj)
+	g.Expect(err).ToNot(HaveOccurred())
+	hash := auditObj.GetAnnotations()[RenderedHashAnnotation]
+	g.Expect(hash).ToNot(BeEmpty())
+
+	// test the hash does not depend on the previous hash
+	err = setRenderedHash(auditObj)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(auditObj.GetAnnotations()).To(HaveKeyWithValue(RenderedHashAnnotation, hash))
+
+	// test the hash changes with the rendered object
+	err = unstructured.SetNestedField(auditObj.Object, int64(5), "spec", "replicas")
+	g.Expect(err).ToNot(HaveOccurred())
+	err = setRenderedHash(auditObj)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(auditObj.GetAnnotations()[RenderedHashAnnotation]).ToNot(Equal(hash))
+}
+
+// clusterObjectClient serves a single cluster object for Get requests and
+// applies patches by bumping its resource version, unless they are no-ops.
+type clusterObjectClient struct {
+	applyClient
+	clusterObj *unstructured.Unstructured
+	noop       bool
+}
+
+func (c *clusterObjectClient) Get(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+	if c.clusterObj == nil {
+		return apierrors.NewNotFound(appsv1.Resource("deployments"), key.Name)
+	}
+	c.clusterObj.DeepCopyInto(obj.(*unstructured.Unstructured))
+	return nil
+}
+
+func (c *clusterObjectClient) Patch(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if err := c.applyClient.Patch(ctx, obj, patch, opts...); err != nil {
+		return err
+	}
+	if !c.noop {
+		c.clusterObj = obj.(*unstructured.Unstructured).DeepCopy()
+		c.clusterObj.SetResourceVersion(strconv.Itoa(len(c.forced)))
+	}
+	c.clusterObj.DeepCopyInto(obj.(*unstructured.Unstructured))
+	return nil
+}
+
+func TestCrudResourceSkipsUpToDate(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	g.Expect(operatorv1alpha1.AddToScheme(scheme)).To(Succeed())
+	gatekeeper := &operatorv1alpha1.Gatekeeper{}
+	gatekeeper.SetName(defaultGatekeeperCrName)
+	clusterClient := &clusterObjectClient{}
+	r := &GatekeeperReconciler{
+		Client: clusterClient,
+		Log:    ctrl.Log.WithName("test"),
+		Scheme: scheme,
+	}
+
+	render := func(replicas int64) *unstructured.Unstructured {
+		auditObj, err := util.GetManifestObject(AuditFile)
+		g.Expect(err).ToNot(HaveOccurred())
+		auditObj.SetNamespace(namespace)
+		err = unstructured.SetNestedField(auditObj.Object, replicas, "spec", "replicas")
+		g.Expect(err).ToNot(HaveOccurred())
+		return auditObj
+	}
+
+	// test the resource is created
+	written, err := r.crudResource(render(1), gatekeeper, apply)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(written).To(BeTrue())
+	g.Expect(clusterClient.forced).To(HaveLen(1))
+
+	// test the unchanged resource is skipped
+	written, err = r.crudResource(render(1), gatekeeper, apply)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(written).To(BeFalse())
+	g.Expect(clusterClient.forced).To(HaveLen(1))
+
+	// test the changed resource is applied
+	written, err = r.crudResource(render(2), gatekeeper, apply)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(written).To(BeTrue())
+	g.Expect(clusterClient.forced).To(HaveLen(2))
+
+	// test fields set by other field managers are ignored
+	clusterClient.clusterObj.SetResourceVersion("external")
+	labels := clusterClient.clusterObj.GetLabels()
+	labels["example.com/team"] = "security"
+	clusterClient.clusterObj.SetLabels(labels)
+	written, err = r.crudResource(render(2), gatekeeper, apply)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(written).To(BeFalse())
+	g.Expect(clusterClient.forced).To(HaveLen(2))
+
+	// test the resource changed outside of the operator is applied
+	err = unstructured.SetNestedField(clusterClient.clusterObj.Object, int64(5), "spec", "replicas")
+	g.Expect(err).ToNot(HaveOccurred())
+	written, err = r.crudResource(render(2), gatekeeper, apply)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(written).To(BeTrue())
+	g.Expect(clusterClient.forced).To(HaveLen(3))
+
+	// test the quantities normalized by the API server are up to date
+	setClusterLimit := func(resource, quantity string) {
+		containers, _, err := unstructured.NestedSlice(clusterClient.clusterObj.Object, "spec", "template", "spec", "containers")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(unstructured.SetNestedField(containers[0].(map[string]interface{}), quantity, "resources", "limits", resource)).To(Succeed())
+		g.Expect(unstructured.SetNestedSlice(clusterClient.clusterObj.Object, containers, "spec", "template", "spec", "containers")).To(Succeed())
+	}
+	setClusterLimit("cpu", "1")
+	written, err = r.crudResource(render(2), gatekeeper, apply)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(written).To(BeFalse())
+	g.Expect(clusterClient.forced).To(HaveLen(3))
+
+	// test an apply that leaves the resource unchanged is not a write, e.g.
+	// when the cluster keeps an equivalent form of a rendered quantity
+	setClusterLimit("memory", "536870912")
+	clusterClient.noop = true
+	written, err = r.crudResource(render(2), gatekeeper, apply)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(written).To(BeFalse())
+	g.Expect(clusterClient.forced).To(HaveLen(4))
+}
+
+func TestContainsFields(t *testing.T) {
+	g := NewWithT(t)
+	rendered := map[string]interface{}{
+		"replicas": int64(3),
+		"args":     []interface{}{"--port=8443"},
+		"containers": []interface{}{
+			map[string]interface{}{"name": "manager", "image": "gatekeeper"},
+		},
+		"securityContext": map[string]interface{}{},
+	}
+	cluster := map[string]interface{}{
+		"replicas": int64(3),
+		"args":     []interface{}{"--port=8443"},
+		"containers": []interface{}{
+			map[string]interface{}{"name": "sidecar", "image": "proxy"},
+			map[string]interface{}{"name": "manager", "image": "gatekeeper", "imagePullPolicy": "Always"},
+		},
+		"strategy": map[string]interface{}{"type": "RollingUpdate"},
+	}
+	g.Expect(containsFields(cluster, rendered)).To(BeTrue())
+
+	// test a changed scalar is detected
+	cluster["replicas"] = int64(5)
+	g.Expect(containsFields(cluster, rendered)).To(BeFalse())
+	cluster["replicas"] = float64(3)
+	g.Expect(containsFields(cluster, rendered)).To(BeTrue())
+
+	// test lists of scalars must be equal
+	cluster["args"] = []interface{}{"--port=8443", "--debug"}
+	g.Expect(containsFields(cluster, rendered)).To(BeFalse())
+	cluster["args"] = []interface{}{"--port=8443"}
+
+	// test a changed list element is detected
+	cluster["containers"] = []interface{}{
+		map[string]interface{}{"name": "manager", "image": "other"},
+	}
+	g.Expect(containsFields(cluster, rendered)).To(BeFalse())
+}
+
+func TestIsUpToDateNormalizedQuantities(t *testing.T) {
+	g := NewWithT(t)
+	obj, err := util.GetManifestObject(AuditFile)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(setRenderedHash(obj)).To(Succeed())
+	getContainers := func(obj *unstructured.Unstructured) []interface{} {
+		containers, _, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+		g.Expect(err).ToNot(HaveOccurred())
+		return containers
+	}
+	limits, _, err := unstructured.NestedMap(getContainers(obj)[0].(map[string]interface{}), "resources", "limits")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(limits).To(HaveKeyWithValue("cpu", "1000m"))
+
+	// test the quantities normalized by the API server match
+	clusterObj := obj.DeepCopy()
+	setLimits := func(cpu string) {
+		containers := getContainers(clusterObj)
+		limits := map[string]interface{}{"cpu": cpu, "memory": "512Mi"}
+		g.Expect(unstructured.SetNestedMap(containers[0].(map[string]interface{}), limits, "resources", "limits")).To(Succeed())
+		g.Expect(unstructured.SetNestedSlice(clusterObj.Object, containers, "spec", "template", "spec", "containers")).To(Succeed())
+	}
+	setLimits("1")
+	g.Expect(isUpToDate(obj, clusterObj)).To(BeTrue())
+
+	// test a changed quantity is detected
+	setLimits("2")
+	g.Expect(isUpToDate(obj, clusterObj)).To(BeFalse())
+	setLimits("500m")
+	g.Expect(isUpToDate(obj, clusterObj)).To(BeFalse())
+
+	// test quantities rendered as numbers match
+	g.Expect(containsFields("1", int64(1))).To(BeTrue())
+	g.Expect(containsFields("500m", float64(0.5))).To(BeTrue())
+	g.Expect(containsFields("other", "1000m")).To(BeFalse())
+}
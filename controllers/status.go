@@ -0,0 +1,262 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/gatekeeper/gatekeeper-operator/api/v1alpha1"
+)
+
+const (
+	// How long to wait before checking the status again when Gatekeeper is
+	// not ready yet.
+	notReadyRequeueInterval = 10 * time.Second
+
+	DeploymentAvailableReason          = "DeploymentAvailable"
+	DeploymentNotFoundReason           = "DeploymentNotFound"
+	RolloutInProgressReason            = "RolloutInProgress"
+	ProgressDeadlineExceededReason     = "ProgressDeadlineExceeded"
+	ReplicasUnavailableReason          = "ReplicasUnavailable"
+	CrashLoopBackOffReason             = "CrashLoopBackOff"
+	WebhookConfigurationNotFoundReason = "WebhookConfigurationNotFound"
+	CertManagerNotInstalledReason      = "CertManagerNotInstalled"
+	CertificateSecretInvalidReason     = "CertificateSecretInvalid"
+	PodDisruptionBudgetSkippedReason   = "PodDisruptionBudgetSkipped"
+)
+
+// updateStatus computes the audit and webhook conditions from the state of
+// the deployed Gatekeeper resources and writes them through the status
+// subresource. It returns whether both components are ready.
+func (r *GatekeeperReconciler) updateStatus(ctx context.Context, gatekeeper *operatorv1alpha1.Gatekeeper) (bool, error) {
+	auditCondition, err := r.deploymentStatusCondition(ctx, gatekeeper, AuditFile)
+	if err != nil {
+		return false, err
+	}
+
+	webhookCondition, err := r.deploymentStatusCondition(ctx, gatekeeper, WebhookFile)
+	if err != nil {
+		return false, err
+	}
+	if webhookCondition.Type == operatorv1alpha1.StatusReady {
+		webhookCondition, err = r.webhookConfigurationStatusCondition(ctx, gatekeeper, webhookCondition)
+		if err != nil {
+			return false, err
+		}
+	}
+	switch getCertificatesMode(gatekeeper.Spec.Webhook) {
+	case operatorv1alpha1.CertificatesCertManager:
+		webhookCondition, err = r.certManagerStatusCondition(ctx, gatekeeper, webhookCondition)
+	case operatorv1alpha1.CertificatesSecret:
+		webhookCondition, err = r.certificateSecretStatusCondition(ctx, gatekeeper, webhookCondition)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	now := metav1.Now()
+	gatekeeper.Status.ObservedGeneration = gatekeeper.GetGeneration()
+	gatekeeper.Status.AuditConditions = setStatusCondition(gatekeeper.Status.AuditConditions, auditCondition, now)
+	gatekeeper.Status.WebhookConditions = setStatusCondition(gatekeeper.Status.WebhookConditions, webhookCondition, now)
+
+	if err := r.Status().Update(ctx, gatekeeper); err != nil {
+		return false, errors.Wrapf(err, "Unable to update Gatekeeper status")
+	}
+
+	ready := auditCondition.Type == operatorv1alpha1.StatusReady &&
+		webhookCondition.Type == operatorv1alpha1.StatusReady
+	return ready, nil
+}
+
+// deploymentStatusCondition retrieves the Deployment rendered from the given
+// asset along with its pods and returns the resulting status condition.
+func (r *GatekeeperReconciler) deploymentStatusCondition(ctx context.Context, gatekeeper *operatorv1alpha1.Gatekeeper,
+	asset string) (operatorv1alpha1.StatusCondition, error) {
+	obj, err := r.getVersionedManifestObject(getManifestVersion(gatekeeper, asset), asset)
+	if err != nil {
+		return operatorv1alpha1.StatusCondition{}, err
+	}
+
+	namespacedName := types.NamespacedName{
+		Namespace: r.Namespace,
+		Name:      obj.GetName(),
+	}
+	deployment := &appsv1.Deployment{}
+	if err := r.Get(ctx, namespacedName, deployment); err != nil {
+		if apierrors.IsNotFound(err) {
+			return notReadyCondition(DeploymentNotFoundReason,
+				fmt.Sprintf("Deployment %s not found", namespacedName)), nil
+		}
+		return operatorv1alpha1.StatusCondition{}, errors.Wrapf(err, "Unable to get deployment %s", namespacedName)
+	}
+
+	pods := &corev1.PodList{}
+	if deployment.Spec.Selector != nil {
+		err = r.uncachedReader().List(ctx, pods,
+			client.InNamespace(r.Namespace),
+			client.MatchingLabels(deployment.Spec.Selector.MatchLabels),
+		)
+		if err != nil {
+			return operatorv1alpha1.StatusCondition{}, errors.Wrapf(err, "Unable to list pods for deployment %s", namespacedName)
+		}
+	}
+
+	return deploymentCondition(deployment, pods.Items), nil
+}
+
+// uncachedReader returns the reader used for the objects that the operator
+// does not watch, e.g. the Gatekeeper pods. They are read directly from the
+// API server when possible so that the operator does not need to cache every
+// such object in the cluster.
+func (r *GatekeeperReconciler) uncachedReader() client.Reader {
+	if r.APIReader != nil {
+		return r.APIReader
+	}
+	return r.Client
+}
+
+// webhookConfigurationStatusCondition checks that the enabled webhook
+// configurations exist, returning the given condition if they all do.
+func (r *GatekeeperReconciler) webhookConfigurationStatusCondition(ctx context.Context, gatekeeper *operatorv1alpha1.Gatekeeper,
+	condition operatorv1alpha1.StatusCondition) (operatorv1alpha1.StatusCondition, error) {
+	_, applyAssets := getStaticAssets(gatekeeper)
+	for _, asset := range applyAssets {
+		if asset != ValidatingWebhookConfiguration && asset != MutatingWebhookConfiguration {
+			continue
+		}
+		obj, err := r.getVersionedManifestObject(getManifestVersion(gatekeeper, asset), asset)
+		if err != nil {
+			return operatorv1alpha1.StatusCondition{}, err
+		}
+
+		clusterObj := &unstructured.Unstructured{}
+		clusterObj.SetAPIVersion(obj.GetAPIVersion())
+		clusterObj.SetKind(obj.GetKind())
+		err = r.Get(ctx, types.NamespacedName{Name: obj.GetName()}, clusterObj)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return notReadyCondition(WebhookConfigurationNotFoundReason,
+					fmt.Sprintf("%s %s not found", obj.GetKind(), obj.GetName())), nil
+			}
+			return operatorv1alpha1.StatusCondition{}, errors.Wrapf(err, "Unable to get %s %s", obj.GetKind(), obj.GetName())
+		}
+	}
+	return condition, nil
+}
+
+// certManagerStatusCondition reports the webhook as not ready when the
+// cert-manager CRDs are missing, as the webhook serving certificate cannot be
+// issued without them. It returns the given condition otherwise.
+func (r *GatekeeperReconciler) certManagerStatusCondition(ctx context.Context, gatekeeper *operatorv1alpha1.Gatekeeper,
+	condition operatorv1alpha1.StatusCondition) (operatorv1alpha1.StatusCondition, error) {
+	installed, err := r.isCertManagerInstalled(ctx, gatekeeper)
+	if err != nil {
+		return operatorv1alpha1.StatusCondition{}, err
+	}
+	if !installed {
+		return notReadyCondition(CertManagerNotInstalledReason,
+			"cert-manager CRDs not found, install cert-manager to issue the webhook serving certificate"), nil
+	}
+	return condition, nil
+}
+
+// certificateSecretStatusCondition reports the webhook as not ready when the
+// Secret providing the webhook certificates cannot be used. It returns the
+// given condition otherwise.
+func (r *GatekeeperReconciler) certificateSecretStatusCondition(ctx context.Context, gatekeeper *operatorv1alpha1.Gatekeeper,
+	condition operatorv1alpha1.StatusCondition) (operatorv1alpha1.StatusCondition, error) {
+	_, err := r.getSecretCertificates(ctx, gatekeeper)
+	if isInvalidSecretError(err) {
+		return notReadyCondition(CertificateSecretInvalidReason, err.Error()), nil
+	} else if err != nil {
+		return operatorv1alpha1.StatusCondition{}, err
+	}
+	return condition, nil
+}
+
+// deploymentCondition derives the readiness of a Gatekeeper component from
+// its Deployment and the pods it manages.
+func deploymentCondition(deployment *appsv1.Deployment, pods []corev1.Pod) operatorv1alpha1.StatusCondition {
+	desiredReplicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		desiredReplicas = *deployment.Spec.Replicas
+	}
+
+	for _, c := range deployment.Status.Conditions {
+		if c.Type == appsv1.DeploymentProgressing && c.Status == corev1.ConditionFalse &&
+			c.Reason == ProgressDeadlineExceededReason {
+			return notReadyCondition(ProgressDeadlineExceededReason, c.Message)
+		}
+	}
+
+	for _, pod := range pods {
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.State.Waiting != nil && cs.State.Waiting.Reason == CrashLoopBackOffReason {
+				return notReadyCondition(CrashLoopBackOffReason,
+					fmt.Sprintf("Container %s in pod %s is crash looping", cs.Name, pod.Name))
+			}
+		}
+	}
+
+	if deployment.Status.ObservedGeneration < deployment.GetGeneration() ||
+		deployment.Status.UpdatedReplicas < desiredReplicas {
+		return notReadyCondition(RolloutInProgressReason,
+			fmt.Sprintf("%d of %d replicas updated", deployment.Status.UpdatedReplicas, desiredReplicas))
+	}
+
+	if deployment.Status.AvailableReplicas < desiredReplicas {
+		return notReadyCondition(ReplicasUnavailableReason,
+			fmt.Sprintf("%d of %d replicas available", deployment.Status.AvailableReplicas, desiredReplicas))
+	}
+
+	return operatorv1alpha1.StatusCondition{
+		Type:    operatorv1alpha1.StatusReady,
+		Status:  corev1.ConditionTrue,
+		Reason:  DeploymentAvailableReason,
+		Message: fmt.Sprintf("%d of %d replicas available", deployment.Status.AvailableReplicas, desiredReplicas),
+	}
+}
+
+func notReadyCondition(reason, message string) operatorv1alpha1.StatusCondition {
+	return operatorv1alpha1.StatusCondition{
+		Type:    operatorv1alpha1.StatusNotReady,
+		Status:  corev1.ConditionTrue,
+		Reason:  reason,
+		Message: message,
+	}
+}
+
+// setStatusCondition replaces the current condition with the given one,
+// preserving the last transition time when the condition type is unchanged.
+func setStatusCondition(conditions []operatorv1alpha1.StatusCondition, condition operatorv1alpha1.StatusCondition,
+	now metav1.Time) []operatorv1alpha1.StatusCondition {
+	condition.LastProbeTime = now
+	condition.LastTransitionTime = now
+	if len(conditions) > 0 && conditions[0].Type == condition.Type {
+		condition.LastTransitionTime = conditions[0].LastTransitionTime
+	}
+	return []operatorv1alpha1.StatusCondition{condition}
+}
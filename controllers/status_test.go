@@ -0,0 +1,152 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1alpha1 "github.com/gatekeeper/gatekeeper-operator/api/v1alpha1"
+)
+
+func TestDeploymentCondition(t *testing.T) {
+	g := NewWithT(t)
+	replicas := int32(3)
+	newDeployment := func() *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Generation: 2,
+			},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: &replicas,
+			},
+			Status: appsv1.DeploymentStatus{
+				ObservedGeneration: 2,
+				UpdatedReplicas:    3,
+				AvailableReplicas:  3,
+			},
+		}
+	}
+
+	// test available deployment
+	deployment := newDeployment()
+	condition := deploymentCondition(deployment, nil)
+	g.Expect(condition.Type).To(Equal(operatorv1alpha1.StatusReady))
+	g.Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+	g.Expect(condition.Reason).To(Equal(DeploymentAvailableReason))
+
+	// test deployment spec not yet observed
+	deployment = newDeployment()
+	deployment.Status.ObservedGeneration = 1
+	condition = deploymentCondition(deployment, nil)
+	g.Expect(condition.Type).To(Equal(operatorv1alpha1.StatusNotReady))
+	g.Expect(condition.Reason).To(Equal(RolloutInProgressReason))
+
+	// test rollout in progress
+	deployment = newDeployment()
+	deployment.Status.UpdatedReplicas = 1
+	condition = deploymentCondition(deployment, nil)
+	g.Expect(condition.Type).To(Equal(operatorv1alpha1.StatusNotReady))
+	g.Expect(condition.Reason).To(Equal(RolloutInProgressReason))
+
+	// test unavailable replicas
+	deployment = newDeployment()
+	deployment.Status.AvailableReplicas = 2
+	condition = deploymentCondition(deployment, nil)
+	g.Expect(condition.Type).To(Equal(operatorv1alpha1.StatusNotReady))
+	g.Expect(condition.Reason).To(Equal(ReplicasUnavailableReason))
+	g.Expect(condition.Message).To(Equal("2 of 3 replicas available"))
+
+	// test progress deadline exceeded
+	deployment = newDeployment()
+	deployment.Status.Conditions = []appsv1.DeploymentCondition{
+		{
+			Type:    appsv1.DeploymentProgressing,
+			Status:  corev1.ConditionFalse,
+			Reason:  ProgressDeadlineExceededReason,
+			Message: "ReplicaSet has timed out progressing.",
+		},
+	}
+	condition = deploymentCondition(deployment, nil)
+	g.Expect(condition.Type).To(Equal(operatorv1alpha1.StatusNotReady))
+	g.Expect(condition.Reason).To(Equal(ProgressDeadlineExceededReason))
+
+	// test crash looping pod
+	deployment = newDeployment()
+	pods := []corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "gatekeeper-audit-abc",
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name: managerContainer,
+						State: corev1.ContainerState{
+							Waiting: &corev1.ContainerStateWaiting{
+								Reason: CrashLoopBackOffReason,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	condition = deploymentCondition(deployment, pods)
+	g.Expect(condition.Type).To(Equal(operatorv1alpha1.StatusNotReady))
+	g.Expect(condition.Reason).To(Equal(CrashLoopBackOffReason))
+
+	// test default replicas
+	deployment = newDeployment()
+	deployment.Spec.Replicas = nil
+	deployment.Status.AvailableReplicas = 1
+	deployment.Status.UpdatedReplicas = 1
+	condition = deploymentCondition(deployment, nil)
+	g.Expect(condition.Type).To(Equal(operatorv1alpha1.StatusReady))
+}
+
+func TestSetStatusCondition(t *testing.T) {
+	g := NewWithT(t)
+	earlier := metav1.NewTime(time.Now().Add(-time.Hour))
+	now := metav1.Now()
+
+	// test first condition
+	conditions := setStatusCondition(nil, notReadyCondition(RolloutInProgressReason, ""), earlier)
+	g.Expect(conditions).To(HaveLen(1))
+	g.Expect(conditions[0].Type).To(Equal(operatorv1alpha1.StatusNotReady))
+	g.Expect(conditions[0].LastTransitionTime).To(Equal(earlier))
+	g.Expect(conditions[0].LastProbeTime).To(Equal(earlier))
+
+	// test same condition type preserves transition time
+	conditions = setStatusCondition(conditions, notReadyCondition(ReplicasUnavailableReason, ""), now)
+	g.Expect(conditions).To(HaveLen(1))
+	g.Expect(conditions[0].Reason).To(Equal(ReplicasUnavailableReason))
+	g.Expect(conditions[0].LastTransitionTime).To(Equal(earlier))
+	g.Expect(conditions[0].LastProbeTime).To(Equal(now))
+
+	// test condition type change updates transition time
+	conditions = setStatusCondition(conditions, operatorv1alpha1.StatusCondition{
+		Type:   operatorv1alpha1.StatusReady,
+		Status: corev1.ConditionTrue,
+	}, now)
+	g.Expect(conditions).To(HaveLen(1))
+	g.Expect(conditions[0].Type).To(Equal(operatorv1alpha1.StatusReady))
+	g.Expect(conditions[0].LastTransitionTime).To(Equal(now))
+}
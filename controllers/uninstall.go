@@ -0,0 +1,306 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	operatorv1alpha1 "github.com/gatekeeper/gatekeeper-operator/api/v1alpha1"
+)
+
+const (
+	gatekeeperFinalizer = "operator.gatekeeper.sh/uninstall"
+	// How long to wait before checking whether the resources of the current
+	// uninstall stage have been removed.
+	uninstallRequeueInterval = 5 * time.Second
+)
+
+var (
+	// uninstallStages lists the Gatekeeper assets in the order they are
+	// removed when the Gatekeeper resource is deleted. The webhook
+	// configurations go first so that the API server stops calling the
+	// webhook before its pods are removed, and the RBAC resources are only
+	// removed once no Gatekeeper pod needs them.
+	uninstallStages = [][]string{
+		{
+			ValidatingWebhookConfiguration,
+			MutatingWebhookConfiguration,
+		},
+		{
+			WebhookFile,
+			AuditFile,
+			WebhookPodDisruptionBudgetFile,
+			AuditPodDisruptionBudgetFile,
+		},
+		{
+			ServiceFile,
+			ClusterRoleBindingFile,
+			ClusterRoleFile,
+			RoleBindingFile,
+			RoleFile,
+			PodSecurityPolicyFile,
+			ServiceAccountFile,
+			ServerCertFile,
+		},
+	}
+	crdStaticAssets = []string{
+		ConfigCRDFile,
+		ConstraintTemplateCRDFile,
+		ConstraintTemplatePodStatusCRDFile,
+		ConstraintPodStatusCRDFile,
+		AssignCRDFile,
+		AssignMetadataCRDFile,
+	}
+)
+
+// uninstall removes the Gatekeeper resources in order, or orphans them when
+// Gatekeeper is unmanaged, and then releases the finalizer on the Gatekeeper
+// resource so that it can be deleted.
+func (r *GatekeeperReconciler) uninstall(ctx context.Context, gatekeeper *operatorv1alpha1.Gatekeeper, logger logr.Logger) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(gatekeeper, gatekeeperFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	if getManagementState(gatekeeper.Spec) == operatorv1alpha1.ManagementStateUnmanaged {
+		// The Gatekeeper resources are left in place, without the owner
+		// reference that would have them garbage collected.
+		logger.Info("Gatekeeper is unmanaged, leaving its resources in place")
+		if err := r.orphanAssets(ctx, gatekeeper, getUninstallAssets(gatekeeper, r.isOpenShift())); err != nil {
+			return ctrl.Result{}, err
+		}
+	} else {
+		logger.Info("Uninstalling Gatekeeper", "uninstallPolicy", getUninstallPolicy(gatekeeper))
+		removed, err := r.removeGatekeeperResources(ctx, gatekeeper, logger)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !removed {
+			return ctrl.Result{RequeueAfter: uninstallRequeueInterval}, nil
+		}
+	}
+
+	controllerutil.RemoveFinalizer(gatekeeper, gatekeeperFinalizer)
+	if err := r.Update(ctx, gatekeeper); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "Unable to remove finalizer from Gatekeeper")
+	}
+	logger.Info("Uninstalled Gatekeeper")
+
+	return ctrl.Result{}, nil
+}
+
+// removeGatekeeperResources removes the Gatekeeper resources in order,
+// following the uninstall policy. It returns whether all of them are gone.
+func (r *GatekeeperReconciler) removeGatekeeperResources(ctx context.Context, gatekeeper *operatorv1alpha1.Gatekeeper, logger logr.Logger) (bool, error) {
+	for _, stage := range getUninstallStages(gatekeeper, r.deletesNamespace()) {
+		removed, err := r.deleteAssets(ctx, gatekeeper, stage)
+		if err != nil {
+			return false, err
+		}
+		if !removed {
+			logger.Info("Waiting for Gatekeeper resources to be removed")
+			return false, nil
+		}
+	}
+
+	if getUninstallPolicy(gatekeeper) == operatorv1alpha1.UninstallPolicyRetainCRDs {
+		if err := r.orphanAssets(ctx, gatekeeper, crdStaticAssets); err != nil {
+			return false, err
+		}
+	}
+
+	// Gatekeeper is gone so no namespace needs to be exempt anymore.
+	if err := r.reconcileExemptNamespaceLabels(ctx, nil); err != nil {
+		return false, err
+	}
+
+	if err := r.reconcilePodSecurityLabels(ctx, ""); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func getUninstallPolicy(gatekeeper *operatorv1alpha1.Gatekeeper) operatorv1alpha1.UninstallPolicy {
+	if gatekeeper.Spec.UninstallPolicy == nil {
+		return operatorv1alpha1.UninstallPolicyRetainCRDs
+	}
+	return *gatekeeper.Spec.UninstallPolicy
+}
+
+// getUninstallStages returns the ordered groups of assets to delete for the
+// uninstall policy of the given Gatekeeper resource. The Config and the
+// cert-manager resources are only deleted when the operator renders them, so
+// that a Config managed by the user is left untouched. The CRDs, and with
+// them all user policies, are only deleted with the DeleteAll policy. The
+// namespace, when deleted, goes last.
+func getUninstallStages(gatekeeper *operatorv1alpha1.Gatekeeper, deleteNamespace bool) [][]string {
+	stages := make([][]string, 0, len(uninstallStages)+2)
+	for _, stage := range uninstallStages {
+		stages = append(stages, append([]string{}, stage...))
+	}
+	if configManaged(gatekeeper.Spec) {
+		// The Config goes along with the deployments, while Gatekeeper can
+		// still handle its removal.
+		stages[1] = append(stages[1], ConfigFile)
+	}
+	if usesCertManager(gatekeeper) {
+		last := len(stages) - 1
+		stages[last] = append(stages[last], certManagerStaticAssets...)
+	}
+	if getUninstallPolicy(gatekeeper) == operatorv1alpha1.UninstallPolicyDeleteAll {
+		stages = append(stages, crdStaticAssets)
+	}
+	if deleteNamespace {
+		stages = append(stages, []string{NamespaceFile})
+	}
+	return stages
+}
+
+// deletesNamespace returns whether the uninstall deletes the Gatekeeper
+// namespace, which the operator only deploys on OpenShift. The namespace the
+// operator runs in is left to garbage collection, once the finalizer is
+// released, so that the operator completes the uninstall.
+func (r *GatekeeperReconciler) deletesNamespace() bool {
+	return r.isOpenShift() && r.Namespace != r.OperatorNamespace
+}
+
+// getUninstallAssets returns every asset that the uninstall of the given
+// Gatekeeper resource deletes or orphans.
+func getUninstallAssets(gatekeeper *operatorv1alpha1.Gatekeeper, namespace bool) []string {
+	assets := make([]string, 0)
+	for _, stage := range getUninstallStages(gatekeeper, namespace) {
+		assets = append(assets, stage...)
+	}
+	if getUninstallPolicy(gatekeeper) != operatorv1alpha1.UninstallPolicyDeleteAll {
+		assets = append(assets, crdStaticAssets...)
+	}
+	return assets
+}
+
+// usesCertManager returns whether the webhook certificates of the given
+// Gatekeeper resource are, or were last, issued by cert-manager.
+func usesCertManager(gatekeeper *operatorv1alpha1.Gatekeeper) bool {
+	if getCertificatesMode(gatekeeper.Spec.Webhook) == operatorv1alpha1.CertificatesCertManager {
+		return true
+	}
+	return gatekeeper.Status.Certificates != nil &&
+		gatekeeper.Status.Certificates.Mode == operatorv1alpha1.CertificatesCertManager
+}
+
+// deleteAssets deletes the cluster objects of the given assets and returns
+// whether all of them are gone.
+func (r *GatekeeperReconciler) deleteAssets(ctx context.Context, gatekeeper *operatorv1alpha1.Gatekeeper, assets []string) (bool, error) {
+	removed := true
+	for _, a := range r.getServedAssets(assets) {
+		obj, err := r.getClusterObject(ctx, gatekeeper, a)
+		if err != nil {
+			// A resource whose CRD is not installed is already gone.
+			if meta.IsNoMatchError(errors.Cause(err)) {
+				continue
+			}
+			return false, err
+		}
+		if obj == nil {
+			continue
+		}
+
+		removed = false
+		if !obj.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		err = r.Delete(ctx, obj, client.PropagationPolicy(metav1.DeletePropagationForeground))
+		if err != nil && !apierrors.IsNotFound(err) {
+			return false, errors.Wrapf(err, "Error attempting to delete resource %s", obj.GetName())
+		}
+		r.Log.Info("Deleted Gatekeeper resource", "Gatekeeper resource", types.NamespacedName{
+			Namespace: obj.GetNamespace(),
+			Name:      obj.GetName(),
+		})
+	}
+	return removed, nil
+}
+
+// orphanAssets removes the owner reference to the Gatekeeper resource from
+// the cluster objects of the given assets so that they are not garbage
+// collected along with it.
+func (r *GatekeeperReconciler) orphanAssets(ctx context.Context, gatekeeper *operatorv1alpha1.Gatekeeper, assets []string) error {
+	for _, a := range r.getServedAssets(assets) {
+		obj, err := r.getClusterObject(ctx, gatekeeper, a)
+		if err != nil {
+			// A resource whose CRD is not installed is already gone.
+			if meta.IsNoMatchError(errors.Cause(err)) {
+				continue
+			}
+			return err
+		}
+		if obj == nil {
+			continue
+		}
+
+		ownerRefs := make([]metav1.OwnerReference, 0)
+		for _, ref := range obj.GetOwnerReferences() {
+			if ref.UID != gatekeeper.GetUID() {
+				ownerRefs = append(ownerRefs, ref)
+			}
+		}
+		if len(ownerRefs) == len(obj.GetOwnerReferences()) {
+			continue
+		}
+		obj.SetOwnerReferences(ownerRefs)
+		if err = r.Update(ctx, obj); err != nil {
+			return errors.Wrapf(err, "Unable to remove owner reference from resource %s", obj.GetName())
+		}
+	}
+	return nil
+}
+
+// getClusterObject retrieves the cluster object for the given asset of the
+// given Gatekeeper resource. It returns nil when the object does not exist.
+func (r *GatekeeperReconciler) getClusterObject(ctx context.Context, gatekeeper *operatorv1alpha1.Gatekeeper, asset string) (*unstructured.Unstructured, error) {
+	version := getManifestVersion(gatekeeper, asset)
+	obj, err := r.getVersionedManifestObject(version, asset)
+	if err != nil {
+		return nil, err
+	}
+	if obj.GetNamespace() != "" {
+		obj.SetNamespace(r.Namespace)
+	}
+
+	namespacedName := types.NamespacedName{
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+	}
+	clusterObj := &unstructured.Unstructured{}
+	clusterObj.SetAPIVersion(obj.GetAPIVersion())
+	clusterObj.SetKind(obj.GetKind())
+	if err = r.Get(ctx, namespacedName, clusterObj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "Error attempting to get resource %s", namespacedName)
+	}
+	return clusterObj, nil
+}
@@ -0,0 +1,137 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	operatorv1alpha1 "github.com/gatekeeper/gatekeeper-operator/api/v1alpha1"
+	"github.com/gatekeeper/gatekeeper-operator/pkg/util"
+)
+
+func TestGetUninstallStages(t *testing.T) {
+	g := NewWithT(t)
+	gatekeeper := &operatorv1alpha1.Gatekeeper{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+	}
+
+	// test default retains CRDs
+	g.Expect(getUninstallPolicy(gatekeeper)).To(Equal(operatorv1alpha1.UninstallPolicyRetainCRDs))
+	stages := getUninstallStages(gatekeeper, false)
+	g.Expect(stages).To(Equal(uninstallStages))
+	for _, stage := range stages {
+		for _, crd := range crdStaticAssets {
+			g.Expect(stage).ToNot(ContainElement(crd))
+		}
+	}
+
+	// test webhook configurations are removed before the deployments
+	g.Expect(stages[0]).To(ConsistOf(ValidatingWebhookConfiguration, MutatingWebhookConfiguration))
+	g.Expect(stages[1]).To(ConsistOf(WebhookFile, AuditFile, WebhookPodDisruptionBudgetFile, AuditPodDisruptionBudgetFile))
+
+	// test RetainCRDs
+	policy := operatorv1alpha1.UninstallPolicyRetainCRDs
+	gatekeeper.Spec.UninstallPolicy = &policy
+	g.Expect(getUninstallStages(gatekeeper, false)).To(Equal(uninstallStages))
+
+	// test DeleteAll removes the CRDs last
+	policy = operatorv1alpha1.UninstallPolicyDeleteAll
+	gatekeeper.Spec.UninstallPolicy = &policy
+	stages = getUninstallStages(gatekeeper, false)
+	g.Expect(stages).To(HaveLen(len(uninstallStages) + 1))
+	g.Expect(stages[len(stages)-1]).To(Equal(crdStaticAssets))
+
+	// test the namespace is removed after the RBAC resources and the CRDs
+	stages = getUninstallStages(gatekeeper, true)
+	g.Expect(stages).To(HaveLen(len(uninstallStages) + 2))
+	g.Expect(stages[len(stages)-2]).To(Equal(crdStaticAssets))
+	g.Expect(stages[len(stages)-1]).To(Equal([]string{NamespaceFile}))
+	for _, stage := range stages[:len(stages)-1] {
+		g.Expect(stage).ToNot(ContainElement(NamespaceFile))
+	}
+
+	// test the namespace the operator runs in is not deleted
+	r := &GatekeeperReconciler{PlatformName: util.OpenShift, Namespace: namespace, OperatorNamespace: "operator"}
+	g.Expect(r.deletesNamespace()).To(BeTrue())
+	r.OperatorNamespace = namespace
+	g.Expect(r.deletesNamespace()).To(BeFalse())
+	r.PlatformName = ""
+	r.OperatorNamespace = "operator"
+	g.Expect(r.deletesNamespace()).To(BeFalse())
+}
+
+func TestUninstallStagesCoverStaticAssets(t *testing.T) {
+	g := NewWithT(t)
+	certManager := operatorv1alpha1.CertificatesCertManager
+	mutation := operatorv1alpha1.WebhookEnabled
+	maxUnavailable := intstr.FromInt(1)
+	specs := []operatorv1alpha1.GatekeeperSpec{
+		{},
+		{
+			MutatingWebhook: &mutation,
+			Webhook: &operatorv1alpha1.WebhookConfig{
+				Certificates: &operatorv1alpha1.CertificatesConfig{Mode: &certManager},
+			},
+			Audit: &operatorv1alpha1.AuditConfig{
+				PodDisruptionBudget: &operatorv1alpha1.PodDisruptionBudgetConfig{MaxUnavailable: &maxUnavailable},
+			},
+			Config: &operatorv1alpha1.ConfigSpec{},
+		},
+	}
+
+	for _, spec := range specs {
+		gatekeeper := &operatorv1alpha1.Gatekeeper{Spec: spec}
+		uninstallAssets := make([]string, 0)
+		for _, stage := range getUninstallStages(gatekeeper, true) {
+			uninstallAssets = append(uninstallAssets, stage...)
+		}
+		uninstallAssets = append(uninstallAssets, crdStaticAssets...)
+
+		_, applyAssets := getStaticAssets(gatekeeper)
+		for _, a := range applyAssets {
+			g.Expect(uninstallAssets).To(ContainElement(a))
+		}
+	}
+}
+
+func TestUninstallStagesOperatorRenderedResources(t *testing.T) {
+	g := NewWithT(t)
+	gatekeeper := &operatorv1alpha1.Gatekeeper{}
+
+	// test a Config managed by the user is left untouched
+	for _, stage := range getUninstallStages(gatekeeper, false) {
+		g.Expect(stage).ToNot(ContainElement(ConfigFile))
+		g.Expect(stage).ToNot(ContainElements(certManagerStaticAssets))
+	}
+
+	// test the Config rendered by the operator is removed with the deployments
+	gatekeeper.Spec.Config = &operatorv1alpha1.ConfigSpec{}
+	stages := getUninstallStages(gatekeeper, false)
+	g.Expect(stages[1]).To(ContainElement(ConfigFile))
+	g.Expect(uninstallStages[1]).ToNot(ContainElement(ConfigFile))
+
+	// test the cert-manager resources last used are removed
+	gatekeeper.Status.Certificates = &operatorv1alpha1.CertificatesStatus{
+		Mode: operatorv1alpha1.CertificatesCertManager,
+	}
+	stages = getUninstallStages(gatekeeper, false)
+	g.Expect(stages[len(stages)-1]).To(ContainElements(certManagerStaticAssets))
+}
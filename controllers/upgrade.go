@@ -0,0 +1,458 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/gatekeeper/gatekeeper-operator/api/v1alpha1"
+)
+
+const (
+	defaultHealthCheckTimeout = operatorv1alpha1.DefaultHealthCheckTimeout
+	defaultMaxRestarts        = operatorv1alpha1.DefaultMaxRestarts
+
+	UpgradeSucceededReason  = "UpgradeSucceeded"
+	UpgradeRolledBackReason = "UpgradeRolledBack"
+	UpgradeCanceledReason   = "UpgradeCanceled"
+	UpgradeRevertedReason   = "UpgradeReverted"
+	SpecHeldBackReason      = "SpecHeldBack"
+)
+
+func getGatekeeperRelease(spec operatorv1alpha1.GatekeeperSpec) operatorv1alpha1.GatekeeperRelease {
+	release := operatorv1alpha1.GatekeeperRelease{
+		Version: getGatekeeperVersion(spec),
+	}
+	if spec.Image != nil && spec.Image.Image != nil {
+		release.Image = *spec.Image.Image
+	}
+	if spec.Audit != nil && spec.Audit.Image != nil {
+		release.AuditImage = *spec.Audit.Image
+	}
+	if spec.Webhook != nil && spec.Webhook.Image != nil {
+		release.WebhookImage = *spec.Webhook.Image
+	}
+	return release
+}
+
+// sameRelease returns whether the given releases deploy the same manifests
+// and images, regardless of the spec recorded with them.
+func sameRelease(a, b operatorv1alpha1.GatekeeperRelease) bool {
+	a.Spec, b.Spec = nil, nil
+	return a == b
+}
+
+// getLastKnownGood returns the release of the given spec along with the spec
+// itself, so that the whole spec can be rendered again when an upgrade from
+// the release is rolled back.
+func getLastKnownGood(spec operatorv1alpha1.GatekeeperSpec) (*operatorv1alpha1.GatekeeperRelease, error) {
+	raw, err := json.Marshal(spec)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Unable to encode the last known-good Gatekeeper spec")
+	}
+	release := getGatekeeperRelease(spec)
+	release.Spec = &runtime.RawExtension{Raw: raw}
+	return &release, nil
+}
+
+// getLastKnownGoodSpec decodes the spec recorded with the last known-good
+// release. A spec that is missing, e.g. as it was recorded by an earlier
+// version of the operator, or that cannot be decoded is ignored.
+func getLastKnownGoodSpec(release *operatorv1alpha1.GatekeeperRelease) (*operatorv1alpha1.GatekeeperSpec, bool) {
+	if release.Spec == nil {
+		return nil, false
+	}
+	spec := &operatorv1alpha1.GatekeeperSpec{}
+	if err := json.Unmarshal(release.Spec.Raw, spec); err != nil {
+		return nil, false
+	}
+	return spec, true
+}
+
+func getHealthCheckTimeout(spec operatorv1alpha1.GatekeeperSpec) time.Duration {
+	if spec.Upgrade == nil || spec.Upgrade.HealthCheckTimeout == nil {
+		return defaultHealthCheckTimeout
+	}
+	return spec.Upgrade.HealthCheckTimeout.Duration
+}
+
+func getMaxRestarts(spec operatorv1alpha1.GatekeeperSpec) int32 {
+	if spec.Upgrade == nil || spec.Upgrade.MaxRestarts == nil {
+		return defaultMaxRestarts
+	}
+	return *spec.Upgrade.MaxRestarts
+}
+
+func isUpgrading(upgrade *operatorv1alpha1.UpgradeStatus) bool {
+	return upgrade != nil &&
+		(upgrade.Phase == operatorv1alpha1.UpgradePhaseAudit || upgrade.Phase == operatorv1alpha1.UpgradePhaseWebhook)
+}
+
+func isRolledBack(upgrade *operatorv1alpha1.UpgradeStatus) bool {
+	return upgrade != nil && upgrade.Phase == operatorv1alpha1.UpgradePhaseRolledBack
+}
+
+// startUpgrade starts an upgrade when the release of the Gatekeeper resource
+// differs from the last known-good release. An upgrade in progress is
+// canceled, and a rolled back upgrade is dismissed, when the last known-good
+// release is requested again.
+func (r *GatekeeperReconciler) startUpgrade(gatekeeper *operatorv1alpha1.Gatekeeper) {
+	release := getGatekeeperRelease(gatekeeper.Spec)
+	lastKnownGood := gatekeeper.Status.LastKnownGood
+	upgrade := gatekeeper.Status.Upgrade
+
+	if lastKnownGood == nil || sameRelease(*lastKnownGood, release) {
+		switch {
+		case isUpgrading(upgrade):
+			gatekeeper.Status.Upgrade = nil
+			r.recordUpgradeStep(gatekeeper, true, corev1.EventTypeNormal, UpgradeCanceledReason,
+				fmt.Sprintf("Upgrade to %s canceled, keeping %s", releaseString(upgrade.Release), releaseString(release)))
+		case isRolledBack(upgrade):
+			gatekeeper.Status.Upgrade = nil
+			r.recordUpgradeStep(gatekeeper, true, corev1.EventTypeNormal, UpgradeRevertedReason,
+				fmt.Sprintf("Reverted to %s after the rolled back upgrade to %s, applying the spec", releaseString(release),
+					releaseString(upgrade.Release)))
+		}
+		return
+	}
+	if upgrade != nil && sameRelease(upgrade.Release, release) {
+		if isRolledBack(upgrade) {
+			r.reportHeldBackSpec(gatekeeper)
+		}
+		return
+	}
+
+	gatekeeper.Status.Upgrade = &operatorv1alpha1.UpgradeStatus{
+		Release:   release,
+		Phase:     operatorv1alpha1.UpgradePhaseAudit,
+		StartTime: metav1.Now(),
+	}
+	r.recordUpgradeStep(gatekeeper, false, corev1.EventTypeNormal, string(operatorv1alpha1.UpgradePhaseAudit),
+		fmt.Sprintf("Upgrading audit from %s to %s", releaseString(*lastKnownGood), releaseString(release)))
+}
+
+// progressUpgrade checks the health of the component being upgraded and moves
+// the upgrade to its next phase, or rolls it back when the component fails
+// its health checks. The assets written during this reconcile are not checked
+// until their update is observed. It returns whether to check the upgrade
+// again.
+func (r *GatekeeperReconciler) progressUpgrade(ctx context.Context, gatekeeper *operatorv1alpha1.Gatekeeper, written map[string]bool) (bool, error) {
+	upgrade := gatekeeper.Status.Upgrade
+	if !isUpgrading(upgrade) {
+		if gatekeeper.Status.LastKnownGood == nil || !isRolledBack(upgrade) {
+			return false, r.recordLastKnownGood(ctx, gatekeeper, written)
+		}
+		return false, nil
+	}
+
+	asset := AuditFile
+	if upgrade.Phase == operatorv1alpha1.UpgradePhaseWebhook {
+		asset = WebhookFile
+	}
+	if written[asset] {
+		return true, nil
+	}
+
+	condition, err := r.deploymentStatusCondition(ctx, gatekeeper, asset)
+	if err != nil {
+		return false, err
+	}
+	restarts, err := r.getPodRestarts(ctx, gatekeeper, asset, upgrade.StartTime)
+	if err != nil {
+		return false, err
+	}
+
+	timeout := getHealthCheckTimeout(gatekeeper.Spec)
+	switch {
+	case restarts > getMaxRestarts(gatekeeper.Spec):
+		r.rollbackUpgrade(gatekeeper, fmt.Sprintf("%d container restarts in pods of %s", restarts, asset))
+	case condition.Reason == CrashLoopBackOffReason || condition.Reason == ProgressDeadlineExceededReason:
+		r.rollbackUpgrade(gatekeeper, condition.Message)
+	case condition.Type == operatorv1alpha1.StatusReady && upgrade.Phase == operatorv1alpha1.UpgradePhaseAudit:
+		upgrade.Phase = operatorv1alpha1.UpgradePhaseWebhook
+		upgrade.StartTime = metav1.Now()
+		r.recordUpgradeStep(gatekeeper, false, corev1.EventTypeNormal, string(operatorv1alpha1.UpgradePhaseWebhook),
+			fmt.Sprintf("Audit upgraded, upgrading webhook to %s", releaseString(upgrade.Release)))
+	case condition.Type == operatorv1alpha1.StatusReady:
+		lastKnownGood, err := getLastKnownGood(gatekeeper.Spec)
+		if err != nil {
+			return false, err
+		}
+		upgrade.Phase = operatorv1alpha1.UpgradePhaseSucceeded
+		upgrade.StartTime = metav1.Now()
+		gatekeeper.Status.LastKnownGood = lastKnownGood
+		r.recordUpgradeStep(gatekeeper, true, corev1.EventTypeNormal, UpgradeSucceededReason,
+			fmt.Sprintf("Upgraded to %s", releaseString(upgrade.Release)))
+	case time.Since(upgrade.StartTime.Time) > timeout:
+		r.rollbackUpgrade(gatekeeper, fmt.Sprintf("%s not ready after %s: %s", asset, timeout, condition.Message))
+	}
+	return true, nil
+}
+
+// recordLastKnownGood records the deployed release and spec as the last
+// known-good release once both the audit and the webhook are ready, so that
+// upgrades from it can be rolled back. It keeps the recorded spec up to date
+// with the changes made outside of an upgrade.
+func (r *GatekeeperReconciler) recordLastKnownGood(ctx context.Context, gatekeeper *operatorv1alpha1.Gatekeeper, written map[string]bool) error {
+	for _, asset := range []string{AuditFile, WebhookFile} {
+		if written[asset] {
+			return nil
+		}
+		condition, err := r.deploymentStatusCondition(ctx, gatekeeper, asset)
+		if err != nil {
+			return err
+		}
+		if condition.Type != operatorv1alpha1.StatusReady {
+			return nil
+		}
+	}
+	lastKnownGood, err := getLastKnownGood(gatekeeper.Spec)
+	if err != nil {
+		return err
+	}
+	gatekeeper.Status.LastKnownGood = lastKnownGood
+	return nil
+}
+
+// rollbackUpgrade marks the upgrade as rolled back so that the last
+// known-good release is rendered again.
+func (r *GatekeeperReconciler) rollbackUpgrade(gatekeeper *operatorv1alpha1.Gatekeeper, reason string) {
+	upgrade := gatekeeper.Status.Upgrade
+	upgrade.Phase = operatorv1alpha1.UpgradePhaseRolledBack
+	upgrade.StartTime = metav1.Now()
+	r.recordUpgradeStep(gatekeeper, false, corev1.EventTypeWarning, UpgradeRolledBackReason,
+		fmt.Sprintf("Upgrade to %s rolled back to %s: %s", releaseString(upgrade.Release),
+			releaseString(*gatekeeper.Status.LastKnownGood), reason))
+}
+
+// reportHeldBackSpec reports the spec changes made since the last known-good
+// release, which are not rendered while the upgrade is rolled back. They are
+// reported once, as the upgrade condition and as a warning event.
+func (r *GatekeeperReconciler) reportHeldBackSpec(gatekeeper *operatorv1alpha1.Gatekeeper) {
+	spec, ok := getLastKnownGoodSpec(gatekeeper.Status.LastKnownGood)
+	if !ok || equality.Semantic.DeepEqual(withoutRelease(*spec), withoutRelease(gatekeeper.Spec)) {
+		return
+	}
+	conditions := gatekeeper.Status.UpgradeConditions
+	if len(conditions) > 0 && conditions[0].Reason == SpecHeldBackReason {
+		return
+	}
+	r.recordUpgradeStep(gatekeeper, false, corev1.EventTypeWarning, SpecHeldBackReason,
+		fmt.Sprintf("Spec changes are held back while the upgrade to %s is rolled back, request %s or another release to apply them",
+			releaseString(gatekeeper.Status.Upgrade.Release), releaseString(*gatekeeper.Status.LastKnownGood)))
+}
+
+// withoutRelease returns a copy of the given spec without its release and
+// upgrade configuration, which are not rendered from the last known-good
+// spec.
+func withoutRelease(spec operatorv1alpha1.GatekeeperSpec) operatorv1alpha1.GatekeeperSpec {
+	spec = *spec.DeepCopy()
+	spec.Version = ""
+	spec.Upgrade = nil
+	if spec.Image != nil {
+		spec.Image.Image = nil
+		if equality.Semantic.DeepEqual(*spec.Image, operatorv1alpha1.ImageConfig{}) {
+			spec.Image = nil
+		}
+	}
+	if spec.Audit != nil {
+		spec.Audit.Image = nil
+		if equality.Semantic.DeepEqual(*spec.Audit, operatorv1alpha1.AuditConfig{}) {
+			spec.Audit = nil
+		}
+	}
+	if spec.Webhook != nil {
+		spec.Webhook.Image = nil
+		if equality.Semantic.DeepEqual(*spec.Webhook, operatorv1alpha1.WebhookConfig{}) {
+			spec.Webhook = nil
+		}
+	}
+	return spec
+}
+
+// recordUpgradeStep records an upgrade step as the upgrade condition and as
+// an event on the Gatekeeper resource.
+func (r *GatekeeperReconciler) recordUpgradeStep(gatekeeper *operatorv1alpha1.Gatekeeper, ready bool, eventType, reason, message string) {
+	condition := notReadyCondition(reason, message)
+	if ready {
+		condition.Type = operatorv1alpha1.StatusReady
+	}
+	gatekeeper.Status.UpgradeConditions = setStatusCondition(gatekeeper.Status.UpgradeConditions, condition, metav1.Now())
+
+	r.Log.Info("Gatekeeper upgrade", "reason", reason, "message", message)
+	if r.Recorder != nil {
+		r.Recorder.Event(gatekeeper, eventType, reason, message)
+	}
+}
+
+// getPodRestarts returns the number of container restarts in the pods of the
+// Deployment rendered from the given asset that were created since the given
+// time.
+func (r *GatekeeperReconciler) getPodRestarts(ctx context.Context, gatekeeper *operatorv1alpha1.Gatekeeper, asset string,
+	since metav1.Time) (int32, error) {
+	pods, err := r.listDeploymentPods(ctx, gatekeeper, asset)
+	if err != nil {
+		return 0, err
+	}
+
+	restarts := int32(0)
+	for _, pod := range pods {
+		if pod.CreationTimestamp.Before(&since) {
+			continue
+		}
+		for _, cs := range pod.Status.ContainerStatuses {
+			restarts += cs.RestartCount
+		}
+	}
+	return restarts, nil
+}
+
+// listDeploymentPods returns the pods of the Deployment rendered from the
+// given asset.
+func (r *GatekeeperReconciler) listDeploymentPods(ctx context.Context, gatekeeper *operatorv1alpha1.Gatekeeper,
+	asset string) ([]corev1.Pod, error) {
+	deployment, err := r.getDeployment(ctx, gatekeeper, asset)
+	if err != nil || deployment == nil || deployment.Spec.Selector == nil {
+		return nil, err
+	}
+	return r.listPods(ctx, deployment, deployment.Spec.Selector.MatchLabels)
+}
+
+// getDeployment returns the cluster Deployment rendered from the given asset,
+// or nil when it does not exist.
+func (r *GatekeeperReconciler) getDeployment(ctx context.Context, gatekeeper *operatorv1alpha1.Gatekeeper,
+	asset string) (*appsv1.Deployment, error) {
+	obj, err := r.getVersionedManifestObject(getManifestVersion(gatekeeper, asset), asset)
+	if err != nil {
+		return nil, err
+	}
+	namespacedName := types.NamespacedName{
+		Namespace: r.Namespace,
+		Name:      obj.GetName(),
+	}
+	deployment := &appsv1.Deployment{}
+	if err := r.Get(ctx, namespacedName, deployment); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "Unable to get deployment %s", namespacedName)
+	}
+	return deployment, nil
+}
+
+// listPods returns the pods of the given Deployment matching the given labels.
+func (r *GatekeeperReconciler) listPods(ctx context.Context, deployment *appsv1.Deployment,
+	labels map[string]string) ([]corev1.Pod, error) {
+	pods := &corev1.PodList{}
+	err := r.uncachedReader().List(ctx, pods,
+		client.InNamespace(deployment.Namespace),
+		client.MatchingLabels(labels),
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Unable to list pods for deployment %s", deployment.Name)
+	}
+	return pods.Items, nil
+}
+
+// getRenderedGatekeeper returns the Gatekeeper resource to render the given
+// asset from. During an upgrade, every asset but the audit ones keeps the
+// last known-good spec until the audit is upgraded, and every asset is
+// rendered from the last known-good spec once the upgrade is rolled back,
+// holding back any later spec change until another release is requested. A
+// last known-good release recorded without its spec only restores the
+// version and images.
+func getRenderedGatekeeper(gatekeeper *operatorv1alpha1.Gatekeeper, asset string) *operatorv1alpha1.Gatekeeper {
+	upgrade := gatekeeper.Status.Upgrade
+	lastKnownGood := gatekeeper.Status.LastKnownGood
+	if upgrade == nil || lastKnownGood == nil {
+		return gatekeeper
+	}
+	if !isRolledBack(upgrade) &&
+		(upgrade.Phase != operatorv1alpha1.UpgradePhaseAudit || isAuditAsset(asset)) {
+		return gatekeeper
+	}
+
+	rendered := gatekeeper.DeepCopy()
+	if spec, ok := getLastKnownGoodSpec(lastKnownGood); ok {
+		rendered.Spec = *spec
+		return rendered
+	}
+	rendered.Spec.Version = lastKnownGood.Version
+	if lastKnownGood.Image == "" {
+		if rendered.Spec.Image != nil {
+			rendered.Spec.Image.Image = nil
+		}
+	} else {
+		if rendered.Spec.Image == nil {
+			rendered.Spec.Image = &operatorv1alpha1.ImageConfig{}
+		}
+		rendered.Spec.Image.Image = stringOrNil(lastKnownGood.Image)
+	}
+	if lastKnownGood.AuditImage != "" || rendered.Spec.Audit != nil {
+		if rendered.Spec.Audit == nil {
+			rendered.Spec.Audit = &operatorv1alpha1.AuditConfig{}
+		}
+		rendered.Spec.Audit.Image = stringOrNil(lastKnownGood.AuditImage)
+	}
+	if lastKnownGood.WebhookImage != "" || rendered.Spec.Webhook != nil {
+		if rendered.Spec.Webhook == nil {
+			rendered.Spec.Webhook = &operatorv1alpha1.WebhookConfig{}
+		}
+		rendered.Spec.Webhook.Image = stringOrNil(lastKnownGood.WebhookImage)
+	}
+	return rendered
+}
+
+func isAuditAsset(asset string) bool {
+	return asset == AuditFile || asset == AuditPodDisruptionBudgetFile
+}
+
+func stringOrNil(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func releaseString(release operatorv1alpha1.GatekeeperRelease) string {
+	var images []string
+	if release.Image != "" {
+		images = append(images, release.Image)
+	}
+	if release.AuditImage != "" {
+		images = append(images, "audit "+release.AuditImage)
+	}
+	if release.WebhookImage != "" {
+		images = append(images, "webhook "+release.WebhookImage)
+	}
+	if len(images) == 0 {
+		return release.Version
+	}
+	return fmt.Sprintf("%s (%s)", release.Version, strings.Join(images, ", "))
+}
@@ -0,0 +1,357 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	admregv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1alpha1 "github.com/gatekeeper/gatekeeper-operator/api/v1alpha1"
+	"github.com/gatekeeper/gatekeeper-operator/pkg/util"
+)
+
+func TestGetRenderedGatekeeper(t *testing.T) {
+	g := NewWithT(t)
+	newImage := "gatekeeper:new"
+	gatekeeper := &operatorv1alpha1.Gatekeeper{}
+	gatekeeper.Spec.Image = &operatorv1alpha1.ImageConfig{Image: &newImage}
+	gatekeeper.Status.LastKnownGood = &operatorv1alpha1.GatekeeperRelease{Version: util.DefaultGatekeeperVersion}
+	gatekeeper.Status.Upgrade = &operatorv1alpha1.UpgradeStatus{
+		Release: getGatekeeperRelease(gatekeeper.Spec),
+		Phase:   operatorv1alpha1.UpgradePhaseAudit,
+	}
+
+	// test audit is upgraded first
+	g.Expect(getRenderedGatekeeper(gatekeeper, AuditFile)).To(BeIdenticalTo(gatekeeper))
+	g.Expect(getRenderedGatekeeper(gatekeeper, AuditPodDisruptionBudgetFile)).To(BeIdenticalTo(gatekeeper))
+	for _, asset := range []string{WebhookFile, ClusterRoleFile, ConstraintTemplateCRDFile} {
+		rendered := getRenderedGatekeeper(gatekeeper, asset)
+		g.Expect(rendered.Spec.Image.Image).To(BeNil())
+	}
+	g.Expect(gatekeeper.Spec.Image.Image).To(Equal(&newImage))
+
+	// test webhook is upgraded next
+	gatekeeper.Status.Upgrade.Phase = operatorv1alpha1.UpgradePhaseWebhook
+	g.Expect(getRenderedGatekeeper(gatekeeper, WebhookFile)).To(BeIdenticalTo(gatekeeper))
+
+	// test every asset is rolled back
+	auditImage := "gatekeeper:audit"
+	gatekeeper.Spec.Audit = &operatorv1alpha1.AuditConfig{Image: &auditImage}
+	gatekeeper.Status.Upgrade.Phase = operatorv1alpha1.UpgradePhaseRolledBack
+	for _, asset := range []string{AuditFile, WebhookFile, ClusterRoleFile} {
+		rendered := getRenderedGatekeeper(gatekeeper, asset)
+		g.Expect(getGatekeeperRelease(rendered.Spec)).To(Equal(*gatekeeper.Status.LastKnownGood))
+	}
+
+	// test the whole last known-good spec is restored when recorded
+	replicas := int32(2)
+	lastKnownGoodSpec := operatorv1alpha1.GatekeeperSpec{
+		Audit: &operatorv1alpha1.AuditConfig{Replicas: &replicas},
+	}
+	lastKnownGood, err := getLastKnownGood(lastKnownGoodSpec)
+	g.Expect(err).ToNot(HaveOccurred())
+	gatekeeper.Status.LastKnownGood = lastKnownGood
+	for _, asset := range []string{AuditFile, WebhookFile, ClusterRoleFile} {
+		rendered := getRenderedGatekeeper(gatekeeper, asset)
+		g.Expect(rendered.Spec).To(Equal(lastKnownGoodSpec))
+	}
+}
+
+func TestRollbackRestoresLastKnownGoodSpec(t *testing.T) {
+	g := NewWithT(t)
+	oldVersion := "v3.3.0"
+	ignore, fail := admregv1.Ignore, admregv1.Fail
+	gatekeeper := &operatorv1alpha1.Gatekeeper{}
+	gatekeeper.Spec.Version = oldVersion
+	gatekeeper.Spec.Webhook = &operatorv1alpha1.WebhookConfig{FailurePolicy: &ignore}
+	lastKnownGood, err := getLastKnownGood(gatekeeper.Spec)
+	g.Expect(err).ToNot(HaveOccurred())
+	gatekeeper.Status.LastKnownGood = lastKnownGood
+
+	gatekeeper.Spec.Version = util.DefaultGatekeeperVersion
+	gatekeeper.Spec.Webhook.FailurePolicy = &fail
+	gatekeeper.Status.Upgrade = &operatorv1alpha1.UpgradeStatus{
+		Release: getGatekeeperRelease(gatekeeper.Spec),
+		Phase:   operatorv1alpha1.UpgradePhaseAudit,
+	}
+
+	// test the CRDs and RBAC resources are held back while the audit is
+	// upgraded
+	g.Expect(getManifestVersion(gatekeeper, AuditFile)).To(Equal(util.DefaultGatekeeperVersion))
+	for _, asset := range []string{AssignCRDFile, ClusterRoleFile, ValidatingWebhookConfiguration} {
+		g.Expect(getManifestVersion(gatekeeper, asset)).To(Equal(oldVersion))
+	}
+
+	// test the CRD changes and the webhook configuration are rolled back
+	gatekeeper.Status.Upgrade.Phase = operatorv1alpha1.UpgradePhaseWebhook
+	g.Expect(getManifestVersion(gatekeeper, AssignCRDFile)).To(Equal(util.DefaultGatekeeperVersion))
+	gatekeeper.Status.Upgrade.Phase = operatorv1alpha1.UpgradePhaseRolledBack
+	for _, asset := range []string{AuditFile, AssignCRDFile, ClusterRoleFile} {
+		g.Expect(getManifestVersion(gatekeeper, asset)).To(Equal(oldVersion))
+	}
+	obj, err := util.GetVersionedManifestObject(getManifestVersion(gatekeeper, AssignCRDFile), AssignCRDFile)
+	g.Expect(err).ToNot(HaveOccurred())
+	expected, err := util.GetVersionedManifestObject(oldVersion, AssignCRDFile)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(obj).To(Equal(expected))
+
+	obj, err = util.GetManifestObject(ValidatingWebhookConfiguration)
+	g.Expect(err).ToNot(HaveOccurred())
+	rendered := getRenderedGatekeeper(gatekeeper, ValidatingWebhookConfiguration)
+	g.Expect(crOverrides(rendered, ValidatingWebhookConfiguration, obj, namespace, false)).To(Succeed())
+	assertFailurePolicy(g, obj, ValidationGatekeeperWebhook, &ignore)
+}
+
+func TestUpgrade(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	oldImage, newImage := "gatekeeper:old", "gatekeeper:new"
+	lastKnownGood := operatorv1alpha1.GatekeeperRelease{Version: util.DefaultGatekeeperVersion, Image: oldImage}
+	gatekeeper := &operatorv1alpha1.Gatekeeper{}
+	gatekeeper.Spec.Image = &operatorv1alpha1.ImageConfig{Image: &newImage}
+	gatekeeper.Status.LastKnownGood = &lastKnownGood
+
+	replicas := int32(1)
+	newDeployment := func(name string, available int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: &replicas,
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}},
+			},
+			Status: appsv1.DeploymentStatus{
+				UpdatedReplicas:   1,
+				AvailableReplicas: available,
+			},
+		}
+	}
+	restartingPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "gatekeeper-controller-manager-1",
+			Namespace:         namespace,
+			Labels:            map[string]string{"app": "gatekeeper-controller-manager"},
+			CreationTimestamp: metav1.NewTime(time.Now().Add(time.Minute)),
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{Name: managerContainer, RestartCount: 4}},
+		},
+	}
+	recorder := record.NewFakeRecorder(10)
+	r := &GatekeeperReconciler{
+		Client: fake.NewFakeClient(
+			newDeployment("gatekeeper-audit", 1),
+			newDeployment("gatekeeper-controller-manager", 1),
+			restartingPod,
+		),
+		Log:       ctrl.Log.WithName("test"),
+		Namespace: namespace,
+		Recorder:  recorder,
+	}
+
+	// test upgrade is started
+	r.startUpgrade(gatekeeper)
+	g.Expect(gatekeeper.Status.Upgrade).ToNot(BeNil())
+	g.Expect(gatekeeper.Status.Upgrade.Phase).To(Equal(operatorv1alpha1.UpgradePhaseAudit))
+	g.Expect(gatekeeper.Status.UpgradeConditions[0].Reason).To(Equal(string(operatorv1alpha1.UpgradePhaseAudit)))
+	g.Expect(recorder.Events).To(Receive(ContainSubstring(string(operatorv1alpha1.UpgradePhaseAudit))))
+
+	// test health is not checked until the update is observed
+	upgrading, err := r.progressUpgrade(ctx, gatekeeper, map[string]bool{AuditFile: true})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(upgrading).To(BeTrue())
+	g.Expect(gatekeeper.Status.Upgrade.Phase).To(Equal(operatorv1alpha1.UpgradePhaseAudit))
+
+	// test webhook is upgraded once audit is ready
+	upgrading, err = r.progressUpgrade(ctx, gatekeeper, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(upgrading).To(BeTrue())
+	g.Expect(gatekeeper.Status.Upgrade.Phase).To(Equal(operatorv1alpha1.UpgradePhaseWebhook))
+	g.Expect(recorder.Events).To(Receive(ContainSubstring(string(operatorv1alpha1.UpgradePhaseWebhook))))
+
+	// test upgrade is rolled back when webhook containers restart
+	upgrading, err = r.progressUpgrade(ctx, gatekeeper, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(upgrading).To(BeTrue())
+	g.Expect(gatekeeper.Status.Upgrade.Phase).To(Equal(operatorv1alpha1.UpgradePhaseRolledBack))
+	g.Expect(gatekeeper.Status.LastKnownGood).To(Equal(&lastKnownGood))
+	g.Expect(gatekeeper.Status.UpgradeConditions[0].Type).To(Equal(operatorv1alpha1.StatusNotReady))
+	g.Expect(gatekeeper.Status.UpgradeConditions[0].Reason).To(Equal(UpgradeRolledBackReason))
+	g.Expect(recorder.Events).To(Receive(HavePrefix(corev1.EventTypeWarning + " " + UpgradeRolledBackReason)))
+
+	// test rolled back upgrade is not retried
+	r.startUpgrade(gatekeeper)
+	g.Expect(gatekeeper.Status.Upgrade.Phase).To(Equal(operatorv1alpha1.UpgradePhaseRolledBack))
+	upgrading, err = r.progressUpgrade(ctx, gatekeeper, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(upgrading).To(BeFalse())
+
+	// test upgrade succeeds when restarts are tolerated
+	newerImage := "gatekeeper:newer"
+	maxRestarts := int32(5)
+	gatekeeper.Spec.Image.Image = &newerImage
+	gatekeeper.Spec.Upgrade = &operatorv1alpha1.UpgradeConfig{MaxRestarts: &maxRestarts}
+	r.startUpgrade(gatekeeper)
+	g.Expect(gatekeeper.Status.Upgrade.Phase).To(Equal(operatorv1alpha1.UpgradePhaseAudit))
+	for i := 0; i < 2; i++ {
+		_, err = r.progressUpgrade(ctx, gatekeeper, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+	}
+	g.Expect(gatekeeper.Status.Upgrade.Phase).To(Equal(operatorv1alpha1.UpgradePhaseSucceeded))
+	g.Expect(gatekeeper.Status.LastKnownGood.Image).To(Equal(newerImage))
+	g.Expect(gatekeeper.Status.UpgradeConditions[0].Type).To(Equal(operatorv1alpha1.StatusReady))
+	g.Expect(gatekeeper.Status.UpgradeConditions[0].Reason).To(Equal(UpgradeSucceededReason))
+
+	// test upgrade is rolled back when not ready within the deadline
+	r.Client = fake.NewFakeClient(newDeployment("gatekeeper-audit", 0))
+	gatekeeper.Spec.Image.Image = &newImage
+	r.startUpgrade(gatekeeper)
+	_, err = r.progressUpgrade(ctx, gatekeeper, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(gatekeeper.Status.Upgrade.Phase).To(Equal(operatorv1alpha1.UpgradePhaseAudit))
+	gatekeeper.Status.Upgrade.StartTime = metav1.NewTime(time.Now().Add(-defaultHealthCheckTimeout))
+	_, err = r.progressUpgrade(ctx, gatekeeper, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(gatekeeper.Status.Upgrade.Phase).To(Equal(operatorv1alpha1.UpgradePhaseRolledBack))
+
+	// test upgrade is canceled when the last known-good release is requested
+	gatekeeper.Spec.Image.Image = &oldImage
+	r.startUpgrade(gatekeeper)
+	g.Expect(gatekeeper.Status.Upgrade.Phase).To(Equal(operatorv1alpha1.UpgradePhaseAudit))
+	gatekeeper.Spec.Image.Image = &newerImage
+	r.startUpgrade(gatekeeper)
+	g.Expect(gatekeeper.Status.Upgrade).To(BeNil())
+	g.Expect(gatekeeper.Status.UpgradeConditions[0].Reason).To(Equal(UpgradeCanceledReason))
+}
+
+func TestRecordLastKnownGood(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	gatekeeper := &operatorv1alpha1.Gatekeeper{}
+	r := &GatekeeperReconciler{
+		Client:    fake.NewFakeClient(),
+		Log:       ctrl.Log.WithName("test"),
+		Namespace: namespace,
+	}
+
+	// test not ready
+	upgrading, err := r.progressUpgrade(ctx, gatekeeper, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(upgrading).To(BeFalse())
+	g.Expect(gatekeeper.Status.LastKnownGood).To(BeNil())
+
+	// test ready
+	replicas := int32(1)
+	for _, name := range []string{"gatekeeper-audit", "gatekeeper-controller-manager"} {
+		err = r.Create(ctx, &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status:     appsv1.DeploymentStatus{UpdatedReplicas: 1, AvailableReplicas: 1},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+	}
+	_, err = r.progressUpgrade(ctx, gatekeeper, map[string]bool{WebhookFile: true})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(gatekeeper.Status.LastKnownGood).To(BeNil())
+	_, err = r.progressUpgrade(ctx, gatekeeper, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(sameRelease(*gatekeeper.Status.LastKnownGood, operatorv1alpha1.GatekeeperRelease{Version: util.DefaultGatekeeperVersion})).To(BeTrue())
+
+	// test the spec changed outside of an upgrade is recorded
+	replicas = 2
+	gatekeeper.Spec.Audit = &operatorv1alpha1.AuditConfig{Replicas: &replicas}
+	r.startUpgrade(gatekeeper)
+	g.Expect(gatekeeper.Status.Upgrade).To(BeNil())
+	_, err = r.progressUpgrade(ctx, gatekeeper, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	spec, ok := getLastKnownGoodSpec(gatekeeper.Status.LastKnownGood)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(*spec).To(Equal(gatekeeper.Spec))
+
+	// test the spec of a rolled back upgrade is not recorded
+	gatekeeper.Status.Upgrade = &operatorv1alpha1.UpgradeStatus{Phase: operatorv1alpha1.UpgradePhaseRolledBack}
+	gatekeeper.Spec.Audit = nil
+	_, err = r.progressUpgrade(ctx, gatekeeper, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	spec, _ = getLastKnownGoodSpec(gatekeeper.Status.LastKnownGood)
+	g.Expect(spec.Audit).ToNot(BeNil())
+}
+
+func TestRolledBackSpecHeldBack(t *testing.T) {
+	g := NewWithT(t)
+	oldVersion := "v3.3.0"
+	ignore, fail := admregv1.Ignore, admregv1.Fail
+	gatekeeper := &operatorv1alpha1.Gatekeeper{}
+	gatekeeper.Spec.Version = oldVersion
+	gatekeeper.Spec.Webhook = &operatorv1alpha1.WebhookConfig{FailurePolicy: &ignore}
+	lastKnownGood, err := getLastKnownGood(gatekeeper.Spec)
+	g.Expect(err).ToNot(HaveOccurred())
+	gatekeeper.Status.LastKnownGood = lastKnownGood
+
+	gatekeeper.Spec.Version = util.DefaultGatekeeperVersion
+	gatekeeper.Status.Upgrade = &operatorv1alpha1.UpgradeStatus{
+		Release: getGatekeeperRelease(gatekeeper.Spec),
+		Phase:   operatorv1alpha1.UpgradePhaseRolledBack,
+	}
+	recorder := record.NewFakeRecorder(10)
+	r := &GatekeeperReconciler{
+		Log:      ctrl.Log.WithName("test"),
+		Recorder: recorder,
+	}
+
+	// test nothing is reported without spec changes
+	r.startUpgrade(gatekeeper)
+	g.Expect(gatekeeper.Status.UpgradeConditions).To(BeEmpty())
+	g.Expect(recorder.Events).ToNot(Receive())
+
+	// test spec changes are reported once as held back
+	gatekeeper.Spec.Webhook.FailurePolicy = &fail
+	for i := 0; i < 2; i++ {
+		r.startUpgrade(gatekeeper)
+		g.Expect(gatekeeper.Status.Upgrade.Phase).To(Equal(operatorv1alpha1.UpgradePhaseRolledBack))
+		g.Expect(gatekeeper.Status.UpgradeConditions[0].Reason).To(Equal(SpecHeldBackReason))
+	}
+	g.Expect(recorder.Events).To(Receive(HavePrefix(corev1.EventTypeWarning + " " + SpecHeldBackReason)))
+	g.Expect(recorder.Events).ToNot(Receive())
+	obj, err := util.GetManifestObject(ValidatingWebhookConfiguration)
+	g.Expect(err).ToNot(HaveOccurred())
+	rendered := getRenderedGatekeeper(gatekeeper, ValidatingWebhookConfiguration)
+	g.Expect(crOverrides(rendered, ValidatingWebhookConfiguration, obj, namespace, false)).To(Succeed())
+	assertFailurePolicy(g, obj, ValidationGatekeeperWebhook, &ignore)
+
+	// test spec changes are applied once the last known-good release is
+	// requested again
+	gatekeeper.Spec.Version = oldVersion
+	r.startUpgrade(gatekeeper)
+	g.Expect(gatekeeper.Status.Upgrade).To(BeNil())
+	g.Expect(gatekeeper.Status.UpgradeConditions[0].Type).To(Equal(operatorv1alpha1.StatusReady))
+	g.Expect(gatekeeper.Status.UpgradeConditions[0].Reason).To(Equal(UpgradeRevertedReason))
+	g.Expect(recorder.Events).To(Receive(ContainSubstring(UpgradeRevertedReason)))
+	obj, err = util.GetManifestObject(ValidatingWebhookConfiguration)
+	g.Expect(err).ToNot(HaveOccurred())
+	rendered = getRenderedGatekeeper(gatekeeper, ValidatingWebhookConfiguration)
+	g.Expect(rendered).To(BeIdenticalTo(gatekeeper))
+	g.Expect(crOverrides(rendered, ValidatingWebhookConfiguration, obj, namespace, false)).To(Succeed())
+	assertFailurePolicy(g, obj, ValidationGatekeeperWebhook, &fail)
+}
@@ -0,0 +1,86 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1alpha1 "github.com/gatekeeper/gatekeeper-operator/api/v1alpha1"
+	"github.com/gatekeeper/gatekeeper-operator/pkg/util"
+)
+
+const (
+	UnsupportedVersionReason = "UnsupportedVersion"
+)
+
+func getGatekeeperVersion(spec operatorv1alpha1.GatekeeperSpec) string {
+	if spec.Version == "" {
+		return util.DefaultGatekeeperVersion
+	}
+	return spec.Version
+}
+
+// getManifestVersion returns the Gatekeeper version whose manifests the given
+// asset is rendered from, which during an upgrade can be the last known-good
+// one. The deployed version is used while the requested version is not
+// bundled with the operator, so that its resources can still be found.
+func getManifestVersion(gatekeeper *operatorv1alpha1.Gatekeeper, asset string) string {
+	version := getGatekeeperVersion(getRenderedGatekeeper(gatekeeper, asset).Spec)
+	if gatekeeper.Status.Version != "" {
+		if supported, _, err := isSupportedVersion(version); err != nil || !supported {
+			return gatekeeper.Status.Version
+		}
+	}
+	return version
+}
+
+// isSupportedVersion returns whether the manifests of the given Gatekeeper
+// version are bundled with the operator, along with the bundled versions.
+func isSupportedVersion(version string) (bool, []string, error) {
+	versions, err := util.GetGatekeeperVersions()
+	if err != nil {
+		return false, nil, err
+	}
+	for _, v := range versions {
+		if v == version {
+			return true, versions, nil
+		}
+	}
+	return false, versions, nil
+}
+
+// updateUnsupportedVersionStatus reports both Gatekeeper components as not
+// ready because the requested version is not bundled with the operator. The
+// resources of the previously deployed version are left as they are.
+func (r *GatekeeperReconciler) updateUnsupportedVersionStatus(ctx context.Context, gatekeeper *operatorv1alpha1.Gatekeeper,
+	version string, versions []string) error {
+	condition := notReadyCondition(UnsupportedVersionReason,
+		fmt.Sprintf("Gatekeeper version %s is not supported, supported versions: %s", version, strings.Join(versions, ", ")))
+
+	now := metav1.Now()
+	gatekeeper.Status.ObservedGeneration = gatekeeper.GetGeneration()
+	gatekeeper.Status.AuditConditions = setStatusCondition(gatekeeper.Status.AuditConditions, condition, now)
+	gatekeeper.Status.WebhookConditions = setStatusCondition(gatekeeper.Status.WebhookConditions, condition, now)
+
+	if err := r.Status().Update(ctx, gatekeeper); err != nil {
+		return errors.Wrapf(err, "Unable to update Gatekeeper status")
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1alpha1 "github.com/gatekeeper/gatekeeper-operator/api/v1alpha1"
+	"github.com/gatekeeper/gatekeeper-operator/pkg/util"
+)
+
+func TestGatekeeperVersion(t *testing.T) {
+	g := NewWithT(t)
+
+	// test default
+	version := getGatekeeperVersion(operatorv1alpha1.GatekeeperSpec{})
+	g.Expect(version).To(Equal(util.DefaultGatekeeperVersion))
+	supported, versions, err := isSupportedVersion(version)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(supported).To(BeTrue())
+	g.Expect(versions).To(ContainElement(util.DefaultGatekeeperVersion))
+
+	// test unknown version
+	version = getGatekeeperVersion(operatorv1alpha1.GatekeeperSpec{Version: "v0.0.1"})
+	g.Expect(version).To(Equal("v0.0.1"))
+	supported, _, err = isSupportedVersion(version)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(supported).To(BeFalse())
+}
+
+func TestVersionedManifestObject(t *testing.T) {
+	g := NewWithT(t)
+	r := &GatekeeperReconciler{}
+
+	obj, err := r.getVersionedManifestObject(util.DefaultGatekeeperVersion, AuditFile)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(obj.GetKind()).To(Equal("Deployment"))
+
+	_, err = r.getVersionedManifestObject("v0.0.1", AuditFile)
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestRenderNonDefaultVersion(t *testing.T) {
+	g := NewWithT(t)
+	r := &GatekeeperReconciler{}
+	gatekeeper := &operatorv1alpha1.Gatekeeper{}
+	gatekeeper.Spec.Version = "v3.3.0"
+	g.Expect(gatekeeper.Spec.Version).ToNot(Equal(util.DefaultGatekeeperVersion))
+	version := getManifestVersion(gatekeeper, WebhookFile)
+	g.Expect(version).To(Equal("v3.3.0"))
+
+	// test the webhook is rendered with the image and flags of the version
+	webhookObj, err := r.getVersionedManifestObject(version, WebhookFile)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(crOverrides(gatekeeper, WebhookFile, webhookObj, namespace, false)).To(Succeed())
+	g.Expect(getManagerImage(webhookObj)).To(Equal("openpolicyagent/gatekeeper:v3.3.0"))
+	expectObjContainerArgument(g, managerContainer, webhookObj).NotTo(HaveKey("--disable-opa-builtin"))
+
+	webhookObj, err = r.getVersionedManifestObject(util.DefaultGatekeeperVersion, WebhookFile)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(getManagerImage(webhookObj)).To(Equal("openpolicyagent/gatekeeper:" + util.DefaultGatekeeperVersion))
+	expectObjContainerArgument(g, managerContainer, webhookObj).To(HaveKeyWithValue("--disable-opa-builtin", "{http.send}"))
+
+	// test the CRDs are rendered from the version
+	for v, subresources := range map[string]bool{version: false, util.DefaultGatekeeperVersion: true} {
+		crdObj, err := r.getVersionedManifestObject(v, AssignCRDFile)
+		g.Expect(err).ToNot(HaveOccurred())
+		_, found, err := unstructured.NestedMap(crdObj.Object, "spec", "subresources")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(found).To(Equal(subresources))
+	}
+
+	// test the resources of an unsupported version are found from the
+	// deployed version
+	gatekeeper.Spec.Version = "v0.0.1"
+	g.Expect(getManifestVersion(gatekeeper, WebhookFile)).To(Equal("v0.0.1"))
+	gatekeeper.Status.Version = version
+	g.Expect(getManifestVersion(gatekeeper, WebhookFile)).To(Equal(version))
+}
+
+func TestUpdateUnsupportedVersionStatus(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	g.Expect(operatorv1alpha1.AddToScheme(scheme)).To(Succeed())
+	gatekeeper := &operatorv1alpha1.Gatekeeper{}
+	gatekeeper.SetName(defaultGatekeeperCrName)
+	gatekeeper.Spec.Version = "v0.0.1"
+	r := &GatekeeperReconciler{
+		Client: fake.NewFakeClientWithScheme(scheme, gatekeeper),
+	}
+
+	err := r.updateUnsupportedVersionStatus(ctx, gatekeeper, gatekeeper.Spec.Version, []string{util.DefaultGatekeeperVersion})
+	g.Expect(err).ToNot(HaveOccurred())
+	updated := &operatorv1alpha1.Gatekeeper{}
+	g.Expect(r.Get(ctx, types.NamespacedName{Name: defaultGatekeeperCrName}, updated)).To(Succeed())
+	for _, conditions := range [][]operatorv1alpha1.StatusCondition{updated.Status.AuditConditions, updated.Status.WebhookConditions} {
+		g.Expect(conditions).To(HaveLen(1))
+		g.Expect(conditions[0].Type).To(Equal(operatorv1alpha1.StatusNotReady))
+		g.Expect(conditions[0].Reason).To(Equal(UnsupportedVersionReason))
+		g.Expect(conditions[0].Message).To(ContainSubstring(util.DefaultGatekeeperVersion))
+	}
+	g.Expect(updated.Status.Version).To(BeEmpty())
+}
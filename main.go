@@ -30,6 +30,7 @@ import (
 
 	"github.com/RHsyseng/operator-utils/pkg/utils/openshift"
 	operatorv1alpha1 "github.com/gatekeeper/gatekeeper-operator/api/v1alpha1"
+	operatorv1beta1 "github.com/gatekeeper/gatekeeper-operator/api/v1beta1"
 	"github.com/gatekeeper/gatekeeper-operator/controllers"
 	"github.com/gatekeeper/gatekeeper-operator/pkg/util"
 	"github.com/gatekeeper/gatekeeper-operator/pkg/version"
@@ -46,6 +47,7 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(operatorv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(operatorv1beta1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -87,16 +89,49 @@ func main() {
 		os.Exit(1)
 	}
 
+	apiVersions, err := controllers.DiscoverAPIVersions(cfg)
+	if err != nil {
+		setupLog.Error(err, "unable to discover API versions")
+		os.Exit(1)
+	}
+	setupLog.Info("discovered API versions", "apiVersions", fmt.Sprintf("%+v", apiVersions))
+
+	// The operator namespace is exempt from Gatekeeper when known, e.g. it is
+	// unknown when running the operator outside of the cluster.
+	operatorNamespace, err := util.GetOperatorNamespace()
+	if err != nil {
+		setupLog.Info("unable to get operator namespace, not exempting it from Gatekeeper", "reason", err.Error())
+	}
+
 	if err = (&controllers.GatekeeperReconciler{
-		Client:       mgr.GetClient(),
-		Log:          ctrl.Log.WithName("controllers").WithName("Gatekeeper"),
-		Scheme:       mgr.GetScheme(),
-		Namespace:    namespace,
-		PlatformName: util.PlatformType(platformName),
+		Client:            mgr.GetClient(),
+		APIReader:         mgr.GetAPIReader(),
+		Log:               ctrl.Log.WithName("controllers").WithName("Gatekeeper"),
+		Scheme:            mgr.GetScheme(),
+		Namespace:         namespace,
+		OperatorNamespace: operatorNamespace,
+		PlatformName:      util.PlatformType(platformName),
+		APIVersions:       apiVersions,
+		Recorder:          mgr.GetEventRecorderFor("gatekeeper-operator"),
+		// Set by OLM so that the image can be mirrored, the image of the
+		// Gatekeeper manifests is used otherwise.
+		DefaultImage: os.Getenv("RELATED_IMAGE_GATEKEEPER"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Gatekeeper")
 		os.Exit(1)
 	}
+	// The webhook needs a serving certificate, e.g. it is disabled when
+	// running the operator outside of the cluster.
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = (&operatorv1alpha1.Gatekeeper{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Gatekeeper")
+			os.Exit(1)
+		}
+		if err = (&operatorv1beta1.Gatekeeper{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Gatekeeper")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	setupLog.Info("starting manager")
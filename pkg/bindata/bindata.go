@@ -1,25 +1,48 @@
 // Code generated for package bindata by go-bindata DO NOT EDIT. (@generated)
 // sources:
-// config/gatekeeper/admissionregistration.k8s.io_v1beta1_mutatingwebhookconfiguration_gatekeeper-mutating-webhook-configuration.yaml
-// config/gatekeeper/admissionregistration.k8s.io_v1beta1_validatingwebhookconfiguration_gatekeeper-validating-webhook-configuration.yaml
-// config/gatekeeper/apiextensions.k8s.io_v1beta1_customresourcedefinition_assign.mutations.gatekeeper.sh.yaml
-// config/gatekeeper/apiextensions.k8s.io_v1beta1_customresourcedefinition_assignmetadata.mutations.gatekeeper.sh.yaml
-// config/gatekeeper/apiextensions.k8s.io_v1beta1_customresourcedefinition_configs.config.gatekeeper.sh.yaml
-// config/gatekeeper/apiextensions.k8s.io_v1beta1_customresourcedefinition_constraintpodstatuses.status.gatekeeper.sh.yaml
-// config/gatekeeper/apiextensions.k8s.io_v1beta1_customresourcedefinition_constrainttemplatepodstatuses.status.gatekeeper.sh.yaml
-// config/gatekeeper/apiextensions.k8s.io_v1beta1_customresourcedefinition_constrainttemplates.templates.gatekeeper.sh.yaml
-// config/gatekeeper/apps_v1_deployment_gatekeeper-audit.yaml
-// config/gatekeeper/apps_v1_deployment_gatekeeper-controller-manager.yaml
-// config/gatekeeper/openshift/rbac.authorization.k8s.io_v1_role_gatekeeper-manager-role.yaml
-// config/gatekeeper/policy_v1beta1_podsecuritypolicy_gatekeeper-admin.yaml
-// config/gatekeeper/rbac.authorization.k8s.io_v1_clusterrole_gatekeeper-manager-role.yaml
-// config/gatekeeper/rbac.authorization.k8s.io_v1_clusterrolebinding_gatekeeper-manager-rolebinding.yaml
-// config/gatekeeper/rbac.authorization.k8s.io_v1_role_gatekeeper-manager-role.yaml
-// config/gatekeeper/rbac.authorization.k8s.io_v1_rolebinding_gatekeeper-manager-rolebinding.yaml
-// config/gatekeeper/v1_namespace_gatekeeper-system.yaml
-// config/gatekeeper/v1_secret_gatekeeper-webhook-server-cert.yaml
-// config/gatekeeper/v1_service_gatekeeper-webhook-service.yaml
-// config/gatekeeper/v1_serviceaccount_gatekeeper-admin.yaml
+// config/gatekeeper/v3.3.0/admissionregistration.k8s.io_v1beta1_mutatingwebhookconfiguration_gatekeeper-mutating-webhook-configuration.yaml
+// config/gatekeeper/v3.3.0/admissionregistration.k8s.io_v1beta1_validatingwebhookconfiguration_gatekeeper-validating-webhook-configuration.yaml
+// config/gatekeeper/v3.3.0/apiextensions.k8s.io_v1beta1_customresourcedefinition_assign.mutations.gatekeeper.sh.yaml
+// config/gatekeeper/v3.3.0/apiextensions.k8s.io_v1beta1_customresourcedefinition_assignmetadata.mutations.gatekeeper.sh.yaml
+// config/gatekeeper/v3.3.0/apiextensions.k8s.io_v1beta1_customresourcedefinition_configs.config.gatekeeper.sh.yaml
+// config/gatekeeper/v3.3.0/apiextensions.k8s.io_v1beta1_customresourcedefinition_constraintpodstatuses.status.gatekeeper.sh.yaml
+// config/gatekeeper/v3.3.0/apiextensions.k8s.io_v1beta1_customresourcedefinition_constrainttemplatepodstatuses.status.gatekeeper.sh.yaml
+// config/gatekeeper/v3.3.0/apiextensions.k8s.io_v1beta1_customresourcedefinition_constrainttemplates.templates.gatekeeper.sh.yaml
+// config/gatekeeper/v3.3.0/apps_v1_deployment_gatekeeper-audit.yaml
+// config/gatekeeper/v3.3.0/apps_v1_deployment_gatekeeper-controller-manager.yaml
+// config/gatekeeper/v3.3.0/policy_v1beta1_podsecuritypolicy_gatekeeper-admin.yaml
+// config/gatekeeper/v3.3.0/rbac.authorization.k8s.io_v1_clusterrole_gatekeeper-manager-role.yaml
+// config/gatekeeper/v3.3.0/rbac.authorization.k8s.io_v1_clusterrolebinding_gatekeeper-manager-rolebinding.yaml
+// config/gatekeeper/v3.3.0/rbac.authorization.k8s.io_v1_role_gatekeeper-manager-role.yaml
+// config/gatekeeper/v3.3.0/rbac.authorization.k8s.io_v1_rolebinding_gatekeeper-manager-rolebinding.yaml
+// config/gatekeeper/v3.3.0/v1_namespace_gatekeeper-system.yaml
+// config/gatekeeper/v3.3.0/v1_secret_gatekeeper-webhook-server-cert.yaml
+// config/gatekeeper/v3.3.0/v1_service_gatekeeper-webhook-service.yaml
+// config/gatekeeper/v3.3.0/v1_serviceaccount_gatekeeper-admin.yaml
+// config/gatekeeper/v3.4.0/admissionregistration.k8s.io_v1beta1_mutatingwebhookconfiguration_gatekeeper-mutating-webhook-configuration.yaml
+// config/gatekeeper/v3.4.0/admissionregistration.k8s.io_v1beta1_validatingwebhookconfiguration_gatekeeper-validating-webhook-configuration.yaml
+// config/gatekeeper/v3.4.0/apiextensions.k8s.io_v1beta1_customresourcedefinition_assign.mutations.gatekeeper.sh.yaml
+// config/gatekeeper/v3.4.0/apiextensions.k8s.io_v1beta1_customresourcedefinition_assignmetadata.mutations.gatekeeper.sh.yaml
+// config/gatekeeper/v3.4.0/apiextensions.k8s.io_v1beta1_customresourcedefinition_configs.config.gatekeeper.sh.yaml
+// config/gatekeeper/v3.4.0/apiextensions.k8s.io_v1beta1_customresourcedefinition_constraintpodstatuses.status.gatekeeper.sh.yaml
+// config/gatekeeper/v3.4.0/apiextensions.k8s.io_v1beta1_customresourcedefinition_constrainttemplatepodstatuses.status.gatekeeper.sh.yaml
+// config/gatekeeper/v3.4.0/apiextensions.k8s.io_v1beta1_customresourcedefinition_constrainttemplates.templates.gatekeeper.sh.yaml
+// config/gatekeeper/v3.4.0/apps_v1_deployment_gatekeeper-audit.yaml
+// config/gatekeeper/v3.4.0/apps_v1_deployment_gatekeeper-controller-manager.yaml
+// config/gatekeeper/v3.4.0/policy_v1beta1_podsecuritypolicy_gatekeeper-admin.yaml
+// config/gatekeeper/v3.4.0/rbac.authorization.k8s.io_v1_clusterrole_gatekeeper-manager-role.yaml
+// config/gatekeeper/v3.4.0/rbac.authorization.k8s.io_v1_clusterrolebinding_gatekeeper-manager-rolebinding.yaml
+// config/gatekeeper/v3.4.0/rbac.authorization.k8s.io_v1_role_gatekeeper-manager-role.yaml
+// config/gatekeeper/v3.4.0/rbac.authorization.k8s.io_v1_rolebinding_gatekeeper-manager-rolebinding.yaml
+// config/gatekeeper/v3.4.0/v1_namespace_gatekeeper-system.yaml
+// config/gatekeeper/v3.4.0/v1_secret_gatekeeper-webhook-server-cert.yaml
+// config/gatekeeper/v3.4.0/v1_service_gatekeeper-webhook-service.yaml
+// config/gatekeeper/v3.4.0/v1_serviceaccount_gatekeeper-admin.yaml
+// config/operator-assets/certmanager/cert-manager.io_v1_certificate_gatekeeper-serving-cert.yaml
+// config/operator-assets/certmanager/cert-manager.io_v1_issuer_gatekeeper-selfsigned-issuer.yaml
+// config/operator-assets/config.gatekeeper.sh_v1alpha1_config_config.yaml
+// config/operator-assets/policy_v1beta1_poddisruptionbudget_gatekeeper-audit.yaml
+// config/operator-assets/policy_v1beta1_poddisruptionbudget_gatekeeper-controller-manager.yaml
 package bindata
 
 import (
@@ -73,7 +96,7 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var _configGatekeeperAdmissionregistrationK8sIo_v1beta1_mutatingwebhookconfiguration_gatekeeperMutatingWebhookConfigurationYaml = []byte(`apiVersion: admissionregistration.k8s.io/v1beta1
+var _configGatekeeperV330AdmissionregistrationK8sIo_v1beta1_mutatingwebhookconfiguration_gatekeeperMutatingWebhookConfigurationYaml = []byte(`apiVersion: admissionregistration.k8s.io/v1beta1
 kind: MutatingWebhookConfiguration
 metadata:
   creationTimestamp: null
@@ -99,22 +122,22 @@ webhooks:
     - '*'
 `)
 
-func configGatekeeperAdmissionregistrationK8sIo_v1beta1_mutatingwebhookconfiguration_gatekeeperMutatingWebhookConfigurationYamlBytes() ([]byte, error) {
-	return _configGatekeeperAdmissionregistrationK8sIo_v1beta1_mutatingwebhookconfiguration_gatekeeperMutatingWebhookConfigurationYaml, nil
+func configGatekeeperV330AdmissionregistrationK8sIo_v1beta1_mutatingwebhookconfiguration_gatekeeperMutatingWebhookConfigurationYamlBytes() ([]byte, error) {
+	return _configGatekeeperV330AdmissionregistrationK8sIo_v1beta1_mutatingwebhookconfiguration_gatekeeperMutatingWebhookConfigurationYaml, nil
 }
 
-func configGatekeeperAdmissionregistrationK8sIo_v1beta1_mutatingwebhookconfiguration_gatekeeperMutatingWebhookConfigurationYaml() (*asset, error) {
-	bytes, err := configGatekeeperAdmissionregistrationK8sIo_v1beta1_mutatingwebhookconfiguration_gatekeeperMutatingWebhookConfigurationYamlBytes()
+func configGatekeeperV330AdmissionregistrationK8sIo_v1beta1_mutatingwebhookconfiguration_gatekeeperMutatingWebhookConfigurationYaml() (*asset, error) {
+	bytes, err := configGatekeeperV330AdmissionregistrationK8sIo_v1beta1_mutatingwebhookconfiguration_gatekeeperMutatingWebhookConfigurationYamlBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "config/gatekeeper/admissionregistration.k8s.io_v1beta1_mutatingwebhookconfiguration_gatekeeper-mutating-webhook-configuration.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	info := bindataFileInfo{name: "config/gatekeeper/v3.3.0/admissionregistration.k8s.io_v1beta1_mutatingwebhookconfiguration_gatekeeper-mutating-webhook-configuration.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _configGatekeeperAdmissionregistrationK8sIo_v1beta1_validatingwebhookconfiguration_gatekeeperValidatingWebhookConfigurationYaml = []byte(`apiVersion: admissionregistration.k8s.io/v1beta1
+var _configGatekeeperV330AdmissionregistrationK8sIo_v1beta1_validatingwebhookconfiguration_gatekeeperValidatingWebhookConfigurationYaml = []byte(`apiVersion: admissionregistration.k8s.io/v1beta1
 kind: ValidatingWebhookConfiguration
 metadata:
   labels:
@@ -167,22 +190,22 @@ webhooks:
   timeoutSeconds: 3
 `)
 
-func configGatekeeperAdmissionregistrationK8sIo_v1beta1_validatingwebhookconfiguration_gatekeeperValidatingWebhookConfigurationYamlBytes() ([]byte, error) {
-	return _configGatekeeperAdmissionregistrationK8sIo_v1beta1_validatingwebhookconfiguration_gatekeeperValidatingWebhookConfigurationYaml, nil
+func configGatekeeperV330AdmissionregistrationK8sIo_v1beta1_validatingwebhookconfiguration_gatekeeperValidatingWebhookConfigurationYamlBytes() ([]byte, error) {
+	return _configGatekeeperV330AdmissionregistrationK8sIo_v1beta1_validatingwebhookconfiguration_gatekeeperValidatingWebhookConfigurationYaml, nil
 }
 
-func configGatekeeperAdmissionregistrationK8sIo_v1beta1_validatingwebhookconfiguration_gatekeeperValidatingWebhookConfigurationYaml() (*asset, error) {
-	bytes, err := configGatekeeperAdmissionregistrationK8sIo_v1beta1_validatingwebhookconfiguration_gatekeeperValidatingWebhookConfigurationYamlBytes()
+func configGatekeeperV330AdmissionregistrationK8sIo_v1beta1_validatingwebhookconfiguration_gatekeeperValidatingWebhookConfigurationYaml() (*asset, error) {
+	bytes, err := configGatekeeperV330AdmissionregistrationK8sIo_v1beta1_validatingwebhookconfiguration_gatekeeperValidatingWebhookConfigurationYamlBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "config/gatekeeper/admissionregistration.k8s.io_v1beta1_validatingwebhookconfiguration_gatekeeper-validating-webhook-configuration.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	info := bindataFileInfo{name: "config/gatekeeper/v3.3.0/admissionregistration.k8s.io_v1beta1_validatingwebhookconfiguration_gatekeeper-validating-webhook-configuration.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_assignMutationsGatekeeperShYaml = []byte(`apiVersion: apiextensions.k8s.io/v1beta1
+var _configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_assignMutationsGatekeeperShYaml = []byte(`apiVersion: apiextensions.k8s.io/v1beta1
 kind: CustomResourceDefinition
 metadata:
   annotations:
@@ -374,22 +397,22 @@ status:
   storedVersions: []
 `)
 
-func configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_assignMutationsGatekeeperShYamlBytes() ([]byte, error) {
-	return _configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_assignMutationsGatekeeperShYaml, nil
+func configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_assignMutationsGatekeeperShYamlBytes() ([]byte, error) {
+	return _configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_assignMutationsGatekeeperShYaml, nil
 }
 
-func configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_assignMutationsGatekeeperShYaml() (*asset, error) {
-	bytes, err := configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_assignMutationsGatekeeperShYamlBytes()
+func configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_assignMutationsGatekeeperShYaml() (*asset, error) {
+	bytes, err := configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_assignMutationsGatekeeperShYamlBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "config/gatekeeper/apiextensions.k8s.io_v1beta1_customresourcedefinition_assign.mutations.gatekeeper.sh.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	info := bindataFileInfo{name: "config/gatekeeper/v3.3.0/apiextensions.k8s.io_v1beta1_customresourcedefinition_assign.mutations.gatekeeper.sh.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_assignmetadataMutationsGatekeeperShYaml = []byte(`apiVersion: apiextensions.k8s.io/v1beta1
+var _configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_assignmetadataMutationsGatekeeperShYaml = []byte(`apiVersion: apiextensions.k8s.io/v1beta1
 kind: CustomResourceDefinition
 metadata:
   annotations:
@@ -539,22 +562,22 @@ status:
   storedVersions: []
 `)
 
-func configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_assignmetadataMutationsGatekeeperShYamlBytes() ([]byte, error) {
-	return _configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_assignmetadataMutationsGatekeeperShYaml, nil
+func configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_assignmetadataMutationsGatekeeperShYamlBytes() ([]byte, error) {
+	return _configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_assignmetadataMutationsGatekeeperShYaml, nil
 }
 
-func configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_assignmetadataMutationsGatekeeperShYaml() (*asset, error) {
-	bytes, err := configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_assignmetadataMutationsGatekeeperShYamlBytes()
+func configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_assignmetadataMutationsGatekeeperShYaml() (*asset, error) {
+	bytes, err := configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_assignmetadataMutationsGatekeeperShYamlBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "config/gatekeeper/apiextensions.k8s.io_v1beta1_customresourcedefinition_assignmetadata.mutations.gatekeeper.sh.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	info := bindataFileInfo{name: "config/gatekeeper/v3.3.0/apiextensions.k8s.io_v1beta1_customresourcedefinition_assignmetadata.mutations.gatekeeper.sh.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_configsConfigGatekeeperShYaml = []byte(`apiVersion: apiextensions.k8s.io/v1beta1
+var _configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_configsConfigGatekeeperShYaml = []byte(`apiVersion: apiextensions.k8s.io/v1beta1
 kind: CustomResourceDefinition
 metadata:
   annotations:
@@ -666,22 +689,22 @@ status:
   storedVersions: []
 `)
 
-func configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_configsConfigGatekeeperShYamlBytes() ([]byte, error) {
-	return _configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_configsConfigGatekeeperShYaml, nil
+func configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_configsConfigGatekeeperShYamlBytes() ([]byte, error) {
+	return _configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_configsConfigGatekeeperShYaml, nil
 }
 
-func configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_configsConfigGatekeeperShYaml() (*asset, error) {
-	bytes, err := configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_configsConfigGatekeeperShYamlBytes()
+func configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_configsConfigGatekeeperShYaml() (*asset, error) {
+	bytes, err := configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_configsConfigGatekeeperShYamlBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "config/gatekeeper/apiextensions.k8s.io_v1beta1_customresourcedefinition_configs.config.gatekeeper.sh.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	info := bindataFileInfo{name: "config/gatekeeper/v3.3.0/apiextensions.k8s.io_v1beta1_customresourcedefinition_configs.config.gatekeeper.sh.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_constraintpodstatusesStatusGatekeeperShYaml = []byte(`apiVersion: apiextensions.k8s.io/v1beta1
+var _configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_constraintpodstatusesStatusGatekeeperShYaml = []byte(`apiVersion: apiextensions.k8s.io/v1beta1
 kind: CustomResourceDefinition
 metadata:
   annotations:
@@ -757,22 +780,22 @@ status:
   storedVersions: []
 `)
 
-func configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_constraintpodstatusesStatusGatekeeperShYamlBytes() ([]byte, error) {
-	return _configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_constraintpodstatusesStatusGatekeeperShYaml, nil
+func configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_constraintpodstatusesStatusGatekeeperShYamlBytes() ([]byte, error) {
+	return _configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_constraintpodstatusesStatusGatekeeperShYaml, nil
 }
 
-func configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_constraintpodstatusesStatusGatekeeperShYaml() (*asset, error) {
-	bytes, err := configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_constraintpodstatusesStatusGatekeeperShYamlBytes()
+func configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_constraintpodstatusesStatusGatekeeperShYaml() (*asset, error) {
+	bytes, err := configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_constraintpodstatusesStatusGatekeeperShYamlBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "config/gatekeeper/apiextensions.k8s.io_v1beta1_customresourcedefinition_constraintpodstatuses.status.gatekeeper.sh.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	info := bindataFileInfo{name: "config/gatekeeper/v3.3.0/apiextensions.k8s.io_v1beta1_customresourcedefinition_constraintpodstatuses.status.gatekeeper.sh.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatepodstatusesStatusGatekeeperShYaml = []byte(`apiVersion: apiextensions.k8s.io/v1beta1
+var _configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatepodstatusesStatusGatekeeperShYaml = []byte(`apiVersion: apiextensions.k8s.io/v1beta1
 kind: CustomResourceDefinition
 metadata:
   annotations:
@@ -847,22 +870,22 @@ status:
   storedVersions: []
 `)
 
-func configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatepodstatusesStatusGatekeeperShYamlBytes() ([]byte, error) {
-	return _configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatepodstatusesStatusGatekeeperShYaml, nil
+func configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatepodstatusesStatusGatekeeperShYamlBytes() ([]byte, error) {
+	return _configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatepodstatusesStatusGatekeeperShYaml, nil
 }
 
-func configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatepodstatusesStatusGatekeeperShYaml() (*asset, error) {
-	bytes, err := configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatepodstatusesStatusGatekeeperShYamlBytes()
+func configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatepodstatusesStatusGatekeeperShYaml() (*asset, error) {
+	bytes, err := configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatepodstatusesStatusGatekeeperShYamlBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "config/gatekeeper/apiextensions.k8s.io_v1beta1_customresourcedefinition_constrainttemplatepodstatuses.status.gatekeeper.sh.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	info := bindataFileInfo{name: "config/gatekeeper/v3.3.0/apiextensions.k8s.io_v1beta1_customresourcedefinition_constrainttemplatepodstatuses.status.gatekeeper.sh.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatesTemplatesGatekeeperShYaml = []byte(`apiVersion: apiextensions.k8s.io/v1beta1
+var _configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatesTemplatesGatekeeperShYaml = []byte(`apiVersion: apiextensions.k8s.io/v1beta1
 kind: CustomResourceDefinition
 metadata:
   creationTimestamp: null
@@ -968,22 +991,22 @@ status:
   storedVersions: []
 `)
 
-func configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatesTemplatesGatekeeperShYamlBytes() ([]byte, error) {
-	return _configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatesTemplatesGatekeeperShYaml, nil
+func configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatesTemplatesGatekeeperShYamlBytes() ([]byte, error) {
+	return _configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatesTemplatesGatekeeperShYaml, nil
 }
 
-func configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatesTemplatesGatekeeperShYaml() (*asset, error) {
-	bytes, err := configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatesTemplatesGatekeeperShYamlBytes()
+func configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatesTemplatesGatekeeperShYaml() (*asset, error) {
+	bytes, err := configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatesTemplatesGatekeeperShYamlBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "config/gatekeeper/apiextensions.k8s.io_v1beta1_customresourcedefinition_constrainttemplates.templates.gatekeeper.sh.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	info := bindataFileInfo{name: "config/gatekeeper/v3.3.0/apiextensions.k8s.io_v1beta1_customresourcedefinition_constrainttemplates.templates.gatekeeper.sh.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _configGatekeeperApps_v1_deployment_gatekeeperAuditYaml = []byte(`apiVersion: apps/v1
+var _configGatekeeperV330Apps_v1_deployment_gatekeeperAuditYaml = []byte(`apiVersion: apps/v1
 kind: Deployment
 metadata:
   labels:
@@ -1066,22 +1089,22 @@ spec:
       terminationGracePeriodSeconds: 60
 `)
 
-func configGatekeeperApps_v1_deployment_gatekeeperAuditYamlBytes() ([]byte, error) {
-	return _configGatekeeperApps_v1_deployment_gatekeeperAuditYaml, nil
+func configGatekeeperV330Apps_v1_deployment_gatekeeperAuditYamlBytes() ([]byte, error) {
+	return _configGatekeeperV330Apps_v1_deployment_gatekeeperAuditYaml, nil
 }
 
-func configGatekeeperApps_v1_deployment_gatekeeperAuditYaml() (*asset, error) {
-	bytes, err := configGatekeeperApps_v1_deployment_gatekeeperAuditYamlBytes()
+func configGatekeeperV330Apps_v1_deployment_gatekeeperAuditYaml() (*asset, error) {
+	bytes, err := configGatekeeperV330Apps_v1_deployment_gatekeeperAuditYamlBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "config/gatekeeper/apps_v1_deployment_gatekeeper-audit.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	info := bindataFileInfo{name: "config/gatekeeper/v3.3.0/apps_v1_deployment_gatekeeper-audit.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _configGatekeeperApps_v1_deployment_gatekeeperControllerManagerYaml = []byte(`apiVersion: apps/v1
+var _configGatekeeperV330Apps_v1_deployment_gatekeeperControllerManagerYaml = []byte(`apiVersion: apps/v1
 kind: Deployment
 metadata:
   labels:
@@ -1189,75 +1212,1585 @@ spec:
           secretName: gatekeeper-webhook-server-cert
 `)
 
-func configGatekeeperApps_v1_deployment_gatekeeperControllerManagerYamlBytes() ([]byte, error) {
-	return _configGatekeeperApps_v1_deployment_gatekeeperControllerManagerYaml, nil
+func configGatekeeperV330Apps_v1_deployment_gatekeeperControllerManagerYamlBytes() ([]byte, error) {
+	return _configGatekeeperV330Apps_v1_deployment_gatekeeperControllerManagerYaml, nil
 }
 
-func configGatekeeperApps_v1_deployment_gatekeeperControllerManagerYaml() (*asset, error) {
-	bytes, err := configGatekeeperApps_v1_deployment_gatekeeperControllerManagerYamlBytes()
+func configGatekeeperV330Apps_v1_deployment_gatekeeperControllerManagerYaml() (*asset, error) {
+	bytes, err := configGatekeeperV330Apps_v1_deployment_gatekeeperControllerManagerYamlBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "config/gatekeeper/apps_v1_deployment_gatekeeper-controller-manager.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	info := bindataFileInfo{name: "config/gatekeeper/v3.3.0/apps_v1_deployment_gatekeeper-controller-manager.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _configGatekeeperOpenshiftRbacAuthorizationK8sIo_v1_role_gatekeeperManagerRoleYaml = []byte(`apiVersion: rbac.authorization.k8s.io/v1
+var _configGatekeeperV330Policy_v1beta1_podsecuritypolicy_gatekeeperAdminYaml = []byte(`apiVersion: policy/v1beta1
+kind: PodSecurityPolicy
+metadata:
+  annotations:
+    seccomp.security.alpha.kubernetes.io/allowedProfileNames: '*'
+  labels:
+    gatekeeper.sh/system: "yes"
+  name: gatekeeper-admin
+spec:
+  allowPrivilegeEscalation: false
+  fsGroup:
+    ranges:
+    - max: 65535
+      min: 1
+    rule: MustRunAs
+  requiredDropCapabilities:
+  - ALL
+  runAsUser:
+    rule: MustRunAsNonRoot
+  seLinux:
+    rule: RunAsAny
+  supplementalGroups:
+    ranges:
+    - max: 65535
+      min: 1
+    rule: MustRunAs
+  volumes:
+  - configMap
+  - projected
+  - secret
+  - downwardAPI
+`)
+
+func configGatekeeperV330Policy_v1beta1_podsecuritypolicy_gatekeeperAdminYamlBytes() ([]byte, error) {
+	return _configGatekeeperV330Policy_v1beta1_podsecuritypolicy_gatekeeperAdminYaml, nil
+}
+
+func configGatekeeperV330Policy_v1beta1_podsecuritypolicy_gatekeeperAdminYaml() (*asset, error) {
+	bytes, err := configGatekeeperV330Policy_v1beta1_podsecuritypolicy_gatekeeperAdminYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/gatekeeper/v3.3.0/policy_v1beta1_podsecuritypolicy_gatekeeper-admin.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _configGatekeeperV330RbacAuthorizationK8sIo_v1_clusterrole_gatekeeperManagerRoleYaml = []byte(`apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  creationTimestamp: null
+  labels:
+    gatekeeper.sh/system: "yes"
+  name: gatekeeper-manager-role
+rules:
+- apiGroups:
+  - '*'
+  resources:
+  - '*'
+  verbs:
+  - get
+  - list
+  - watch
+- apiGroups:
+  - apiextensions.k8s.io
+  resources:
+  - customresourcedefinitions
+  verbs:
+  - create
+  - delete
+  - get
+  - list
+  - patch
+  - update
+  - watch
+- apiGroups:
+  - config.gatekeeper.sh
+  resources:
+  - configs
+  verbs:
+  - create
+  - delete
+  - get
+  - list
+  - patch
+  - update
+  - watch
+- apiGroups:
+  - config.gatekeeper.sh
+  resources:
+  - configs/status
+  verbs:
+  - get
+  - patch
+  - update
+- apiGroups:
+  - constraints.gatekeeper.sh
+  resources:
+  - '*'
+  verbs:
+  - create
+  - delete
+  - get
+  - list
+  - patch
+  - update
+  - watch
+- apiGroups:
+  - mutations.gatekeeper.sh
+  resources:
+  - '*'
+  verbs:
+  - create
+  - delete
+  - get
+  - list
+  - patch
+  - update
+  - watch
+- apiGroups:
+  - policy
+  resourceNames:
+  - gatekeeper-admin
+  resources:
+  - podsecuritypolicies
+  verbs:
+  - use
+- apiGroups:
+  - status.gatekeeper.sh
+  resources:
+  - '*'
+  verbs:
+  - create
+  - delete
+  - get
+  - list
+  - patch
+  - update
+  - watch
+- apiGroups:
+  - templates.gatekeeper.sh
+  resources:
+  - constrainttemplates
+  verbs:
+  - create
+  - delete
+  - get
+  - list
+  - patch
+  - update
+  - watch
+- apiGroups:
+  - templates.gatekeeper.sh
+  resources:
+  - constrainttemplates/finalizers
+  verbs:
+  - delete
+  - get
+  - patch
+  - update
+- apiGroups:
+  - templates.gatekeeper.sh
+  resources:
+  - constrainttemplates/status
+  verbs:
+  - get
+  - patch
+  - update
+- apiGroups:
+  - admissionregistration.k8s.io
+  resourceNames:
+  - gatekeeper-validating-webhook-configuration
+  resources:
+  - validatingwebhookconfigurations
+  verbs:
+  - create
+  - delete
+  - get
+  - list
+  - patch
+  - update
+  - watch
+- apiGroups:
+  - admissionregistration.k8s.io
+  resourceNames:
+  - gatekeeper-mutating-webhook-configuration
+  resources:
+  - mutatingwebhookconfigurations
+  verbs:
+  - create
+  - delete
+  - get
+  - list
+  - patch
+  - update
+  - watch
+`)
+
+func configGatekeeperV330RbacAuthorizationK8sIo_v1_clusterrole_gatekeeperManagerRoleYamlBytes() ([]byte, error) {
+	return _configGatekeeperV330RbacAuthorizationK8sIo_v1_clusterrole_gatekeeperManagerRoleYaml, nil
+}
+
+func configGatekeeperV330RbacAuthorizationK8sIo_v1_clusterrole_gatekeeperManagerRoleYaml() (*asset, error) {
+	bytes, err := configGatekeeperV330RbacAuthorizationK8sIo_v1_clusterrole_gatekeeperManagerRoleYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/gatekeeper/v3.3.0/rbac.authorization.k8s.io_v1_clusterrole_gatekeeper-manager-role.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _configGatekeeperV330RbacAuthorizationK8sIo_v1_clusterrolebinding_gatekeeperManagerRolebindingYaml = []byte(`apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  labels:
+    gatekeeper.sh/system: "yes"
+  name: gatekeeper-manager-rolebinding
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: gatekeeper-manager-role
+subjects:
+- kind: ServiceAccount
+  name: gatekeeper-admin
+  namespace: gatekeeper-system
+`)
+
+func configGatekeeperV330RbacAuthorizationK8sIo_v1_clusterrolebinding_gatekeeperManagerRolebindingYamlBytes() ([]byte, error) {
+	return _configGatekeeperV330RbacAuthorizationK8sIo_v1_clusterrolebinding_gatekeeperManagerRolebindingYaml, nil
+}
+
+func configGatekeeperV330RbacAuthorizationK8sIo_v1_clusterrolebinding_gatekeeperManagerRolebindingYaml() (*asset, error) {
+	bytes, err := configGatekeeperV330RbacAuthorizationK8sIo_v1_clusterrolebinding_gatekeeperManagerRolebindingYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/gatekeeper/v3.3.0/rbac.authorization.k8s.io_v1_clusterrolebinding_gatekeeper-manager-rolebinding.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _configGatekeeperV330RbacAuthorizationK8sIo_v1_role_gatekeeperManagerRoleYaml = []byte(`apiVersion: rbac.authorization.k8s.io/v1
 kind: Role
 metadata:
-  creationTimestamp: null
+  creationTimestamp: null
+  labels:
+    gatekeeper.sh/system: "yes"
+  name: gatekeeper-manager-role
+  namespace: gatekeeper-system
+rules:
+- apiGroups:
+  - ""
+  resources:
+  - events
+  verbs:
+  - create
+  - patch
+- apiGroups:
+  - ""
+  resources:
+  - secrets
+  verbs:
+  - create
+  - delete
+  - get
+  - list
+  - patch
+  - update
+  - watch
+`)
+
+func configGatekeeperV330RbacAuthorizationK8sIo_v1_role_gatekeeperManagerRoleYamlBytes() ([]byte, error) {
+	return _configGatekeeperV330RbacAuthorizationK8sIo_v1_role_gatekeeperManagerRoleYaml, nil
+}
+
+func configGatekeeperV330RbacAuthorizationK8sIo_v1_role_gatekeeperManagerRoleYaml() (*asset, error) {
+	bytes, err := configGatekeeperV330RbacAuthorizationK8sIo_v1_role_gatekeeperManagerRoleYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/gatekeeper/v3.3.0/rbac.authorization.k8s.io_v1_role_gatekeeper-manager-role.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _configGatekeeperV330RbacAuthorizationK8sIo_v1_rolebinding_gatekeeperManagerRolebindingYaml = []byte(`apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  labels:
+    gatekeeper.sh/system: "yes"
+  name: gatekeeper-manager-rolebinding
+  namespace: gatekeeper-system
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: gatekeeper-manager-role
+subjects:
+- kind: ServiceAccount
+  name: gatekeeper-admin
+  namespace: gatekeeper-system
+`)
+
+func configGatekeeperV330RbacAuthorizationK8sIo_v1_rolebinding_gatekeeperManagerRolebindingYamlBytes() ([]byte, error) {
+	return _configGatekeeperV330RbacAuthorizationK8sIo_v1_rolebinding_gatekeeperManagerRolebindingYaml, nil
+}
+
+func configGatekeeperV330RbacAuthorizationK8sIo_v1_rolebinding_gatekeeperManagerRolebindingYaml() (*asset, error) {
+	bytes, err := configGatekeeperV330RbacAuthorizationK8sIo_v1_rolebinding_gatekeeperManagerRolebindingYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/gatekeeper/v3.3.0/rbac.authorization.k8s.io_v1_rolebinding_gatekeeper-manager-rolebinding.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _configGatekeeperV330V1_namespace_gatekeeperSystemYaml = []byte(`apiVersion: v1
+kind: Namespace
+metadata:
+  labels:
+    admission.gatekeeper.sh/ignore: no-self-managing
+    control-plane: controller-manager
+    gatekeeper.sh/system: "yes"
+  name: gatekeeper-system
+`)
+
+func configGatekeeperV330V1_namespace_gatekeeperSystemYamlBytes() ([]byte, error) {
+	return _configGatekeeperV330V1_namespace_gatekeeperSystemYaml, nil
+}
+
+func configGatekeeperV330V1_namespace_gatekeeperSystemYaml() (*asset, error) {
+	bytes, err := configGatekeeperV330V1_namespace_gatekeeperSystemYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/gatekeeper/v3.3.0/v1_namespace_gatekeeper-system.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _configGatekeeperV330V1_secret_gatekeeperWebhookServerCertYaml = []byte(`apiVersion: v1
+kind: Secret
+metadata:
+  labels:
+    gatekeeper.sh/system: "yes"
+  name: gatekeeper-webhook-server-cert
+  namespace: gatekeeper-system
+`)
+
+func configGatekeeperV330V1_secret_gatekeeperWebhookServerCertYamlBytes() ([]byte, error) {
+	return _configGatekeeperV330V1_secret_gatekeeperWebhookServerCertYaml, nil
+}
+
+func configGatekeeperV330V1_secret_gatekeeperWebhookServerCertYaml() (*asset, error) {
+	bytes, err := configGatekeeperV330V1_secret_gatekeeperWebhookServerCertYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/gatekeeper/v3.3.0/v1_secret_gatekeeper-webhook-server-cert.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _configGatekeeperV330V1_service_gatekeeperWebhookServiceYaml = []byte(`apiVersion: v1
+kind: Service
+metadata:
+  labels:
+    gatekeeper.sh/system: "yes"
+  name: gatekeeper-webhook-service
+  namespace: gatekeeper-system
+spec:
+  ports:
+  - port: 443
+    targetPort: 8443
+  selector:
+    control-plane: controller-manager
+    gatekeeper.sh/operation: webhook
+    gatekeeper.sh/system: "yes"
+`)
+
+func configGatekeeperV330V1_service_gatekeeperWebhookServiceYamlBytes() ([]byte, error) {
+	return _configGatekeeperV330V1_service_gatekeeperWebhookServiceYaml, nil
+}
+
+func configGatekeeperV330V1_service_gatekeeperWebhookServiceYaml() (*asset, error) {
+	bytes, err := configGatekeeperV330V1_service_gatekeeperWebhookServiceYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/gatekeeper/v3.3.0/v1_service_gatekeeper-webhook-service.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _configGatekeeperV330V1_serviceaccount_gatekeeperAdminYaml = []byte(`apiVersion: v1
+kind: ServiceAccount
+metadata:
+  labels:
+    gatekeeper.sh/system: "yes"
+  name: gatekeeper-admin
+  namespace: gatekeeper-system
+`)
+
+func configGatekeeperV330V1_serviceaccount_gatekeeperAdminYamlBytes() ([]byte, error) {
+	return _configGatekeeperV330V1_serviceaccount_gatekeeperAdminYaml, nil
+}
+
+func configGatekeeperV330V1_serviceaccount_gatekeeperAdminYaml() (*asset, error) {
+	bytes, err := configGatekeeperV330V1_serviceaccount_gatekeeperAdminYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/gatekeeper/v3.3.0/v1_serviceaccount_gatekeeper-admin.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _configGatekeeperV340AdmissionregistrationK8sIo_v1beta1_mutatingwebhookconfiguration_gatekeeperMutatingWebhookConfigurationYaml = []byte(`apiVersion: admissionregistration.k8s.io/v1beta1
+kind: MutatingWebhookConfiguration
+metadata:
+  creationTimestamp: null
+  name: gatekeeper-mutating-webhook-configuration
+webhooks:
+- clientConfig:
+    caBundle: Cg==
+    service:
+      name: gatekeeper-webhook-service
+      namespace: gatekeeper-system
+      path: /v1/mutate
+  failurePolicy: Ignore
+  name: mutation.gatekeeper.sh
+  rules:
+  - apiGroups:
+    - '*'
+    apiVersions:
+    - '*'
+    operations:
+    - CREATE
+    - UPDATE
+    resources:
+    - '*'
+`)
+
+func configGatekeeperV340AdmissionregistrationK8sIo_v1beta1_mutatingwebhookconfiguration_gatekeeperMutatingWebhookConfigurationYamlBytes() ([]byte, error) {
+	return _configGatekeeperV340AdmissionregistrationK8sIo_v1beta1_mutatingwebhookconfiguration_gatekeeperMutatingWebhookConfigurationYaml, nil
+}
+
+func configGatekeeperV340AdmissionregistrationK8sIo_v1beta1_mutatingwebhookconfiguration_gatekeeperMutatingWebhookConfigurationYaml() (*asset, error) {
+	bytes, err := configGatekeeperV340AdmissionregistrationK8sIo_v1beta1_mutatingwebhookconfiguration_gatekeeperMutatingWebhookConfigurationYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/gatekeeper/v3.4.0/admissionregistration.k8s.io_v1beta1_mutatingwebhookconfiguration_gatekeeper-mutating-webhook-configuration.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _configGatekeeperV340AdmissionregistrationK8sIo_v1beta1_validatingwebhookconfiguration_gatekeeperValidatingWebhookConfigurationYaml = []byte(`apiVersion: admissionregistration.k8s.io/v1beta1
+kind: ValidatingWebhookConfiguration
+metadata:
+  labels:
+    gatekeeper.sh/system: "yes"
+  name: gatekeeper-validating-webhook-configuration
+webhooks:
+- clientConfig:
+    caBundle: Cg==
+    service:
+      name: gatekeeper-webhook-service
+      namespace: gatekeeper-system
+      path: /v1/admit
+  failurePolicy: Ignore
+  name: validation.gatekeeper.sh
+  namespaceSelector:
+    matchExpressions:
+    - key: admission.gatekeeper.sh/ignore
+      operator: DoesNotExist
+  rules:
+  - apiGroups:
+    - '*'
+    apiVersions:
+    - '*'
+    operations:
+    - CREATE
+    - UPDATE
+    resources:
+    - '*'
+  sideEffects: None
+  timeoutSeconds: 3
+- clientConfig:
+    caBundle: Cg==
+    service:
+      name: gatekeeper-webhook-service
+      namespace: gatekeeper-system
+      path: /v1/admitlabel
+  failurePolicy: Fail
+  name: check-ignore-label.gatekeeper.sh
+  rules:
+  - apiGroups:
+    - ""
+    apiVersions:
+    - '*'
+    operations:
+    - CREATE
+    - UPDATE
+    resources:
+    - namespaces
+  sideEffects: None
+  timeoutSeconds: 3
+`)
+
+func configGatekeeperV340AdmissionregistrationK8sIo_v1beta1_validatingwebhookconfiguration_gatekeeperValidatingWebhookConfigurationYamlBytes() ([]byte, error) {
+	return _configGatekeeperV340AdmissionregistrationK8sIo_v1beta1_validatingwebhookconfiguration_gatekeeperValidatingWebhookConfigurationYaml, nil
+}
+
+func configGatekeeperV340AdmissionregistrationK8sIo_v1beta1_validatingwebhookconfiguration_gatekeeperValidatingWebhookConfigurationYaml() (*asset, error) {
+	bytes, err := configGatekeeperV340AdmissionregistrationK8sIo_v1beta1_validatingwebhookconfiguration_gatekeeperValidatingWebhookConfigurationYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/gatekeeper/v3.4.0/admissionregistration.k8s.io_v1beta1_validatingwebhookconfiguration_gatekeeper-validating-webhook-configuration.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_assignMutationsGatekeeperShYaml = []byte(`apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  annotations:
+    controller-gen.kubebuilder.io/version: v0.3.0
+  creationTimestamp: null
+  labels:
+    gatekeeper.sh/system: "yes"
+  name: assign.mutations.gatekeeper.sh
+spec:
+  group: mutations.gatekeeper.sh
+  names:
+    kind: Assign
+    listKind: AssignList
+    plural: assign
+    singular: assign
+  scope: Cluster
+  subresources:
+    status: {}
+  validation:
+    openAPIV3Schema:
+      description: Assign is the Schema for the assign API
+      properties:
+        apiVersion:
+          description: 'APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources'
+          type: string
+        kind:
+          description: 'Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds'
+          type: string
+        metadata:
+          type: object
+        spec:
+          description: AssignSpec defines the desired state of Assign
+          properties:
+            applyTo:
+              description: 'INSERT ADDITIONAL SPEC FIELDS - desired state of cluster Important: Run "make" to regenerate code after modifying this file'
+              items:
+                description: ApplyTo determines what GVKs items the mutation should apply to. Globs are not allowed.
+                properties:
+                  groups:
+                    items:
+                      type: string
+                    type: array
+                  kinds:
+                    items:
+                      type: string
+                    type: array
+                  versions:
+                    items:
+                      type: string
+                    type: array
+                type: object
+              type: array
+            location:
+              type: string
+            match:
+              properties:
+                excludedNamespaces:
+                  items:
+                    type: string
+                  type: array
+                kinds:
+                  items:
+                    description: Kinds accepts a list of objects with apiGroups and kinds fields that list the groups/kinds of objects to which the mutation will apply. If multiple groups/kinds objects are specified, only one match is needed for the resource to be in scope.
+                    properties:
+                      apiGroups:
+                        description: APIGroups is the API groups the resources belong to. '*' is all groups. If '*' is present, the length of the slice must be one. Required.
+                        items:
+                          type: string
+                        type: array
+                      kinds:
+                        items:
+                          type: string
+                        type: array
+                    type: object
+                  type: array
+                labelSelector:
+                  description: A label selector is a label query over a set of resources. The result of matchLabels and matchExpressions are ANDed. An empty label selector matches all objects. A null label selector matches no objects.
+                  properties:
+                    matchExpressions:
+                      description: matchExpressions is a list of label selector requirements. The requirements are ANDed.
+                      items:
+                        description: A label selector requirement is a selector that contains values, a key, and an operator that relates the key and values.
+                        properties:
+                          key:
+                            description: key is the label key that the selector applies to.
+                            type: string
+                          operator:
+                            description: operator represents a key's relationship to a set of values. Valid operators are In, NotIn, Exists and DoesNotExist.
+                            type: string
+                          values:
+                            description: values is an array of string values. If the operator is In or NotIn, the values array must be non-empty. If the operator is Exists or DoesNotExist, the values array must be empty. This array is replaced during a strategic merge patch.
+                            items:
+                              type: string
+                            type: array
+                        required:
+                        - key
+                        - operator
+                        type: object
+                      type: array
+                    matchLabels:
+                      additionalProperties:
+                        type: string
+                      description: matchLabels is a map of {key,value} pairs. A single {key,value} in the matchLabels map is equivalent to an element of matchExpressions, whose key field is "key", the operator is "In", and the values array contains only "value". The requirements are ANDed.
+                      type: object
+                  type: object
+                namespaceSelector:
+                  description: A label selector is a label query over a set of resources. The result of matchLabels and matchExpressions are ANDed. An empty label selector matches all objects. A null label selector matches no objects.
+                  properties:
+                    matchExpressions:
+                      description: matchExpressions is a list of label selector requirements. The requirements are ANDed.
+                      items:
+                        description: A label selector requirement is a selector that contains values, a key, and an operator that relates the key and values.
+                        properties:
+                          key:
+                            description: key is the label key that the selector applies to.
+                            type: string
+                          operator:
+                            description: operator represents a key's relationship to a set of values. Valid operators are In, NotIn, Exists and DoesNotExist.
+                            type: string
+                          values:
+                            description: values is an array of string values. If the operator is In or NotIn, the values array must be non-empty. If the operator is Exists or DoesNotExist, the values array must be empty. This array is replaced during a strategic merge patch.
+                            items:
+                              type: string
+                            type: array
+                        required:
+                        - key
+                        - operator
+                        type: object
+                      type: array
+                    matchLabels:
+                      additionalProperties:
+                        type: string
+                      description: matchLabels is a map of {key,value} pairs. A single {key,value} in the matchLabels map is equivalent to an element of matchExpressions, whose key field is "key", the operator is "In", and the values array contains only "value". The requirements are ANDed.
+                      type: object
+                  type: object
+                namespaces:
+                  items:
+                    type: string
+                  type: array
+                scope:
+                  description: ResourceScope is an enum defining the different scopes available to a custom resource
+                  type: string
+              required:
+              - scope
+              type: object
+            parameters:
+              properties:
+                assign:
+                  description: Assign.value holds the value to be assigned
+                  type: object
+                  x-kubernetes-preserve-unknown-fields: true
+                ifIn:
+                  description: IfIn Only mutate if the current value is in the supplied list
+                  items:
+                    type: string
+                  type: array
+                ifNotIn:
+                  description: IfNotIn Only mutate if the current value is NOT in the supplied list
+                  items:
+                    type: string
+                  type: array
+                pathTests:
+                  items:
+                    description: "PathTests allows the user to customize how the mutation works if parent paths are missing. It traverses the list in order. All sub paths are tested against the provided condition, if the test fails, the mutation is not applied. All ` + "`" + `subPath` + "`" + ` entries must be a prefix of ` + "`" + `location` + "`" + `. Any glob characters will take on the same value as was used to expand the matching glob in ` + "`" + `location` + "`" + `. \n Available Tests: * MustExist    - the path must exist or do not mutate * MustNotExist - the path must not exist or do not mutate"
+                    properties:
+                      condition:
+                        enum:
+                        - MustExist
+                        - MustNotExist
+                        type: string
+                      subPath:
+                        type: string
+                    type: object
+                  type: array
+              type: object
+          type: object
+        status:
+          description: AssignStatus defines the observed state of Assign
+          type: object
+      type: object
+  version: v1alpha1
+  versions:
+  - name: v1alpha1
+    served: true
+    storage: true
+status:
+  acceptedNames:
+    kind: ""
+    plural: ""
+  conditions: []
+  storedVersions: []
+`)
+
+func configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_assignMutationsGatekeeperShYamlBytes() ([]byte, error) {
+	return _configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_assignMutationsGatekeeperShYaml, nil
+}
+
+func configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_assignMutationsGatekeeperShYaml() (*asset, error) {
+	bytes, err := configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_assignMutationsGatekeeperShYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/gatekeeper/v3.4.0/apiextensions.k8s.io_v1beta1_customresourcedefinition_assign.mutations.gatekeeper.sh.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_assignmetadataMutationsGatekeeperShYaml = []byte(`apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  annotations:
+    controller-gen.kubebuilder.io/version: v0.3.0
+  creationTimestamp: null
+  labels:
+    gatekeeper.sh/system: "yes"
+  name: assignmetadata.mutations.gatekeeper.sh
+spec:
+  group: mutations.gatekeeper.sh
+  names:
+    kind: AssignMetadata
+    listKind: AssignMetadataList
+    plural: assignmetadata
+    singular: assignmetadata
+  scope: Cluster
+  subresources:
+    status: {}
+  validation:
+    openAPIV3Schema:
+      description: AssignMetadata is the Schema for the assignmetadata API
+      properties:
+        apiVersion:
+          description: 'APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources'
+          type: string
+        kind:
+          description: 'Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds'
+          type: string
+        metadata:
+          type: object
+        spec:
+          description: AssignMetadataSpec defines the desired state of AssignMetadata
+          properties:
+            location:
+              type: string
+            match:
+              properties:
+                excludedNamespaces:
+                  items:
+                    type: string
+                  type: array
+                kinds:
+                  items:
+                    description: Kinds accepts a list of objects with apiGroups and kinds fields that list the groups/kinds of objects to which the mutation will apply. If multiple groups/kinds objects are specified, only one match is needed for the resource to be in scope.
+                    properties:
+                      apiGroups:
+                        description: APIGroups is the API groups the resources belong to. '*' is all groups. If '*' is present, the length of the slice must be one. Required.
+                        items:
+                          type: string
+                        type: array
+                      kinds:
+                        items:
+                          type: string
+                        type: array
+                    type: object
+                  type: array
+                labelSelector:
+                  description: A label selector is a label query over a set of resources. The result of matchLabels and matchExpressions are ANDed. An empty label selector matches all objects. A null label selector matches no objects.
+                  properties:
+                    matchExpressions:
+                      description: matchExpressions is a list of label selector requirements. The requirements are ANDed.
+                      items:
+                        description: A label selector requirement is a selector that contains values, a key, and an operator that relates the key and values.
+                        properties:
+                          key:
+                            description: key is the label key that the selector applies to.
+                            type: string
+                          operator:
+                            description: operator represents a key's relationship to a set of values. Valid operators are In, NotIn, Exists and DoesNotExist.
+                            type: string
+                          values:
+                            description: values is an array of string values. If the operator is In or NotIn, the values array must be non-empty. If the operator is Exists or DoesNotExist, the values array must be empty. This array is replaced during a strategic merge patch.
+                            items:
+                              type: string
+                            type: array
+                        required:
+                        - key
+                        - operator
+                        type: object
+                      type: array
+                    matchLabels:
+                      additionalProperties:
+                        type: string
+                      description: matchLabels is a map of {key,value} pairs. A single {key,value} in the matchLabels map is equivalent to an element of matchExpressions, whose key field is "key", the operator is "In", and the values array contains only "value". The requirements are ANDed.
+                      type: object
+                  type: object
+                namespaceSelector:
+                  description: A label selector is a label query over a set of resources. The result of matchLabels and matchExpressions are ANDed. An empty label selector matches all objects. A null label selector matches no objects.
+                  properties:
+                    matchExpressions:
+                      description: matchExpressions is a list of label selector requirements. The requirements are ANDed.
+                      items:
+                        description: A label selector requirement is a selector that contains values, a key, and an operator that relates the key and values.
+                        properties:
+                          key:
+                            description: key is the label key that the selector applies to.
+                            type: string
+                          operator:
+                            description: operator represents a key's relationship to a set of values. Valid operators are In, NotIn, Exists and DoesNotExist.
+                            type: string
+                          values:
+                            description: values is an array of string values. If the operator is In or NotIn, the values array must be non-empty. If the operator is Exists or DoesNotExist, the values array must be empty. This array is replaced during a strategic merge patch.
+                            items:
+                              type: string
+                            type: array
+                        required:
+                        - key
+                        - operator
+                        type: object
+                      type: array
+                    matchLabels:
+                      additionalProperties:
+                        type: string
+                      description: matchLabels is a map of {key,value} pairs. A single {key,value} in the matchLabels map is equivalent to an element of matchExpressions, whose key field is "key", the operator is "In", and the values array contains only "value". The requirements are ANDed.
+                      type: object
+                  type: object
+                namespaces:
+                  items:
+                    type: string
+                  type: array
+                scope:
+                  description: ResourceScope is an enum defining the different scopes available to a custom resource
+                  type: string
+              required:
+              - scope
+              type: object
+            parameters:
+              properties:
+                assign:
+                  description: Assign.value holds the value to be assigned
+                  type: object
+                  x-kubernetes-preserve-unknown-fields: true
+              type: object
+          type: object
+        status:
+          description: AssignMetadataStatus defines the observed state of AssignMetadata
+          type: object
+      type: object
+  version: v1alpha1
+  versions:
+  - name: v1alpha1
+    served: true
+    storage: true
+status:
+  acceptedNames:
+    kind: ""
+    plural: ""
+  conditions: []
+  storedVersions: []
+`)
+
+func configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_assignmetadataMutationsGatekeeperShYamlBytes() ([]byte, error) {
+	return _configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_assignmetadataMutationsGatekeeperShYaml, nil
+}
+
+func configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_assignmetadataMutationsGatekeeperShYaml() (*asset, error) {
+	bytes, err := configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_assignmetadataMutationsGatekeeperShYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/gatekeeper/v3.4.0/apiextensions.k8s.io_v1beta1_customresourcedefinition_assignmetadata.mutations.gatekeeper.sh.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_configsConfigGatekeeperShYaml = []byte(`apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  annotations:
+    controller-gen.kubebuilder.io/version: v0.3.0
+  creationTimestamp: null
+  labels:
+    gatekeeper.sh/system: "yes"
+  name: configs.config.gatekeeper.sh
+spec:
+  group: config.gatekeeper.sh
+  names:
+    kind: Config
+    listKind: ConfigList
+    plural: configs
+    singular: config
+  scope: Namespaced
+  validation:
+    openAPIV3Schema:
+      description: Config is the Schema for the configs API
+      properties:
+        apiVersion:
+          description: 'APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources'
+          type: string
+        kind:
+          description: 'Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds'
+          type: string
+        metadata:
+          type: object
+        spec:
+          description: ConfigSpec defines the desired state of Config
+          properties:
+            match:
+              description: Configuration for namespace exclusion
+              items:
+                properties:
+                  excludedNamespaces:
+                    items:
+                      type: string
+                    type: array
+                  processes:
+                    items:
+                      type: string
+                    type: array
+                type: object
+              type: array
+            readiness:
+              description: Configuration for readiness tracker
+              properties:
+                statsEnabled:
+                  type: boolean
+              type: object
+            sync:
+              description: Configuration for syncing k8s objects
+              properties:
+                syncOnly:
+                  description: If non-empty, only entries on this list will be replicated into OPA
+                  items:
+                    properties:
+                      group:
+                        type: string
+                      kind:
+                        type: string
+                      version:
+                        type: string
+                    type: object
+                  type: array
+              type: object
+            validation:
+              description: Configuration for validation
+              properties:
+                traces:
+                  description: List of requests to trace. Both "user" and "kinds" must be specified
+                  items:
+                    properties:
+                      dump:
+                        description: Also dump the state of OPA with the trace. Set to ` + "`" + `All` + "`" + ` to dump everything.
+                        type: string
+                      kind:
+                        description: Only trace requests of the following GroupVersionKind
+                        properties:
+                          group:
+                            type: string
+                          kind:
+                            type: string
+                          version:
+                            type: string
+                        type: object
+                      user:
+                        description: Only trace requests from the specified user
+                        type: string
+                    type: object
+                  type: array
+              type: object
+          type: object
+        status:
+          description: ConfigStatus defines the observed state of Config
+          type: object
+      type: object
+  version: v1alpha1
+  versions:
+  - name: v1alpha1
+    served: true
+    storage: true
+status:
+  acceptedNames:
+    kind: ""
+    plural: ""
+  conditions: []
+  storedVersions: []
+`)
+
+func configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_configsConfigGatekeeperShYamlBytes() ([]byte, error) {
+	return _configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_configsConfigGatekeeperShYaml, nil
+}
+
+func configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_configsConfigGatekeeperShYaml() (*asset, error) {
+	bytes, err := configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_configsConfigGatekeeperShYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/gatekeeper/v3.4.0/apiextensions.k8s.io_v1beta1_customresourcedefinition_configs.config.gatekeeper.sh.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_constraintpodstatusesStatusGatekeeperShYaml = []byte(`apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  annotations:
+    controller-gen.kubebuilder.io/version: v0.3.0
+  creationTimestamp: null
+  labels:
+    gatekeeper.sh/system: "yes"
+  name: constraintpodstatuses.status.gatekeeper.sh
+spec:
+  group: status.gatekeeper.sh
+  names:
+    kind: ConstraintPodStatus
+    listKind: ConstraintPodStatusList
+    plural: constraintpodstatuses
+    singular: constraintpodstatus
+  scope: Namespaced
+  validation:
+    openAPIV3Schema:
+      description: ConstraintPodStatus is the Schema for the constraintpodstatuses API
+      properties:
+        apiVersion:
+          description: 'APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources'
+          type: string
+        kind:
+          description: 'Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds'
+          type: string
+        metadata:
+          type: object
+        status:
+          description: ConstraintPodStatusStatus defines the observed state of ConstraintPodStatus
+          properties:
+            constraintUID:
+              description: Storing the constraint UID allows us to detect drift, such as when a constraint has been recreated after its CRD was deleted out from under it, interrupting the watch
+              type: string
+            enforced:
+              type: boolean
+            errors:
+              items:
+                description: Error represents a single error caught while adding a constraint to OPA
+                properties:
+                  code:
+                    type: string
+                  location:
+                    type: string
+                  message:
+                    type: string
+                required:
+                - code
+                - message
+                type: object
+              type: array
+            id:
+              type: string
+            observedGeneration:
+              format: int64
+              type: integer
+            operations:
+              items:
+                type: string
+              type: array
+          type: object
+      type: object
+  version: v1beta1
+  versions:
+  - name: v1beta1
+    served: true
+    storage: true
+status:
+  acceptedNames:
+    kind: ""
+    plural: ""
+  conditions: []
+  storedVersions: []
+`)
+
+func configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_constraintpodstatusesStatusGatekeeperShYamlBytes() ([]byte, error) {
+	return _configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_constraintpodstatusesStatusGatekeeperShYaml, nil
+}
+
+func configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_constraintpodstatusesStatusGatekeeperShYaml() (*asset, error) {
+	bytes, err := configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_constraintpodstatusesStatusGatekeeperShYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/gatekeeper/v3.4.0/apiextensions.k8s.io_v1beta1_customresourcedefinition_constraintpodstatuses.status.gatekeeper.sh.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatepodstatusesStatusGatekeeperShYaml = []byte(`apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  annotations:
+    controller-gen.kubebuilder.io/version: v0.3.0
+  creationTimestamp: null
+  labels:
+    gatekeeper.sh/system: "yes"
+  name: constrainttemplatepodstatuses.status.gatekeeper.sh
+spec:
+  group: status.gatekeeper.sh
+  names:
+    kind: ConstraintTemplatePodStatus
+    listKind: ConstraintTemplatePodStatusList
+    plural: constrainttemplatepodstatuses
+    singular: constrainttemplatepodstatus
+  scope: Namespaced
+  validation:
+    openAPIV3Schema:
+      description: ConstraintTemplatePodStatus is the Schema for the constrainttemplatepodstatuses API
+      properties:
+        apiVersion:
+          description: 'APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources'
+          type: string
+        kind:
+          description: 'Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds'
+          type: string
+        metadata:
+          type: object
+        status:
+          description: ConstraintTemplatePodStatusStatus defines the observed state of ConstraintTemplatePodStatus
+          properties:
+            errors:
+              items:
+                description: CreateCRDError represents a single error caught during parsing, compiling, etc.
+                properties:
+                  code:
+                    type: string
+                  location:
+                    type: string
+                  message:
+                    type: string
+                required:
+                - code
+                - message
+                type: object
+              type: array
+            id:
+              description: 'Important: Run "make" to regenerate code after modifying this file'
+              type: string
+            observedGeneration:
+              format: int64
+              type: integer
+            operations:
+              items:
+                type: string
+              type: array
+            templateUID:
+              description: UID is a type that holds unique ID values, including UUIDs.  Because we don't ONLY use UUIDs, this is an alias to string.  Being a type captures intent and helps make sure that UIDs and names do not get conflated.
+              type: string
+          type: object
+      type: object
+  version: v1beta1
+  versions:
+  - name: v1beta1
+    served: true
+    storage: true
+status:
+  acceptedNames:
+    kind: ""
+    plural: ""
+  conditions: []
+  storedVersions: []
+`)
+
+func configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatepodstatusesStatusGatekeeperShYamlBytes() ([]byte, error) {
+	return _configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatepodstatusesStatusGatekeeperShYaml, nil
+}
+
+func configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatepodstatusesStatusGatekeeperShYaml() (*asset, error) {
+	bytes, err := configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatepodstatusesStatusGatekeeperShYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/gatekeeper/v3.4.0/apiextensions.k8s.io_v1beta1_customresourcedefinition_constrainttemplatepodstatuses.status.gatekeeper.sh.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatesTemplatesGatekeeperShYaml = []byte(`apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  creationTimestamp: null
+  labels:
+    controller-tools.k8s.io: "1.0"
+    gatekeeper.sh/system: "yes"
+  name: constrainttemplates.templates.gatekeeper.sh
+spec:
+  group: templates.gatekeeper.sh
+  names:
+    kind: ConstraintTemplate
+    plural: constrainttemplates
+  scope: Cluster
+  subresources:
+    status: {}
+  validation:
+    openAPIV3Schema:
+      properties:
+        apiVersion:
+          description: 'APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources'
+          type: string
+        kind:
+          description: 'Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds'
+          type: string
+        metadata:
+          type: object
+        spec:
+          properties:
+            crd:
+              properties:
+                spec:
+                  properties:
+                    names:
+                      properties:
+                        kind:
+                          type: string
+                        shortNames:
+                          items:
+                            type: string
+                          type: array
+                      type: object
+                    validation:
+                      type: object
+                  type: object
+              type: object
+            targets:
+              items:
+                properties:
+                  libs:
+                    items:
+                      type: string
+                    type: array
+                  rego:
+                    type: string
+                  target:
+                    type: string
+                type: object
+              type: array
+          type: object
+        status:
+          properties:
+            byPod:
+              items:
+                properties:
+                  errors:
+                    items:
+                      properties:
+                        code:
+                          type: string
+                        location:
+                          type: string
+                        message:
+                          type: string
+                      required:
+                      - code
+                      - message
+                      type: object
+                    type: array
+                  id:
+                    description: a unique identifier for the pod that wrote the status
+                    type: string
+                  observedGeneration:
+                    format: int64
+                    type: integer
+                type: object
+              type: array
+            created:
+              type: boolean
+          type: object
+  version: v1beta1
+  versions:
+  - name: v1beta1
+    served: true
+    storage: true
+  - name: v1alpha1
+    served: true
+    storage: false
+status:
+  acceptedNames:
+    kind: ""
+    plural: ""
+  conditions: []
+  storedVersions: []
+`)
+
+func configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatesTemplatesGatekeeperShYamlBytes() ([]byte, error) {
+	return _configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatesTemplatesGatekeeperShYaml, nil
+}
+
+func configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatesTemplatesGatekeeperShYaml() (*asset, error) {
+	bytes, err := configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatesTemplatesGatekeeperShYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/gatekeeper/v3.4.0/apiextensions.k8s.io_v1beta1_customresourcedefinition_constrainttemplates.templates.gatekeeper.sh.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _configGatekeeperV340Apps_v1_deployment_gatekeeperAuditYaml = []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    control-plane: audit-controller
+    gatekeeper.sh/operation: audit
+    gatekeeper.sh/system: "yes"
+  name: gatekeeper-audit
+  namespace: gatekeeper-system
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      control-plane: audit-controller
+      gatekeeper.sh/operation: audit
+      gatekeeper.sh/system: "yes"
+  template:
+    metadata:
+      annotations:
+        container.seccomp.security.alpha.kubernetes.io/manager: runtime/default
+      labels:
+        control-plane: audit-controller
+        gatekeeper.sh/operation: audit
+        gatekeeper.sh/system: "yes"
+    spec:
+      automountServiceAccountToken: true
+      containers:
+      - args:
+        - --operation=audit
+        - --operation=status
+        - --logtostderr
+        command:
+        - /manager
+        env:
+        - name: POD_NAMESPACE
+          valueFrom:
+            fieldRef:
+              apiVersion: v1
+              fieldPath: metadata.namespace
+        - name: POD_NAME
+          valueFrom:
+            fieldRef:
+              fieldPath: metadata.name
+        image: openpolicyagent/gatekeeper:v3.4.0
+        imagePullPolicy: Always
+        livenessProbe:
+          httpGet:
+            path: /healthz
+            port: 9090
+        name: manager
+        ports:
+        - containerPort: 8888
+          name: metrics
+          protocol: TCP
+        - containerPort: 9090
+          name: healthz
+          protocol: TCP
+        readinessProbe:
+          httpGet:
+            path: /readyz
+            port: 9090
+        resources:
+          limits:
+            cpu: 1000m
+            memory: 512Mi
+          requests:
+            cpu: 100m
+            memory: 256Mi
+        securityContext:
+          allowPrivilegeEscalation: false
+          capabilities:
+            drop:
+            - all
+          readOnlyRootFilesystem: true
+          runAsGroup: 999
+          runAsNonRoot: true
+          runAsUser: 1000
+      nodeSelector:
+        kubernetes.io/os: linux
+      serviceAccountName: gatekeeper-admin
+      terminationGracePeriodSeconds: 60
+`)
+
+func configGatekeeperV340Apps_v1_deployment_gatekeeperAuditYamlBytes() ([]byte, error) {
+	return _configGatekeeperV340Apps_v1_deployment_gatekeeperAuditYaml, nil
+}
+
+func configGatekeeperV340Apps_v1_deployment_gatekeeperAuditYaml() (*asset, error) {
+	bytes, err := configGatekeeperV340Apps_v1_deployment_gatekeeperAuditYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/gatekeeper/v3.4.0/apps_v1_deployment_gatekeeper-audit.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _configGatekeeperV340Apps_v1_deployment_gatekeeperControllerManagerYaml = []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
   labels:
+    control-plane: controller-manager
+    gatekeeper.sh/operation: webhook
     gatekeeper.sh/system: "yes"
-  name: gatekeeper-manager-role
+  name: gatekeeper-controller-manager
   namespace: gatekeeper-system
-rules:
-- apiGroups:
-  - ""
-  resources:
-  - events
-  verbs:
-  - create
-  - patch
-- apiGroups:
-  - ""
-  resources:
-  - secrets
-  verbs:
-  - create
-  - delete
-  - get
-  - list
-  - patch
-  - update
-  - watch
-- apiGroups:
-  - security.openshift.io
-  resourceNames:
-    - anyuid
-  resources:
-    - securitycontextconstraints
-  verbs:
-    - use
+spec:
+  replicas: 3
+  selector:
+    matchLabels:
+      control-plane: controller-manager
+      gatekeeper.sh/operation: webhook
+      gatekeeper.sh/system: "yes"
+  template:
+    metadata:
+      annotations:
+        container.seccomp.security.alpha.kubernetes.io/manager: runtime/default
+      labels:
+        control-plane: controller-manager
+        gatekeeper.sh/operation: webhook
+        gatekeeper.sh/system: "yes"
+    spec:
+      affinity:
+        podAntiAffinity:
+          preferredDuringSchedulingIgnoredDuringExecution:
+          - podAffinityTerm:
+              labelSelector:
+                matchExpressions:
+                - key: gatekeeper.sh/operation
+                  operator: In
+                  values:
+                  - webhook
+              topologyKey: kubernetes.io/hostname
+            weight: 100
+      automountServiceAccountToken: true
+      containers:
+      - args:
+        - --port=8443
+        - --logtostderr
+        - --exempt-namespace=gatekeeper-system
+        - --operation=webhook
+        - --disable-opa-builtin={http.send}
+        command:
+        - /manager
+        env:
+        - name: POD_NAMESPACE
+          valueFrom:
+            fieldRef:
+              apiVersion: v1
+              fieldPath: metadata.namespace
+        - name: POD_NAME
+          valueFrom:
+            fieldRef:
+              fieldPath: metadata.name
+        image: openpolicyagent/gatekeeper:v3.4.0
+        imagePullPolicy: Always
+        livenessProbe:
+          httpGet:
+            path: /healthz
+            port: 9090
+        name: manager
+        ports:
+        - containerPort: 8443
+          name: webhook-server
+          protocol: TCP
+        - containerPort: 8888
+          name: metrics
+          protocol: TCP
+        - containerPort: 9090
+          name: healthz
+          protocol: TCP
+        readinessProbe:
+          httpGet:
+            path: /readyz
+            port: 9090
+        resources:
+          limits:
+            cpu: 1000m
+            memory: 512Mi
+          requests:
+            cpu: 100m
+            memory: 256Mi
+        securityContext:
+          allowPrivilegeEscalation: false
+          capabilities:
+            drop:
+            - all
+          readOnlyRootFilesystem: true
+          runAsGroup: 999
+          runAsNonRoot: true
+          runAsUser: 1000
+        volumeMounts:
+        - mountPath: /certs
+          name: cert
+          readOnly: true
+      nodeSelector:
+        kubernetes.io/os: linux
+      serviceAccountName: gatekeeper-admin
+      terminationGracePeriodSeconds: 60
+      volumes:
+      - name: cert
+        secret:
+          defaultMode: 420
+          secretName: gatekeeper-webhook-server-cert
 `)
 
-func configGatekeeperOpenshiftRbacAuthorizationK8sIo_v1_role_gatekeeperManagerRoleYamlBytes() ([]byte, error) {
-	return _configGatekeeperOpenshiftRbacAuthorizationK8sIo_v1_role_gatekeeperManagerRoleYaml, nil
+func configGatekeeperV340Apps_v1_deployment_gatekeeperControllerManagerYamlBytes() ([]byte, error) {
+	return _configGatekeeperV340Apps_v1_deployment_gatekeeperControllerManagerYaml, nil
 }
 
-func configGatekeeperOpenshiftRbacAuthorizationK8sIo_v1_role_gatekeeperManagerRoleYaml() (*asset, error) {
-	bytes, err := configGatekeeperOpenshiftRbacAuthorizationK8sIo_v1_role_gatekeeperManagerRoleYamlBytes()
+func configGatekeeperV340Apps_v1_deployment_gatekeeperControllerManagerYaml() (*asset, error) {
+	bytes, err := configGatekeeperV340Apps_v1_deployment_gatekeeperControllerManagerYamlBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "config/gatekeeper/openshift/rbac.authorization.k8s.io_v1_role_gatekeeper-manager-role.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	info := bindataFileInfo{name: "config/gatekeeper/v3.4.0/apps_v1_deployment_gatekeeper-controller-manager.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _configGatekeeperPolicy_v1beta1_podsecuritypolicy_gatekeeperAdminYaml = []byte(`apiVersion: policy/v1beta1
+var _configGatekeeperV340Policy_v1beta1_podsecuritypolicy_gatekeeperAdminYaml = []byte(`apiVersion: policy/v1beta1
 kind: PodSecurityPolicy
 metadata:
   annotations:
@@ -1290,22 +2823,22 @@ spec:
   - downwardAPI
 `)
 
-func configGatekeeperPolicy_v1beta1_podsecuritypolicy_gatekeeperAdminYamlBytes() ([]byte, error) {
-	return _configGatekeeperPolicy_v1beta1_podsecuritypolicy_gatekeeperAdminYaml, nil
+func configGatekeeperV340Policy_v1beta1_podsecuritypolicy_gatekeeperAdminYamlBytes() ([]byte, error) {
+	return _configGatekeeperV340Policy_v1beta1_podsecuritypolicy_gatekeeperAdminYaml, nil
 }
 
-func configGatekeeperPolicy_v1beta1_podsecuritypolicy_gatekeeperAdminYaml() (*asset, error) {
-	bytes, err := configGatekeeperPolicy_v1beta1_podsecuritypolicy_gatekeeperAdminYamlBytes()
+func configGatekeeperV340Policy_v1beta1_podsecuritypolicy_gatekeeperAdminYaml() (*asset, error) {
+	bytes, err := configGatekeeperV340Policy_v1beta1_podsecuritypolicy_gatekeeperAdminYamlBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "config/gatekeeper/policy_v1beta1_podsecuritypolicy_gatekeeper-admin.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	info := bindataFileInfo{name: "config/gatekeeper/v3.4.0/policy_v1beta1_podsecuritypolicy_gatekeeper-admin.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _configGatekeeperRbacAuthorizationK8sIo_v1_clusterrole_gatekeeperManagerRoleYaml = []byte(`apiVersion: rbac.authorization.k8s.io/v1
+var _configGatekeeperV340RbacAuthorizationK8sIo_v1_clusterrole_gatekeeperManagerRoleYaml = []byte(`apiVersion: rbac.authorization.k8s.io/v1
 kind: ClusterRole
 metadata:
   creationTimestamp: null
@@ -1456,22 +2989,22 @@ rules:
   - watch
 `)
 
-func configGatekeeperRbacAuthorizationK8sIo_v1_clusterrole_gatekeeperManagerRoleYamlBytes() ([]byte, error) {
-	return _configGatekeeperRbacAuthorizationK8sIo_v1_clusterrole_gatekeeperManagerRoleYaml, nil
+func configGatekeeperV340RbacAuthorizationK8sIo_v1_clusterrole_gatekeeperManagerRoleYamlBytes() ([]byte, error) {
+	return _configGatekeeperV340RbacAuthorizationK8sIo_v1_clusterrole_gatekeeperManagerRoleYaml, nil
 }
 
-func configGatekeeperRbacAuthorizationK8sIo_v1_clusterrole_gatekeeperManagerRoleYaml() (*asset, error) {
-	bytes, err := configGatekeeperRbacAuthorizationK8sIo_v1_clusterrole_gatekeeperManagerRoleYamlBytes()
+func configGatekeeperV340RbacAuthorizationK8sIo_v1_clusterrole_gatekeeperManagerRoleYaml() (*asset, error) {
+	bytes, err := configGatekeeperV340RbacAuthorizationK8sIo_v1_clusterrole_gatekeeperManagerRoleYamlBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "config/gatekeeper/rbac.authorization.k8s.io_v1_clusterrole_gatekeeper-manager-role.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	info := bindataFileInfo{name: "config/gatekeeper/v3.4.0/rbac.authorization.k8s.io_v1_clusterrole_gatekeeper-manager-role.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _configGatekeeperRbacAuthorizationK8sIo_v1_clusterrolebinding_gatekeeperManagerRolebindingYaml = []byte(`apiVersion: rbac.authorization.k8s.io/v1
+var _configGatekeeperV340RbacAuthorizationK8sIo_v1_clusterrolebinding_gatekeeperManagerRolebindingYaml = []byte(`apiVersion: rbac.authorization.k8s.io/v1
 kind: ClusterRoleBinding
 metadata:
   labels:
@@ -1487,22 +3020,22 @@ subjects:
   namespace: gatekeeper-system
 `)
 
-func configGatekeeperRbacAuthorizationK8sIo_v1_clusterrolebinding_gatekeeperManagerRolebindingYamlBytes() ([]byte, error) {
-	return _configGatekeeperRbacAuthorizationK8sIo_v1_clusterrolebinding_gatekeeperManagerRolebindingYaml, nil
+func configGatekeeperV340RbacAuthorizationK8sIo_v1_clusterrolebinding_gatekeeperManagerRolebindingYamlBytes() ([]byte, error) {
+	return _configGatekeeperV340RbacAuthorizationK8sIo_v1_clusterrolebinding_gatekeeperManagerRolebindingYaml, nil
 }
 
-func configGatekeeperRbacAuthorizationK8sIo_v1_clusterrolebinding_gatekeeperManagerRolebindingYaml() (*asset, error) {
-	bytes, err := configGatekeeperRbacAuthorizationK8sIo_v1_clusterrolebinding_gatekeeperManagerRolebindingYamlBytes()
+func configGatekeeperV340RbacAuthorizationK8sIo_v1_clusterrolebinding_gatekeeperManagerRolebindingYaml() (*asset, error) {
+	bytes, err := configGatekeeperV340RbacAuthorizationK8sIo_v1_clusterrolebinding_gatekeeperManagerRolebindingYamlBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "config/gatekeeper/rbac.authorization.k8s.io_v1_clusterrolebinding_gatekeeper-manager-rolebinding.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	info := bindataFileInfo{name: "config/gatekeeper/v3.4.0/rbac.authorization.k8s.io_v1_clusterrolebinding_gatekeeper-manager-rolebinding.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _configGatekeeperRbacAuthorizationK8sIo_v1_role_gatekeeperManagerRoleYaml = []byte(`apiVersion: rbac.authorization.k8s.io/v1
+var _configGatekeeperV340RbacAuthorizationK8sIo_v1_role_gatekeeperManagerRoleYaml = []byte(`apiVersion: rbac.authorization.k8s.io/v1
 kind: Role
 metadata:
   creationTimestamp: null
@@ -1532,22 +3065,22 @@ rules:
   - watch
 `)
 
-func configGatekeeperRbacAuthorizationK8sIo_v1_role_gatekeeperManagerRoleYamlBytes() ([]byte, error) {
-	return _configGatekeeperRbacAuthorizationK8sIo_v1_role_gatekeeperManagerRoleYaml, nil
+func configGatekeeperV340RbacAuthorizationK8sIo_v1_role_gatekeeperManagerRoleYamlBytes() ([]byte, error) {
+	return _configGatekeeperV340RbacAuthorizationK8sIo_v1_role_gatekeeperManagerRoleYaml, nil
 }
 
-func configGatekeeperRbacAuthorizationK8sIo_v1_role_gatekeeperManagerRoleYaml() (*asset, error) {
-	bytes, err := configGatekeeperRbacAuthorizationK8sIo_v1_role_gatekeeperManagerRoleYamlBytes()
+func configGatekeeperV340RbacAuthorizationK8sIo_v1_role_gatekeeperManagerRoleYaml() (*asset, error) {
+	bytes, err := configGatekeeperV340RbacAuthorizationK8sIo_v1_role_gatekeeperManagerRoleYamlBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "config/gatekeeper/rbac.authorization.k8s.io_v1_role_gatekeeper-manager-role.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	info := bindataFileInfo{name: "config/gatekeeper/v3.4.0/rbac.authorization.k8s.io_v1_role_gatekeeper-manager-role.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _configGatekeeperRbacAuthorizationK8sIo_v1_rolebinding_gatekeeperManagerRolebindingYaml = []byte(`apiVersion: rbac.authorization.k8s.io/v1
+var _configGatekeeperV340RbacAuthorizationK8sIo_v1_rolebinding_gatekeeperManagerRolebindingYaml = []byte(`apiVersion: rbac.authorization.k8s.io/v1
 kind: RoleBinding
 metadata:
   labels:
@@ -1564,22 +3097,22 @@ subjects:
   namespace: gatekeeper-system
 `)
 
-func configGatekeeperRbacAuthorizationK8sIo_v1_rolebinding_gatekeeperManagerRolebindingYamlBytes() ([]byte, error) {
-	return _configGatekeeperRbacAuthorizationK8sIo_v1_rolebinding_gatekeeperManagerRolebindingYaml, nil
+func configGatekeeperV340RbacAuthorizationK8sIo_v1_rolebinding_gatekeeperManagerRolebindingYamlBytes() ([]byte, error) {
+	return _configGatekeeperV340RbacAuthorizationK8sIo_v1_rolebinding_gatekeeperManagerRolebindingYaml, nil
 }
 
-func configGatekeeperRbacAuthorizationK8sIo_v1_rolebinding_gatekeeperManagerRolebindingYaml() (*asset, error) {
-	bytes, err := configGatekeeperRbacAuthorizationK8sIo_v1_rolebinding_gatekeeperManagerRolebindingYamlBytes()
+func configGatekeeperV340RbacAuthorizationK8sIo_v1_rolebinding_gatekeeperManagerRolebindingYaml() (*asset, error) {
+	bytes, err := configGatekeeperV340RbacAuthorizationK8sIo_v1_rolebinding_gatekeeperManagerRolebindingYamlBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "config/gatekeeper/rbac.authorization.k8s.io_v1_rolebinding_gatekeeper-manager-rolebinding.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	info := bindataFileInfo{name: "config/gatekeeper/v3.4.0/rbac.authorization.k8s.io_v1_rolebinding_gatekeeper-manager-rolebinding.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _configGatekeeperV1_namespace_gatekeeperSystemYaml = []byte(`apiVersion: v1
+var _configGatekeeperV340V1_namespace_gatekeeperSystemYaml = []byte(`apiVersion: v1
 kind: Namespace
 metadata:
   labels:
@@ -1589,22 +3122,22 @@ metadata:
   name: gatekeeper-system
 `)
 
-func configGatekeeperV1_namespace_gatekeeperSystemYamlBytes() ([]byte, error) {
-	return _configGatekeeperV1_namespace_gatekeeperSystemYaml, nil
+func configGatekeeperV340V1_namespace_gatekeeperSystemYamlBytes() ([]byte, error) {
+	return _configGatekeeperV340V1_namespace_gatekeeperSystemYaml, nil
 }
 
-func configGatekeeperV1_namespace_gatekeeperSystemYaml() (*asset, error) {
-	bytes, err := configGatekeeperV1_namespace_gatekeeperSystemYamlBytes()
+func configGatekeeperV340V1_namespace_gatekeeperSystemYaml() (*asset, error) {
+	bytes, err := configGatekeeperV340V1_namespace_gatekeeperSystemYamlBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "config/gatekeeper/v1_namespace_gatekeeper-system.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	info := bindataFileInfo{name: "config/gatekeeper/v3.4.0/v1_namespace_gatekeeper-system.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _configGatekeeperV1_secret_gatekeeperWebhookServerCertYaml = []byte(`apiVersion: v1
+var _configGatekeeperV340V1_secret_gatekeeperWebhookServerCertYaml = []byte(`apiVersion: v1
 kind: Secret
 metadata:
   labels:
@@ -1613,22 +3146,22 @@ metadata:
   namespace: gatekeeper-system
 `)
 
-func configGatekeeperV1_secret_gatekeeperWebhookServerCertYamlBytes() ([]byte, error) {
-	return _configGatekeeperV1_secret_gatekeeperWebhookServerCertYaml, nil
+func configGatekeeperV340V1_secret_gatekeeperWebhookServerCertYamlBytes() ([]byte, error) {
+	return _configGatekeeperV340V1_secret_gatekeeperWebhookServerCertYaml, nil
 }
 
-func configGatekeeperV1_secret_gatekeeperWebhookServerCertYaml() (*asset, error) {
-	bytes, err := configGatekeeperV1_secret_gatekeeperWebhookServerCertYamlBytes()
+func configGatekeeperV340V1_secret_gatekeeperWebhookServerCertYaml() (*asset, error) {
+	bytes, err := configGatekeeperV340V1_secret_gatekeeperWebhookServerCertYamlBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "config/gatekeeper/v1_secret_gatekeeper-webhook-server-cert.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	info := bindataFileInfo{name: "config/gatekeeper/v3.4.0/v1_secret_gatekeeper-webhook-server-cert.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _configGatekeeperV1_service_gatekeeperWebhookServiceYaml = []byte(`apiVersion: v1
+var _configGatekeeperV340V1_service_gatekeeperWebhookServiceYaml = []byte(`apiVersion: v1
 kind: Service
 metadata:
   labels:
@@ -1645,22 +3178,22 @@ spec:
     gatekeeper.sh/system: "yes"
 `)
 
-func configGatekeeperV1_service_gatekeeperWebhookServiceYamlBytes() ([]byte, error) {
-	return _configGatekeeperV1_service_gatekeeperWebhookServiceYaml, nil
+func configGatekeeperV340V1_service_gatekeeperWebhookServiceYamlBytes() ([]byte, error) {
+	return _configGatekeeperV340V1_service_gatekeeperWebhookServiceYaml, nil
 }
 
-func configGatekeeperV1_service_gatekeeperWebhookServiceYaml() (*asset, error) {
-	bytes, err := configGatekeeperV1_service_gatekeeperWebhookServiceYamlBytes()
+func configGatekeeperV340V1_service_gatekeeperWebhookServiceYaml() (*asset, error) {
+	bytes, err := configGatekeeperV340V1_service_gatekeeperWebhookServiceYamlBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "config/gatekeeper/v1_service_gatekeeper-webhook-service.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	info := bindataFileInfo{name: "config/gatekeeper/v3.4.0/v1_service_gatekeeper-webhook-service.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _configGatekeeperV1_serviceaccount_gatekeeperAdminYaml = []byte(`apiVersion: v1
+var _configGatekeeperV340V1_serviceaccount_gatekeeperAdminYaml = []byte(`apiVersion: v1
 kind: ServiceAccount
 metadata:
   labels:
@@ -1669,17 +3202,161 @@ metadata:
   namespace: gatekeeper-system
 `)
 
-func configGatekeeperV1_serviceaccount_gatekeeperAdminYamlBytes() ([]byte, error) {
-	return _configGatekeeperV1_serviceaccount_gatekeeperAdminYaml, nil
+func configGatekeeperV340V1_serviceaccount_gatekeeperAdminYamlBytes() ([]byte, error) {
+	return _configGatekeeperV340V1_serviceaccount_gatekeeperAdminYaml, nil
+}
+
+func configGatekeeperV340V1_serviceaccount_gatekeeperAdminYaml() (*asset, error) {
+	bytes, err := configGatekeeperV340V1_serviceaccount_gatekeeperAdminYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/gatekeeper/v3.4.0/v1_serviceaccount_gatekeeper-admin.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _configOperatorAssetsCertmanagerCertManagerIo_v1_certificate_gatekeeperServingCertYaml = []byte(`apiVersion: cert-manager.io/v1
+kind: Certificate
+metadata:
+  labels:
+    gatekeeper.sh/system: "yes"
+  name: gatekeeper-serving-cert
+  namespace: gatekeeper-system
+spec:
+  dnsNames:
+  - gatekeeper-webhook-service.gatekeeper-system.svc
+  - gatekeeper-webhook-service.gatekeeper-system.svc.cluster.local
+  issuerRef:
+    kind: Issuer
+    name: gatekeeper-selfsigned-issuer
+  secretName: gatekeeper-webhook-server-cert
+`)
+
+func configOperatorAssetsCertmanagerCertManagerIo_v1_certificate_gatekeeperServingCertYamlBytes() ([]byte, error) {
+	return _configOperatorAssetsCertmanagerCertManagerIo_v1_certificate_gatekeeperServingCertYaml, nil
+}
+
+func configOperatorAssetsCertmanagerCertManagerIo_v1_certificate_gatekeeperServingCertYaml() (*asset, error) {
+	bytes, err := configOperatorAssetsCertmanagerCertManagerIo_v1_certificate_gatekeeperServingCertYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/operator-assets/certmanager/cert-manager.io_v1_certificate_gatekeeper-serving-cert.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _configOperatorAssetsCertmanagerCertManagerIo_v1_issuer_gatekeeperSelfsignedIssuerYaml = []byte(`apiVersion: cert-manager.io/v1
+kind: Issuer
+metadata:
+  labels:
+    gatekeeper.sh/system: "yes"
+  name: gatekeeper-selfsigned-issuer
+  namespace: gatekeeper-system
+spec:
+  selfSigned: {}
+`)
+
+func configOperatorAssetsCertmanagerCertManagerIo_v1_issuer_gatekeeperSelfsignedIssuerYamlBytes() ([]byte, error) {
+	return _configOperatorAssetsCertmanagerCertManagerIo_v1_issuer_gatekeeperSelfsignedIssuerYaml, nil
+}
+
+func configOperatorAssetsCertmanagerCertManagerIo_v1_issuer_gatekeeperSelfsignedIssuerYaml() (*asset, error) {
+	bytes, err := configOperatorAssetsCertmanagerCertManagerIo_v1_issuer_gatekeeperSelfsignedIssuerYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/operator-assets/certmanager/cert-manager.io_v1_issuer_gatekeeper-selfsigned-issuer.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _configOperatorAssetsConfigGatekeeperSh_v1alpha1_config_configYaml = []byte(`apiVersion: config.gatekeeper.sh/v1alpha1
+kind: Config
+metadata:
+  labels:
+    gatekeeper.sh/system: "yes"
+  name: config
+  namespace: gatekeeper-system
+`)
+
+func configOperatorAssetsConfigGatekeeperSh_v1alpha1_config_configYamlBytes() ([]byte, error) {
+	return _configOperatorAssetsConfigGatekeeperSh_v1alpha1_config_configYaml, nil
+}
+
+func configOperatorAssetsConfigGatekeeperSh_v1alpha1_config_configYaml() (*asset, error) {
+	bytes, err := configOperatorAssetsConfigGatekeeperSh_v1alpha1_config_configYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/operator-assets/config.gatekeeper.sh_v1alpha1_config_config.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _configOperatorAssetsPolicy_v1beta1_poddisruptionbudget_gatekeeperAuditYaml = []byte(`apiVersion: policy/v1beta1
+kind: PodDisruptionBudget
+metadata:
+  labels:
+    gatekeeper.sh/system: "yes"
+  name: gatekeeper-audit
+  namespace: gatekeeper-system
+spec:
+  maxUnavailable: 1
+  selector:
+    matchLabels:
+      control-plane: audit-controller
+      gatekeeper.sh/operation: audit
+      gatekeeper.sh/system: "yes"
+`)
+
+func configOperatorAssetsPolicy_v1beta1_poddisruptionbudget_gatekeeperAuditYamlBytes() ([]byte, error) {
+	return _configOperatorAssetsPolicy_v1beta1_poddisruptionbudget_gatekeeperAuditYaml, nil
+}
+
+func configOperatorAssetsPolicy_v1beta1_poddisruptionbudget_gatekeeperAuditYaml() (*asset, error) {
+	bytes, err := configOperatorAssetsPolicy_v1beta1_poddisruptionbudget_gatekeeperAuditYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/operator-assets/policy_v1beta1_poddisruptionbudget_gatekeeper-audit.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _configOperatorAssetsPolicy_v1beta1_poddisruptionbudget_gatekeeperControllerManagerYaml = []byte(`apiVersion: policy/v1beta1
+kind: PodDisruptionBudget
+metadata:
+  labels:
+    gatekeeper.sh/system: "yes"
+  name: gatekeeper-controller-manager
+  namespace: gatekeeper-system
+spec:
+  minAvailable: 1
+  selector:
+    matchLabels:
+      control-plane: controller-manager
+      gatekeeper.sh/operation: webhook
+      gatekeeper.sh/system: "yes"
+`)
+
+func configOperatorAssetsPolicy_v1beta1_poddisruptionbudget_gatekeeperControllerManagerYamlBytes() ([]byte, error) {
+	return _configOperatorAssetsPolicy_v1beta1_poddisruptionbudget_gatekeeperControllerManagerYaml, nil
 }
 
-func configGatekeeperV1_serviceaccount_gatekeeperAdminYaml() (*asset, error) {
-	bytes, err := configGatekeeperV1_serviceaccount_gatekeeperAdminYamlBytes()
+func configOperatorAssetsPolicy_v1beta1_poddisruptionbudget_gatekeeperControllerManagerYaml() (*asset, error) {
+	bytes, err := configOperatorAssetsPolicy_v1beta1_poddisruptionbudget_gatekeeperControllerManagerYamlBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "config/gatekeeper/v1_serviceaccount_gatekeeper-admin.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	info := bindataFileInfo{name: "config/operator-assets/policy_v1beta1_poddisruptionbudget_gatekeeper-controller-manager.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -1736,26 +3413,49 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
-	"config/gatekeeper/admissionregistration.k8s.io_v1beta1_mutatingwebhookconfiguration_gatekeeper-mutating-webhook-configuration.yaml":     configGatekeeperAdmissionregistrationK8sIo_v1beta1_mutatingwebhookconfiguration_gatekeeperMutatingWebhookConfigurationYaml,
-	"config/gatekeeper/admissionregistration.k8s.io_v1beta1_validatingwebhookconfiguration_gatekeeper-validating-webhook-configuration.yaml": configGatekeeperAdmissionregistrationK8sIo_v1beta1_validatingwebhookconfiguration_gatekeeperValidatingWebhookConfigurationYaml,
-	"config/gatekeeper/apiextensions.k8s.io_v1beta1_customresourcedefinition_assign.mutations.gatekeeper.sh.yaml":                            configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_assignMutationsGatekeeperShYaml,
-	"config/gatekeeper/apiextensions.k8s.io_v1beta1_customresourcedefinition_assignmetadata.mutations.gatekeeper.sh.yaml":                    configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_assignmetadataMutationsGatekeeperShYaml,
-	"config/gatekeeper/apiextensions.k8s.io_v1beta1_customresourcedefinition_configs.config.gatekeeper.sh.yaml":                              configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_configsConfigGatekeeperShYaml,
-	"config/gatekeeper/apiextensions.k8s.io_v1beta1_customresourcedefinition_constraintpodstatuses.status.gatekeeper.sh.yaml":                configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_constraintpodstatusesStatusGatekeeperShYaml,
-	"config/gatekeeper/apiextensions.k8s.io_v1beta1_customresourcedefinition_constrainttemplatepodstatuses.status.gatekeeper.sh.yaml":        configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatepodstatusesStatusGatekeeperShYaml,
-	"config/gatekeeper/apiextensions.k8s.io_v1beta1_customresourcedefinition_constrainttemplates.templates.gatekeeper.sh.yaml":               configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatesTemplatesGatekeeperShYaml,
-	"config/gatekeeper/apps_v1_deployment_gatekeeper-audit.yaml":                                                                             configGatekeeperApps_v1_deployment_gatekeeperAuditYaml,
-	"config/gatekeeper/apps_v1_deployment_gatekeeper-controller-manager.yaml":                                                                configGatekeeperApps_v1_deployment_gatekeeperControllerManagerYaml,
-	"config/gatekeeper/openshift/rbac.authorization.k8s.io_v1_role_gatekeeper-manager-role.yaml":                                             configGatekeeperOpenshiftRbacAuthorizationK8sIo_v1_role_gatekeeperManagerRoleYaml,
-	"config/gatekeeper/policy_v1beta1_podsecuritypolicy_gatekeeper-admin.yaml":                                                               configGatekeeperPolicy_v1beta1_podsecuritypolicy_gatekeeperAdminYaml,
-	"config/gatekeeper/rbac.authorization.k8s.io_v1_clusterrole_gatekeeper-manager-role.yaml":                                                configGatekeeperRbacAuthorizationK8sIo_v1_clusterrole_gatekeeperManagerRoleYaml,
-	"config/gatekeeper/rbac.authorization.k8s.io_v1_clusterrolebinding_gatekeeper-manager-rolebinding.yaml":                                  configGatekeeperRbacAuthorizationK8sIo_v1_clusterrolebinding_gatekeeperManagerRolebindingYaml,
-	"config/gatekeeper/rbac.authorization.k8s.io_v1_role_gatekeeper-manager-role.yaml":                                                       configGatekeeperRbacAuthorizationK8sIo_v1_role_gatekeeperManagerRoleYaml,
-	"config/gatekeeper/rbac.authorization.k8s.io_v1_rolebinding_gatekeeper-manager-rolebinding.yaml":                                         configGatekeeperRbacAuthorizationK8sIo_v1_rolebinding_gatekeeperManagerRolebindingYaml,
-	"config/gatekeeper/v1_namespace_gatekeeper-system.yaml":                                                                                  configGatekeeperV1_namespace_gatekeeperSystemYaml,
-	"config/gatekeeper/v1_secret_gatekeeper-webhook-server-cert.yaml":                                                                        configGatekeeperV1_secret_gatekeeperWebhookServerCertYaml,
-	"config/gatekeeper/v1_service_gatekeeper-webhook-service.yaml":                                                                           configGatekeeperV1_service_gatekeeperWebhookServiceYaml,
-	"config/gatekeeper/v1_serviceaccount_gatekeeper-admin.yaml":                                                                              configGatekeeperV1_serviceaccount_gatekeeperAdminYaml,
+	"config/gatekeeper/v3.3.0/admissionregistration.k8s.io_v1beta1_mutatingwebhookconfiguration_gatekeeper-mutating-webhook-configuration.yaml":     configGatekeeperV330AdmissionregistrationK8sIo_v1beta1_mutatingwebhookconfiguration_gatekeeperMutatingWebhookConfigurationYaml,
+	"config/gatekeeper/v3.3.0/admissionregistration.k8s.io_v1beta1_validatingwebhookconfiguration_gatekeeper-validating-webhook-configuration.yaml": configGatekeeperV330AdmissionregistrationK8sIo_v1beta1_validatingwebhookconfiguration_gatekeeperValidatingWebhookConfigurationYaml,
+	"config/gatekeeper/v3.3.0/apiextensions.k8s.io_v1beta1_customresourcedefinition_assign.mutations.gatekeeper.sh.yaml":                            configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_assignMutationsGatekeeperShYaml,
+	"config/gatekeeper/v3.3.0/apiextensions.k8s.io_v1beta1_customresourcedefinition_assignmetadata.mutations.gatekeeper.sh.yaml":                    configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_assignmetadataMutationsGatekeeperShYaml,
+	"config/gatekeeper/v3.3.0/apiextensions.k8s.io_v1beta1_customresourcedefinition_configs.config.gatekeeper.sh.yaml":                              configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_configsConfigGatekeeperShYaml,
+	"config/gatekeeper/v3.3.0/apiextensions.k8s.io_v1beta1_customresourcedefinition_constraintpodstatuses.status.gatekeeper.sh.yaml":                configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_constraintpodstatusesStatusGatekeeperShYaml,
+	"config/gatekeeper/v3.3.0/apiextensions.k8s.io_v1beta1_customresourcedefinition_constrainttemplatepodstatuses.status.gatekeeper.sh.yaml":        configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatepodstatusesStatusGatekeeperShYaml,
+	"config/gatekeeper/v3.3.0/apiextensions.k8s.io_v1beta1_customresourcedefinition_constrainttemplates.templates.gatekeeper.sh.yaml":               configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatesTemplatesGatekeeperShYaml,
+	"config/gatekeeper/v3.3.0/apps_v1_deployment_gatekeeper-audit.yaml":                                                                             configGatekeeperV330Apps_v1_deployment_gatekeeperAuditYaml,
+	"config/gatekeeper/v3.3.0/apps_v1_deployment_gatekeeper-controller-manager.yaml":                                                                configGatekeeperV330Apps_v1_deployment_gatekeeperControllerManagerYaml,
+	"config/gatekeeper/v3.3.0/policy_v1beta1_podsecuritypolicy_gatekeeper-admin.yaml":                                                               configGatekeeperV330Policy_v1beta1_podsecuritypolicy_gatekeeperAdminYaml,
+	"config/gatekeeper/v3.3.0/rbac.authorization.k8s.io_v1_clusterrole_gatekeeper-manager-role.yaml":                                                configGatekeeperV330RbacAuthorizationK8sIo_v1_clusterrole_gatekeeperManagerRoleYaml,
+	"config/gatekeeper/v3.3.0/rbac.authorization.k8s.io_v1_clusterrolebinding_gatekeeper-manager-rolebinding.yaml":                                  configGatekeeperV330RbacAuthorizationK8sIo_v1_clusterrolebinding_gatekeeperManagerRolebindingYaml,
+	"config/gatekeeper/v3.3.0/rbac.authorization.k8s.io_v1_role_gatekeeper-manager-role.yaml":                                                       configGatekeeperV330RbacAuthorizationK8sIo_v1_role_gatekeeperManagerRoleYaml,
+	"config/gatekeeper/v3.3.0/rbac.authorization.k8s.io_v1_rolebinding_gatekeeper-manager-rolebinding.yaml":                                         configGatekeeperV330RbacAuthorizationK8sIo_v1_rolebinding_gatekeeperManagerRolebindingYaml,
+	"config/gatekeeper/v3.3.0/v1_namespace_gatekeeper-system.yaml":                                                                                  configGatekeeperV330V1_namespace_gatekeeperSystemYaml,
+	"config/gatekeeper/v3.3.0/v1_secret_gatekeeper-webhook-server-cert.yaml":                                                                        configGatekeeperV330V1_secret_gatekeeperWebhookServerCertYaml,
+	"config/gatekeeper/v3.3.0/v1_service_gatekeeper-webhook-service.yaml":                                                                           configGatekeeperV330V1_service_gatekeeperWebhookServiceYaml,
+	"config/gatekeeper/v3.3.0/v1_serviceaccount_gatekeeper-admin.yaml":                                                                              configGatekeeperV330V1_serviceaccount_gatekeeperAdminYaml,
+	"config/gatekeeper/v3.4.0/admissionregistration.k8s.io_v1beta1_mutatingwebhookconfiguration_gatekeeper-mutating-webhook-configuration.yaml":     configGatekeeperV340AdmissionregistrationK8sIo_v1beta1_mutatingwebhookconfiguration_gatekeeperMutatingWebhookConfigurationYaml,
+	"config/gatekeeper/v3.4.0/admissionregistration.k8s.io_v1beta1_validatingwebhookconfiguration_gatekeeper-validating-webhook-configuration.yaml": configGatekeeperV340AdmissionregistrationK8sIo_v1beta1_validatingwebhookconfiguration_gatekeeperValidatingWebhookConfigurationYaml,
+	"config/gatekeeper/v3.4.0/apiextensions.k8s.io_v1beta1_customresourcedefinition_assign.mutations.gatekeeper.sh.yaml":                            configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_assignMutationsGatekeeperShYaml,
+	"config/gatekeeper/v3.4.0/apiextensions.k8s.io_v1beta1_customresourcedefinition_assignmetadata.mutations.gatekeeper.sh.yaml":                    configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_assignmetadataMutationsGatekeeperShYaml,
+	"config/gatekeeper/v3.4.0/apiextensions.k8s.io_v1beta1_customresourcedefinition_configs.config.gatekeeper.sh.yaml":                              configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_configsConfigGatekeeperShYaml,
+	"config/gatekeeper/v3.4.0/apiextensions.k8s.io_v1beta1_customresourcedefinition_constraintpodstatuses.status.gatekeeper.sh.yaml":                configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_constraintpodstatusesStatusGatekeeperShYaml,
+	"config/gatekeeper/v3.4.0/apiextensions.k8s.io_v1beta1_customresourcedefinition_constrainttemplatepodstatuses.status.gatekeeper.sh.yaml":        configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatepodstatusesStatusGatekeeperShYaml,
+	"config/gatekeeper/v3.4.0/apiextensions.k8s.io_v1beta1_customresourcedefinition_constrainttemplates.templates.gatekeeper.sh.yaml":               configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatesTemplatesGatekeeperShYaml,
+	"config/gatekeeper/v3.4.0/apps_v1_deployment_gatekeeper-audit.yaml":                                                                             configGatekeeperV340Apps_v1_deployment_gatekeeperAuditYaml,
+	"config/gatekeeper/v3.4.0/apps_v1_deployment_gatekeeper-controller-manager.yaml":                                                                configGatekeeperV340Apps_v1_deployment_gatekeeperControllerManagerYaml,
+	"config/gatekeeper/v3.4.0/policy_v1beta1_podsecuritypolicy_gatekeeper-admin.yaml":                                                               configGatekeeperV340Policy_v1beta1_podsecuritypolicy_gatekeeperAdminYaml,
+	"config/gatekeeper/v3.4.0/rbac.authorization.k8s.io_v1_clusterrole_gatekeeper-manager-role.yaml":                                                configGatekeeperV340RbacAuthorizationK8sIo_v1_clusterrole_gatekeeperManagerRoleYaml,
+	"config/gatekeeper/v3.4.0/rbac.authorization.k8s.io_v1_clusterrolebinding_gatekeeper-manager-rolebinding.yaml":                                  configGatekeeperV340RbacAuthorizationK8sIo_v1_clusterrolebinding_gatekeeperManagerRolebindingYaml,
+	"config/gatekeeper/v3.4.0/rbac.authorization.k8s.io_v1_role_gatekeeper-manager-role.yaml":                                                       configGatekeeperV340RbacAuthorizationK8sIo_v1_role_gatekeeperManagerRoleYaml,
+	"config/gatekeeper/v3.4.0/rbac.authorization.k8s.io_v1_rolebinding_gatekeeper-manager-rolebinding.yaml":                                         configGatekeeperV340RbacAuthorizationK8sIo_v1_rolebinding_gatekeeperManagerRolebindingYaml,
+	"config/gatekeeper/v3.4.0/v1_namespace_gatekeeper-system.yaml":                                                                                  configGatekeeperV340V1_namespace_gatekeeperSystemYaml,
+	"config/gatekeeper/v3.4.0/v1_secret_gatekeeper-webhook-server-cert.yaml":                                                                        configGatekeeperV340V1_secret_gatekeeperWebhookServerCertYaml,
+	"config/gatekeeper/v3.4.0/v1_service_gatekeeper-webhook-service.yaml":                                                                           configGatekeeperV340V1_service_gatekeeperWebhookServiceYaml,
+	"config/gatekeeper/v3.4.0/v1_serviceaccount_gatekeeper-admin.yaml":                                                                              configGatekeeperV340V1_serviceaccount_gatekeeperAdminYaml,
+	"config/operator-assets/certmanager/cert-manager.io_v1_certificate_gatekeeper-serving-cert.yaml":                                                configOperatorAssetsCertmanagerCertManagerIo_v1_certificate_gatekeeperServingCertYaml,
+	"config/operator-assets/certmanager/cert-manager.io_v1_issuer_gatekeeper-selfsigned-issuer.yaml":                                                configOperatorAssetsCertmanagerCertManagerIo_v1_issuer_gatekeeperSelfsignedIssuerYaml,
+	"config/operator-assets/config.gatekeeper.sh_v1alpha1_config_config.yaml":                                                                       configOperatorAssetsConfigGatekeeperSh_v1alpha1_config_configYaml,
+	"config/operator-assets/policy_v1beta1_poddisruptionbudget_gatekeeper-audit.yaml":                                                               configOperatorAssetsPolicy_v1beta1_poddisruptionbudget_gatekeeperAuditYaml,
+	"config/operator-assets/policy_v1beta1_poddisruptionbudget_gatekeeper-controller-manager.yaml":                                                  configOperatorAssetsPolicy_v1beta1_poddisruptionbudget_gatekeeperControllerManagerYaml,
 }
 
 // AssetDir returns the file names below a certain
@@ -1801,28 +3501,57 @@ type bintree struct {
 var _bintree = &bintree{nil, map[string]*bintree{
 	"config": {nil, map[string]*bintree{
 		"gatekeeper": {nil, map[string]*bintree{
-			"admissionregistration.k8s.io_v1beta1_mutatingwebhookconfiguration_gatekeeper-mutating-webhook-configuration.yaml":     {configGatekeeperAdmissionregistrationK8sIo_v1beta1_mutatingwebhookconfiguration_gatekeeperMutatingWebhookConfigurationYaml, map[string]*bintree{}},
-			"admissionregistration.k8s.io_v1beta1_validatingwebhookconfiguration_gatekeeper-validating-webhook-configuration.yaml": {configGatekeeperAdmissionregistrationK8sIo_v1beta1_validatingwebhookconfiguration_gatekeeperValidatingWebhookConfigurationYaml, map[string]*bintree{}},
-			"apiextensions.k8s.io_v1beta1_customresourcedefinition_assign.mutations.gatekeeper.sh.yaml":                            {configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_assignMutationsGatekeeperShYaml, map[string]*bintree{}},
-			"apiextensions.k8s.io_v1beta1_customresourcedefinition_assignmetadata.mutations.gatekeeper.sh.yaml":                    {configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_assignmetadataMutationsGatekeeperShYaml, map[string]*bintree{}},
-			"apiextensions.k8s.io_v1beta1_customresourcedefinition_configs.config.gatekeeper.sh.yaml":                              {configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_configsConfigGatekeeperShYaml, map[string]*bintree{}},
-			"apiextensions.k8s.io_v1beta1_customresourcedefinition_constraintpodstatuses.status.gatekeeper.sh.yaml":                {configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_constraintpodstatusesStatusGatekeeperShYaml, map[string]*bintree{}},
-			"apiextensions.k8s.io_v1beta1_customresourcedefinition_constrainttemplatepodstatuses.status.gatekeeper.sh.yaml":        {configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatepodstatusesStatusGatekeeperShYaml, map[string]*bintree{}},
-			"apiextensions.k8s.io_v1beta1_customresourcedefinition_constrainttemplates.templates.gatekeeper.sh.yaml":               {configGatekeeperApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatesTemplatesGatekeeperShYaml, map[string]*bintree{}},
-			"apps_v1_deployment_gatekeeper-audit.yaml":                                                                             {configGatekeeperApps_v1_deployment_gatekeeperAuditYaml, map[string]*bintree{}},
-			"apps_v1_deployment_gatekeeper-controller-manager.yaml":                                                                {configGatekeeperApps_v1_deployment_gatekeeperControllerManagerYaml, map[string]*bintree{}},
-			"openshift": {nil, map[string]*bintree{
-				"rbac.authorization.k8s.io_v1_role_gatekeeper-manager-role.yaml": {configGatekeeperOpenshiftRbacAuthorizationK8sIo_v1_role_gatekeeperManagerRoleYaml, map[string]*bintree{}},
+			"v3.3.0": {nil, map[string]*bintree{
+				"admissionregistration.k8s.io_v1beta1_mutatingwebhookconfiguration_gatekeeper-mutating-webhook-configuration.yaml":     {configGatekeeperV330AdmissionregistrationK8sIo_v1beta1_mutatingwebhookconfiguration_gatekeeperMutatingWebhookConfigurationYaml, map[string]*bintree{}},
+				"admissionregistration.k8s.io_v1beta1_validatingwebhookconfiguration_gatekeeper-validating-webhook-configuration.yaml": {configGatekeeperV330AdmissionregistrationK8sIo_v1beta1_validatingwebhookconfiguration_gatekeeperValidatingWebhookConfigurationYaml, map[string]*bintree{}},
+				"apiextensions.k8s.io_v1beta1_customresourcedefinition_assign.mutations.gatekeeper.sh.yaml":                            {configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_assignMutationsGatekeeperShYaml, map[string]*bintree{}},
+				"apiextensions.k8s.io_v1beta1_customresourcedefinition_assignmetadata.mutations.gatekeeper.sh.yaml":                    {configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_assignmetadataMutationsGatekeeperShYaml, map[string]*bintree{}},
+				"apiextensions.k8s.io_v1beta1_customresourcedefinition_configs.config.gatekeeper.sh.yaml":                              {configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_configsConfigGatekeeperShYaml, map[string]*bintree{}},
+				"apiextensions.k8s.io_v1beta1_customresourcedefinition_constraintpodstatuses.status.gatekeeper.sh.yaml":                {configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_constraintpodstatusesStatusGatekeeperShYaml, map[string]*bintree{}},
+				"apiextensions.k8s.io_v1beta1_customresourcedefinition_constrainttemplatepodstatuses.status.gatekeeper.sh.yaml":        {configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatepodstatusesStatusGatekeeperShYaml, map[string]*bintree{}},
+				"apiextensions.k8s.io_v1beta1_customresourcedefinition_constrainttemplates.templates.gatekeeper.sh.yaml":               {configGatekeeperV330ApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatesTemplatesGatekeeperShYaml, map[string]*bintree{}},
+				"apps_v1_deployment_gatekeeper-audit.yaml":                                                                             {configGatekeeperV330Apps_v1_deployment_gatekeeperAuditYaml, map[string]*bintree{}},
+				"apps_v1_deployment_gatekeeper-controller-manager.yaml":                                                                {configGatekeeperV330Apps_v1_deployment_gatekeeperControllerManagerYaml, map[string]*bintree{}},
+				"policy_v1beta1_podsecuritypolicy_gatekeeper-admin.yaml":                                                               {configGatekeeperV330Policy_v1beta1_podsecuritypolicy_gatekeeperAdminYaml, map[string]*bintree{}},
+				"rbac.authorization.k8s.io_v1_clusterrole_gatekeeper-manager-role.yaml":                                                {configGatekeeperV330RbacAuthorizationK8sIo_v1_clusterrole_gatekeeperManagerRoleYaml, map[string]*bintree{}},
+				"rbac.authorization.k8s.io_v1_clusterrolebinding_gatekeeper-manager-rolebinding.yaml":                                  {configGatekeeperV330RbacAuthorizationK8sIo_v1_clusterrolebinding_gatekeeperManagerRolebindingYaml, map[string]*bintree{}},
+				"rbac.authorization.k8s.io_v1_role_gatekeeper-manager-role.yaml":                                                       {configGatekeeperV330RbacAuthorizationK8sIo_v1_role_gatekeeperManagerRoleYaml, map[string]*bintree{}},
+				"rbac.authorization.k8s.io_v1_rolebinding_gatekeeper-manager-rolebinding.yaml":                                         {configGatekeeperV330RbacAuthorizationK8sIo_v1_rolebinding_gatekeeperManagerRolebindingYaml, map[string]*bintree{}},
+				"v1_namespace_gatekeeper-system.yaml":                                                                                  {configGatekeeperV330V1_namespace_gatekeeperSystemYaml, map[string]*bintree{}},
+				"v1_secret_gatekeeper-webhook-server-cert.yaml":                                                                        {configGatekeeperV330V1_secret_gatekeeperWebhookServerCertYaml, map[string]*bintree{}},
+				"v1_service_gatekeeper-webhook-service.yaml":                                                                           {configGatekeeperV330V1_service_gatekeeperWebhookServiceYaml, map[string]*bintree{}},
+				"v1_serviceaccount_gatekeeper-admin.yaml":                                                                              {configGatekeeperV330V1_serviceaccount_gatekeeperAdminYaml, map[string]*bintree{}},
+			}},
+			"v3.4.0": {nil, map[string]*bintree{
+				"admissionregistration.k8s.io_v1beta1_mutatingwebhookconfiguration_gatekeeper-mutating-webhook-configuration.yaml":     {configGatekeeperV340AdmissionregistrationK8sIo_v1beta1_mutatingwebhookconfiguration_gatekeeperMutatingWebhookConfigurationYaml, map[string]*bintree{}},
+				"admissionregistration.k8s.io_v1beta1_validatingwebhookconfiguration_gatekeeper-validating-webhook-configuration.yaml": {configGatekeeperV340AdmissionregistrationK8sIo_v1beta1_validatingwebhookconfiguration_gatekeeperValidatingWebhookConfigurationYaml, map[string]*bintree{}},
+				"apiextensions.k8s.io_v1beta1_customresourcedefinition_assign.mutations.gatekeeper.sh.yaml":                            {configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_assignMutationsGatekeeperShYaml, map[string]*bintree{}},
+				"apiextensions.k8s.io_v1beta1_customresourcedefinition_assignmetadata.mutations.gatekeeper.sh.yaml":                    {configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_assignmetadataMutationsGatekeeperShYaml, map[string]*bintree{}},
+				"apiextensions.k8s.io_v1beta1_customresourcedefinition_configs.config.gatekeeper.sh.yaml":                              {configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_configsConfigGatekeeperShYaml, map[string]*bintree{}},
+				"apiextensions.k8s.io_v1beta1_customresourcedefinition_constraintpodstatuses.status.gatekeeper.sh.yaml":                {configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_constraintpodstatusesStatusGatekeeperShYaml, map[string]*bintree{}},
+				"apiextensions.k8s.io_v1beta1_customresourcedefinition_constrainttemplatepodstatuses.status.gatekeeper.sh.yaml":        {configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatepodstatusesStatusGatekeeperShYaml, map[string]*bintree{}},
+				"apiextensions.k8s.io_v1beta1_customresourcedefinition_constrainttemplates.templates.gatekeeper.sh.yaml":               {configGatekeeperV340ApiextensionsK8sIo_v1beta1_customresourcedefinition_constrainttemplatesTemplatesGatekeeperShYaml, map[string]*bintree{}},
+				"apps_v1_deployment_gatekeeper-audit.yaml":                                                                             {configGatekeeperV340Apps_v1_deployment_gatekeeperAuditYaml, map[string]*bintree{}},
+				"apps_v1_deployment_gatekeeper-controller-manager.yaml":                                                                {configGatekeeperV340Apps_v1_deployment_gatekeeperControllerManagerYaml, map[string]*bintree{}},
+				"policy_v1beta1_podsecuritypolicy_gatekeeper-admin.yaml":                                                               {configGatekeeperV340Policy_v1beta1_podsecuritypolicy_gatekeeperAdminYaml, map[string]*bintree{}},
+				"rbac.authorization.k8s.io_v1_clusterrole_gatekeeper-manager-role.yaml":                                                {configGatekeeperV340RbacAuthorizationK8sIo_v1_clusterrole_gatekeeperManagerRoleYaml, map[string]*bintree{}},
+				"rbac.authorization.k8s.io_v1_clusterrolebinding_gatekeeper-manager-rolebinding.yaml":                                  {configGatekeeperV340RbacAuthorizationK8sIo_v1_clusterrolebinding_gatekeeperManagerRolebindingYaml, map[string]*bintree{}},
+				"rbac.authorization.k8s.io_v1_role_gatekeeper-manager-role.yaml":                                                       {configGatekeeperV340RbacAuthorizationK8sIo_v1_role_gatekeeperManagerRoleYaml, map[string]*bintree{}},
+				"rbac.authorization.k8s.io_v1_rolebinding_gatekeeper-manager-rolebinding.yaml":                                         {configGatekeeperV340RbacAuthorizationK8sIo_v1_rolebinding_gatekeeperManagerRolebindingYaml, map[string]*bintree{}},
+				"v1_namespace_gatekeeper-system.yaml":                                                                                  {configGatekeeperV340V1_namespace_gatekeeperSystemYaml, map[string]*bintree{}},
+				"v1_secret_gatekeeper-webhook-server-cert.yaml":                                                                        {configGatekeeperV340V1_secret_gatekeeperWebhookServerCertYaml, map[string]*bintree{}},
+				"v1_service_gatekeeper-webhook-service.yaml":                                                                           {configGatekeeperV340V1_service_gatekeeperWebhookServiceYaml, map[string]*bintree{}},
+				"v1_serviceaccount_gatekeeper-admin.yaml":                                                                              {configGatekeeperV340V1_serviceaccount_gatekeeperAdminYaml, map[string]*bintree{}},
+			}},
+		}},
+		"operator-assets": {nil, map[string]*bintree{
+			"certmanager": {nil, map[string]*bintree{
+				"cert-manager.io_v1_certificate_gatekeeper-serving-cert.yaml": {configOperatorAssetsCertmanagerCertManagerIo_v1_certificate_gatekeeperServingCertYaml, map[string]*bintree{}},
+				"cert-manager.io_v1_issuer_gatekeeper-selfsigned-issuer.yaml": {configOperatorAssetsCertmanagerCertManagerIo_v1_issuer_gatekeeperSelfsignedIssuerYaml, map[string]*bintree{}},
 			}},
-			"policy_v1beta1_podsecuritypolicy_gatekeeper-admin.yaml":                              {configGatekeeperPolicy_v1beta1_podsecuritypolicy_gatekeeperAdminYaml, map[string]*bintree{}},
-			"rbac.authorization.k8s.io_v1_clusterrole_gatekeeper-manager-role.yaml":               {configGatekeeperRbacAuthorizationK8sIo_v1_clusterrole_gatekeeperManagerRoleYaml, map[string]*bintree{}},
-			"rbac.authorization.k8s.io_v1_clusterrolebinding_gatekeeper-manager-rolebinding.yaml": {configGatekeeperRbacAuthorizationK8sIo_v1_clusterrolebinding_gatekeeperManagerRolebindingYaml, map[string]*bintree{}},
-			"rbac.authorization.k8s.io_v1_role_gatekeeper-manager-role.yaml":                      {configGatekeeperRbacAuthorizationK8sIo_v1_role_gatekeeperManagerRoleYaml, map[string]*bintree{}},
-			"rbac.authorization.k8s.io_v1_rolebinding_gatekeeper-manager-rolebinding.yaml":        {configGatekeeperRbacAuthorizationK8sIo_v1_rolebinding_gatekeeperManagerRolebindingYaml, map[string]*bintree{}},
-			"v1_namespace_gatekeeper-system.yaml":                                                 {configGatekeeperV1_namespace_gatekeeperSystemYaml, map[string]*bintree{}},
-			"v1_secret_gatekeeper-webhook-server-cert.yaml":                                       {configGatekeeperV1_secret_gatekeeperWebhookServerCertYaml, map[string]*bintree{}},
-			"v1_service_gatekeeper-webhook-service.yaml":                                          {configGatekeeperV1_service_gatekeeperWebhookServiceYaml, map[string]*bintree{}},
-			"v1_serviceaccount_gatekeeper-admin.yaml":                                             {configGatekeeperV1_serviceaccount_gatekeeperAdminYaml, map[string]*bintree{}},
+			"config.gatekeeper.sh_v1alpha1_config_config.yaml":                      {configOperatorAssetsConfigGatekeeperSh_v1alpha1_config_configYaml, map[string]*bintree{}},
+			"policy_v1beta1_poddisruptionbudget_gatekeeper-audit.yaml":              {configOperatorAssetsPolicy_v1beta1_poddisruptionbudget_gatekeeperAuditYaml, map[string]*bintree{}},
+			"policy_v1beta1_poddisruptionbudget_gatekeeper-controller-manager.yaml": {configOperatorAssetsPolicy_v1beta1_poddisruptionbudget_gatekeeperControllerManagerYaml, map[string]*bintree{}},
 		}},
 	}},
 }}
@@ -0,0 +1,323 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	CACertName = "ca.crt"
+	CAKeyName  = "ca.key"
+	CertName   = "tls.crt"
+	KeyName    = "tls.key"
+
+	caCommonName   = "gatekeeper-ca"
+	caOrganization = "gatekeeper"
+	// The CA outlives the serving certificates it signs by this factor so that
+	// serving certificates can be rotated without changing the CA bundle.
+	caValidityFactor = 10
+	rsaKeySize       = 2048
+)
+
+// Certificates holds the PEM encoded CA and serving certificates of the
+// Gatekeeper webhook.
+type Certificates struct {
+	// CACert holds the current CA certificate followed by the previous one
+	// while it is still valid, so that clients trust serving certificates
+	// signed by either during a CA rotation.
+	CACert []byte
+	CAKey  []byte
+	Cert   []byte
+	Key    []byte
+	// NotAfter is the expiry time of the serving certificate.
+	NotAfter time.Time
+}
+
+// Options configures the certificates generated by Ensure.
+type Options struct {
+	// DNSNames the serving certificate must be valid for.
+	DNSNames []string
+	// Validity of generated serving certificates.
+	Validity time.Duration
+	// RotateBefore is how long before expiry certificates are regenerated.
+	RotateBefore time.Duration
+}
+
+// FromSecretData returns the certificates stored in the given secret data.
+func FromSecretData(data map[string][]byte) *Certificates {
+	c := &Certificates{
+		CACert: data[CACertName],
+		CAKey:  data[CAKeyName],
+		Cert:   data[CertName],
+		Key:    data[KeyName],
+	}
+	if cert, _, err := parseKeyPair(c.Cert, c.Key); err == nil {
+		c.NotAfter = cert.NotAfter
+	}
+	return c
+}
+
+// SecretData returns the certificates as secret data. The CA key is omitted
+// when it is not known, e.g. for certificates issued outside of the operator.
+func (c *Certificates) SecretData() map[string][]byte {
+	data := map[string][]byte{
+		CACertName: c.CACert,
+		CertName:   c.Cert,
+		KeyName:    c.Key,
+	}
+	if len(c.CAKey) > 0 {
+		data[CAKeyName] = c.CAKey
+	}
+	return data
+}
+
+// Verify checks that the serving certificate matches its private key, chains
+// up to the CA and is valid for the given DNS names at the given time. It
+// returns the parsed serving certificate.
+func (c *Certificates) Verify(dnsNames []string, now time.Time) (*x509.Certificate, error) {
+	keyPair, err := tls.X509KeyPair(c.Cert, c.Key)
+	if err != nil {
+		return nil, errors.Wrap(err, "Invalid serving certificate")
+	}
+	cert, err := x509.ParseCertificate(keyPair.Certificate[0])
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to parse serving certificate")
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(c.CACert) {
+		return nil, errors.New("no CA certificate found")
+	}
+	intermediates := x509.NewCertPool()
+	for _, der := range keyPair.Certificate[1:] {
+		intermediate, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, errors.Wrap(err, "Unable to parse intermediate certificate")
+		}
+		intermediates.AddCert(intermediate)
+	}
+	for _, name := range dnsNames {
+		_, err := cert.Verify(x509.VerifyOptions{
+			DNSName:       name,
+			Roots:         roots,
+			Intermediates: intermediates,
+			CurrentTime:   now,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "Serving certificate is not valid for %s", name)
+		}
+	}
+	return cert, nil
+}
+
+// RotateAt returns the time at which the serving certificate is rotated.
+func (c *Certificates) RotateAt(opts Options) time.Time {
+	return c.NotAfter.Add(-opts.RotateBefore)
+}
+
+// Ensure returns certificates that are valid for the given options at the
+// given time. The current certificates are reused when they are still valid
+// and not due for rotation, otherwise a new serving certificate, and if
+// needed a new CA, is generated.
+func Ensure(current *Certificates, opts Options, now time.Time) (*Certificates, error) {
+	if opts.RotateBefore >= opts.Validity {
+		return nil, fmt.Errorf("certificate rotation period %s must be shorter than the certificate validity %s",
+			opts.RotateBefore, opts.Validity)
+	}
+	if current == nil {
+		current = &Certificates{}
+	}
+
+	result := &Certificates{
+		CACert: current.CACert,
+		CAKey:  current.CAKey,
+	}
+	ca, caKey, err := parseKeyPair(current.CACert, current.CAKey)
+	if err != nil || now.Add(opts.Validity).After(ca.NotAfter) {
+		// The CA is missing, invalid or would expire before a new serving
+		// certificate.
+		ca, caKey, result.CACert, result.CAKey, err = newCA(opts, now)
+		if err != nil {
+			return nil, err
+		}
+		if previous := validCACerts(current.CACert, now); len(previous) > 0 {
+			result.CACert = append(result.CACert, previous...)
+		}
+	}
+
+	cert, _, err := parseKeyPair(current.Cert, current.Key)
+	if err == nil && !needsRotation(cert, ca, opts, now) {
+		result.Cert = current.Cert
+		result.Key = current.Key
+		result.NotAfter = cert.NotAfter
+		return result, nil
+	}
+
+	result.Cert, result.Key, result.NotAfter, err = newServingCert(ca, caKey, opts, now)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func needsRotation(cert, ca *x509.Certificate, opts Options, now time.Time) bool {
+	if !now.Before(cert.NotAfter.Add(-opts.RotateBefore)) {
+		return true
+	}
+	if err := cert.CheckSignatureFrom(ca); err != nil {
+		return true
+	}
+	for _, name := range opts.DNSNames {
+		if err := cert.VerifyHostname(name); err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func newCA(opts Options, now time.Time) (*x509.Certificate, *rsa.PrivateKey, []byte, []byte, error) {
+	key, err := rsa.GenerateKey(rand.Reader, rsaKeySize)
+	if err != nil {
+		return nil, nil, nil, nil, errors.Wrap(err, "Unable to generate CA key")
+	}
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName:   caCommonName,
+			Organization: []string{caOrganization},
+		},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(opts.Validity * caValidityFactor),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, nil, nil, errors.Wrap(err, "Unable to create CA certificate")
+	}
+	ca, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, nil, nil, errors.Wrap(err, "Unable to parse CA certificate")
+	}
+	return ca, key, encodeCert(der), encodeKey(key), nil
+}
+
+func newServingCert(ca *x509.Certificate, caKey *rsa.PrivateKey, opts Options, now time.Time) ([]byte, []byte, time.Time, error) {
+	key, err := rsa.GenerateKey(rand.Reader, rsaKeySize)
+	if err != nil {
+		return nil, nil, time.Time{}, errors.Wrap(err, "Unable to generate serving key")
+	}
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
+	commonName := ""
+	if len(opts.DNSNames) > 0 {
+		commonName = opts.DNSNames[0]
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName: commonName,
+		},
+		DNSNames:    opts.DNSNames,
+		NotBefore:   now.Add(-time.Hour),
+		NotAfter:    now.Add(opts.Validity),
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, time.Time{}, errors.Wrap(err, "Unable to create serving certificate")
+	}
+	return encodeCert(der), encodeKey(key), template.NotAfter, nil
+}
+
+// parseKeyPair parses the first certificate of the given PEM data along with
+// its RSA private key.
+func parseKeyPair(certPEM, keyPEM []byte) (*x509.Certificate, *rsa.PrivateKey, error) {
+	certBlock, _ := pem.Decode(certPEM)
+	if certBlock == nil {
+		return nil, nil, errors.New("no certificate found")
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Unable to parse certificate")
+	}
+	keyBlock, _ := pem.Decode(keyPEM)
+	if keyBlock == nil {
+		return nil, nil, errors.New("no private key found")
+	}
+	key, err := x509.ParsePKCS1PrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Unable to parse private key")
+	}
+	if pub, ok := cert.PublicKey.(*rsa.PublicKey); !ok || key.PublicKey.N.Cmp(pub.N) != 0 {
+		return nil, nil, errors.New("private key does not match certificate")
+	}
+	return cert, key, nil
+}
+
+// validCACerts returns the PEM encoded certificates from the given data that
+// have not expired yet.
+func validCACerts(caPEM []byte, now time.Time) []byte {
+	var valid bytes.Buffer
+	for {
+		var block *pem.Block
+		block, caPEM = pem.Decode(caPEM)
+		if block == nil {
+			break
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil || !now.Before(cert.NotAfter) {
+			continue
+		}
+		valid.Write(pem.EncodeToMemory(block))
+	}
+	return valid.Bytes()
+}
+
+func newSerialNumber() (*big.Int, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to generate certificate serial number")
+	}
+	return serial, nil
+}
+
+func encodeCert(der []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func encodeKey(key *rsa.PrivateKey) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
@@ -0,0 +1,165 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+var testOptions = Options{
+	DNSNames:     []string{"gatekeeper-webhook-service.testns.svc"},
+	Validity:     24 * time.Hour,
+	RotateBefore: time.Hour,
+}
+
+func verifyCertificates(g *WithT, c *Certificates, dnsName string, now time.Time) {
+	block, _ := pem.Decode(c.Cert)
+	g.Expect(block).ToNot(BeNil())
+	cert, err := x509.ParseCertificate(block.Bytes)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cert.NotAfter).To(BeTemporally("~", c.NotAfter, time.Second))
+
+	roots := x509.NewCertPool()
+	g.Expect(roots.AppendCertsFromPEM(c.CACert)).To(BeTrue())
+	_, err = cert.Verify(x509.VerifyOptions{
+		DNSName:     dnsName,
+		Roots:       roots,
+		CurrentTime: now,
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+}
+
+func TestEnsure(t *testing.T) {
+	g := NewWithT(t)
+	now := time.Now()
+
+	// test generating new certificates
+	certificates, err := Ensure(nil, testOptions, now)
+	g.Expect(err).ToNot(HaveOccurred())
+	verifyCertificates(g, certificates, testOptions.DNSNames[0], now)
+	g.Expect(certificates.NotAfter).To(BeTemporally("~", now.Add(testOptions.Validity), time.Second))
+	g.Expect(certificates.RotateAt(testOptions)).To(BeTemporally("~", now.Add(23*time.Hour), time.Second))
+
+	// test valid certificates are reused
+	current := FromSecretData(certificates.SecretData())
+	g.Expect(current.NotAfter).To(BeTemporally("~", certificates.NotAfter, time.Second))
+	later := now.Add(12 * time.Hour)
+	reused, err := Ensure(current, testOptions, later)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(reused.CACert).To(Equal(certificates.CACert))
+	g.Expect(reused.Cert).To(Equal(certificates.Cert))
+	g.Expect(reused.Key).To(Equal(certificates.Key))
+
+	// test serving certificate is rotated before expiry with the same CA
+	later = now.Add(23*time.Hour + time.Minute)
+	rotated, err := Ensure(current, testOptions, later)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(rotated.CACert).To(Equal(certificates.CACert))
+	g.Expect(rotated.CAKey).To(Equal(certificates.CAKey))
+	g.Expect(rotated.Cert).ToNot(Equal(certificates.Cert))
+	verifyCertificates(g, rotated, testOptions.DNSNames[0], later)
+
+	// test serving certificate is rotated when the DNS names change
+	opts := testOptions
+	opts.DNSNames = []string{"gatekeeper-webhook-service.otherns.svc"}
+	rotated, err = Ensure(current, opts, now)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(rotated.Cert).ToNot(Equal(certificates.Cert))
+	verifyCertificates(g, rotated, opts.DNSNames[0], now)
+
+	// test CA is rotated before expiry and the previous CA is still trusted
+	later = now.Add(9*testOptions.Validity + time.Hour)
+	rotated, err = Ensure(current, testOptions, later)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(rotated.CAKey).ToNot(Equal(certificates.CAKey))
+	verifyCertificates(g, rotated, testOptions.DNSNames[0], later)
+	previous := &Certificates{
+		CACert:   rotated.CACert,
+		Cert:     certificates.Cert,
+		NotAfter: certificates.NotAfter,
+	}
+	verifyCertificates(g, previous, testOptions.DNSNames[0], now)
+
+	// test invalid certificates are replaced
+	current = &Certificates{
+		CACert: []byte("invalid"),
+		CAKey:  certificates.CAKey,
+		Cert:   certificates.Cert,
+		Key:    certificates.CAKey,
+	}
+	rotated, err = Ensure(current, testOptions, now)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(rotated.CAKey).ToNot(Equal(certificates.CAKey))
+	verifyCertificates(g, rotated, testOptions.DNSNames[0], now)
+
+	// test invalid options
+	opts = testOptions
+	opts.RotateBefore = opts.Validity
+	_, err = Ensure(nil, opts, now)
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestVerify(t *testing.T) {
+	g := NewWithT(t)
+	now := time.Now()
+	certificates, err := Ensure(nil, testOptions, now)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// test valid certificates
+	cert, err := certificates.Verify(testOptions.DNSNames, now)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cert.NotAfter).To(BeTemporally("~", certificates.NotAfter, time.Second))
+
+	// test certificates issued for other DNS names
+	_, err = certificates.Verify([]string{"gatekeeper-webhook-service.otherns.svc"}, now)
+	g.Expect(err).To(HaveOccurred())
+
+	// test expired certificates
+	_, err = certificates.Verify(testOptions.DNSNames, now.Add(testOptions.Validity+time.Hour))
+	g.Expect(err).To(HaveOccurred())
+
+	// test certificates signed by another CA
+	other, err := Ensure(nil, testOptions, now)
+	g.Expect(err).ToNot(HaveOccurred())
+	mismatched := &Certificates{
+		CACert: other.CACert,
+		Cert:   certificates.Cert,
+		Key:    certificates.Key,
+	}
+	_, err = mismatched.Verify(testOptions.DNSNames, now)
+	g.Expect(err).To(HaveOccurred())
+
+	// test private key not matching the certificate
+	mismatched = &Certificates{
+		CACert: certificates.CACert,
+		Cert:   certificates.Cert,
+		Key:    other.Key,
+	}
+	_, err = mismatched.Verify(testOptions.DNSNames, now)
+	g.Expect(err).To(HaveOccurred())
+
+	// test missing CA
+	mismatched = &Certificates{
+		Cert: certificates.Cert,
+		Key:  certificates.Key,
+	}
+	_, err = mismatched.Verify(testOptions.DNSNames, now)
+	g.Expect(err).To(HaveOccurred())
+}
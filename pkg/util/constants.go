@@ -15,6 +15,8 @@ limitations under the License.
 package util
 
 const (
+	NamespaceKind                      = "Namespace"
+	SecretKind                         = "Secret"
 	ServiceKind                        = "Service"
 	ValidatingWebhookConfigurationKind = "ValidatingWebhookConfiguration"
 	MutatingWebhookConfigurationKind   = "MutatingWebhookConfiguration"
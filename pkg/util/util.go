@@ -17,22 +17,46 @@ package util
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/version"
 	"k8s.io/client-go/kubernetes/scheme"
 
 	"github.com/gatekeeper/gatekeeper-operator/pkg/bindata"
 )
 
+const (
+	// DefaultGatekeeperVersion is the version of the Gatekeeper manifests
+	// deployed when the Gatekeeper CR does not specify one.
+	DefaultGatekeeperVersion = "v3.4.0"
+)
+
 var (
 	staticAssetsDir = "config/gatekeeper/"
+	// operatorAssetsDir holds the manifests of the resources the operator
+	// deploys along with the Gatekeeper manifests of every version.
+	operatorAssetsDir = "config/operator-assets/"
 )
 
+// GetManifestObject returns the object of the given asset from the manifests
+// of the default Gatekeeper version.
 func GetManifestObject(asset string) (*unstructured.Unstructured, error) {
-	assetName := staticAssetsDir + asset
+	return GetVersionedManifestObject(DefaultGatekeeperVersion, asset)
+}
+
+// GetVersionedManifestObject returns the object of the given asset from the
+// manifests of the given Gatekeeper version, or from the operator manifests
+// shared by every version.
+func GetVersionedManifestObject(version, asset string) (*unstructured.Unstructured, error) {
+	assetName := operatorAssetsDir + asset
 	bytes, err := bindata.Asset(assetName)
+	if err != nil {
+		assetName = staticAssetsDir + version + "/" + asset
+		bytes, err = bindata.Asset(assetName)
+	}
 	if err != nil {
 		return nil, errors.Wrapf(err, "Unable to retrieve bindata asset %s", assetName)
 	}
@@ -44,6 +68,34 @@ func GetManifestObject(asset string) (*unstructured.Unstructured, error) {
 	return obj, nil
 }
 
+// GetGatekeeperVersions returns the sorted Gatekeeper versions whose manifests
+// are embedded in the operator.
+func GetGatekeeperVersions() ([]string, error) {
+	versions, err := bindata.AssetDir(strings.TrimSuffix(staticAssetsDir, "/"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Unable to list bindata assets of %s", staticAssetsDir)
+	}
+	SortVersions(versions)
+	return versions, nil
+}
+
+// SortVersions sorts the given Gatekeeper versions by semantic version, so
+// that e.g. v3.10.0 comes after v3.4.0. Versions that cannot be parsed come
+// first, sorted alphabetically.
+func SortVersions(versions []string) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		a, errA := version.ParseSemantic(versions[i])
+		b, errB := version.ParseSemantic(versions[j])
+		switch {
+		case errA != nil && errB != nil:
+			return versions[i] < versions[j]
+		case errA != nil || errB != nil:
+			return errA != nil
+		}
+		return a.LessThan(b)
+	})
+}
+
 func unmarshalJSON(in []byte) (*unstructured.Unstructured, error) {
 	if in == nil {
 		return nil, errors.New("input bytes is nil")
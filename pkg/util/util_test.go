@@ -0,0 +1,34 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestSortVersions(t *testing.T) {
+	g := NewWithT(t)
+	versions := []string{"v3.10.0", "v3.4.0", "latest", "v3.4.1", "v3.3.0", "v4.0.0"}
+	SortVersions(versions)
+	g.Expect(versions).To(Equal([]string{"latest", "v3.3.0", "v3.4.0", "v3.4.1", "v3.10.0", "v4.0.0"}))
+
+	// test the bundled versions are sorted
+	bundled, err := GetGatekeeperVersions()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(bundled).To(ContainElement(DefaultGatekeeperVersion))
+	g.Expect(bundled[0]).To(Equal("v3.3.0"))
+}
@@ -29,8 +29,10 @@ import (
 	admregv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	extv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -39,6 +41,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/gatekeeper/gatekeeper-operator/api/v1alpha1"
+	"github.com/gatekeeper/gatekeeper-operator/api/v1beta1"
 	"github.com/gatekeeper/gatekeeper-operator/controllers"
 	"github.com/gatekeeper/gatekeeper-operator/pkg/util"
 	test "github.com/gatekeeper/gatekeeper-operator/test/e2e/util"
@@ -149,6 +152,20 @@ var _ = Describe("Gatekeeper", func() {
 					Expect(validatingWebhookConfiguration.OwnerReferences[0].Kind).To(Equal("Gatekeeper"))
 					Expect(validatingWebhookConfiguration.OwnerReferences[0].Name).To(Equal(gkName))
 				})
+
+				By("Checking Gatekeeper status is ready", func() {
+					Eventually(func() bool {
+						err := K8sClient.Get(ctx, gatekeeperName, gatekeeper)
+						if err != nil {
+							return false
+						}
+						return gatekeeper.Status.ObservedGeneration == gatekeeper.Generation &&
+							len(gatekeeper.Status.AuditConditions) == 1 &&
+							gatekeeper.Status.AuditConditions[0].Type == v1alpha1.StatusReady &&
+							len(gatekeeper.Status.WebhookConditions) == 1 &&
+							gatekeeper.Status.WebhookConditions[0].Type == v1alpha1.StatusReady
+					}, waitTimeout, pollInterval).Should(BeTrue())
+				})
 			})
 		})
 	})
@@ -202,6 +219,15 @@ var _ = Describe("Gatekeeper", func() {
 				Expect(webhookDeployment.Spec.Template.Spec.Containers[0].ImagePullPolicy).To(Equal(webhookImagePullPolicy))
 			})
 
+			By("Checking default pod disruption budgets", func() {
+				pdb := gatekeeperPodDisruptionBudget(controllerManagerName)
+				Expect(pdb.Spec.MinAvailable).NotTo(BeNil())
+				Expect(pdb.Spec.MinAvailable.IntValue()).To(Equal(1))
+				Expect(pdb.Spec.MaxUnavailable).To(BeNil())
+				err := K8sClient.Get(ctx, auditName, &policyv1beta1.PodDisruptionBudget{})
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			})
+
 			byCheckingFailurePolicy(&validatingWebhookName, "default",
 				util.ValidatingWebhookConfigurationKind,
 				controllers.ValidationGatekeeperWebhook,
@@ -272,7 +298,9 @@ var _ = Describe("Gatekeeper", func() {
 
 			By("Checking expected pod affinity", func() {
 				Expect(auditDeployment.Spec.Template.Spec.Affinity).To(BeEquivalentTo(gatekeeper.Spec.Affinity))
-				Expect(webhookDeployment.Spec.Template.Spec.Affinity).To(BeEquivalentTo(gatekeeper.Spec.Affinity))
+				expectedAffinity := test.DefaultDeployment.Affinity.DeepCopy()
+				expectedAffinity.PodAffinity = gatekeeper.Spec.Affinity.PodAffinity
+				Expect(webhookDeployment.Spec.Template.Spec.Affinity).To(BeEquivalentTo(expectedAffinity))
 			})
 
 			By("Checking expected node selector", func() {
@@ -290,6 +318,17 @@ var _ = Describe("Gatekeeper", func() {
 				Expect(webhookDeployment.Spec.Template.Spec.Tolerations).To(BeEquivalentTo(gatekeeper.Spec.Tolerations))
 			})
 
+			By("Checking expected topology spread constraints", func() {
+				Expect(auditDeployment.Spec.Template.Spec.TopologySpreadConstraints).To(BeNil())
+				Expect(webhookDeployment.Spec.Template.Spec.TopologySpreadConstraints).To(BeEquivalentTo(gatekeeper.Spec.Webhook.Scheduling.TopologySpreadConstraints))
+			})
+
+			By("Checking expected pod disruption budget", func() {
+				pdb := gatekeeperPodDisruptionBudget(controllerManagerName)
+				Expect(pdb.Spec.MinAvailable).To(BeNil())
+				Expect(pdb.Spec.MaxUnavailable).To(Equal(gatekeeper.Spec.Webhook.PodDisruptionBudget.MaxUnavailable))
+			})
+
 			By("Checking expected resource limits and requests", func() {
 				assertResources(*gatekeeper.Spec.Audit.Resources, auditDeployment.Spec.Template.Spec.Containers[0].Resources)
 				assertResources(*gatekeeper.Spec.Webhook.Resources, webhookDeployment.Spec.Template.Spec.Containers[0].Resources)
@@ -451,10 +490,335 @@ var _ = Describe("Gatekeeper", func() {
 
 			_, webhookDeployment = gatekeeperDeployments()
 			byCheckingMutationDisabled(webhookDeployment)
+			byCheckingMutatingCRDs("retained", crdDeployed)
+		})
+
+		It("Enables then disables Gatekeeper mutation deleting the mutating CRDs", func() {
+			gatekeeper := emptyGatekeeper()
+			By("First creating Gatekeeper CR with mutation enabled", func() {
+				webhookMode := v1alpha1.WebhookEnabled
+				gatekeeper.Spec.MutatingWebhook = &webhookMode
+				Expect(K8sClient.Create(ctx, gatekeeper)).Should(Succeed())
+			})
+
+			_, webhookDeployment := gatekeeperDeployments()
+			byCheckingMutationEnabled(webhookDeployment)
+
+			By("Getting Gatekeeper CR for updating", func() {
+				err := K8sClient.Get(ctx, gatekeeperName, gatekeeper)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			By("Updating Gatekeeper CR with mutation disabled and mutating CRDs deleted", func() {
+				webhookMode := v1alpha1.WebhookDisabled
+				gatekeeper.Spec.MutatingWebhook = &webhookMode
+				mutationCRDsPolicy := v1alpha1.MutationCRDsDelete
+				gatekeeper.Spec.MutationCRDsPolicy = &mutationCRDsPolicy
+				Expect(K8sClient.Update(ctx, gatekeeper)).Should(Succeed())
+			})
+
+			_, webhookDeployment = gatekeeperDeployments()
+			byCheckingMutationDisabled(webhookDeployment)
+			byCheckingMutatingCRDs("not deployed", crdNotDeployed)
+		})
+	})
+
+	Describe("Webhook certificates", func() {
+		It("Uses certificates managed by the operator", func() {
+			gatekeeper := emptyGatekeeper()
+			By("Creating Gatekeeper CR with operator managed certificates", func() {
+				mode := v1alpha1.CertificatesOperator
+				gatekeeper.Spec.Webhook = &v1alpha1.WebhookConfig{
+					Certificates: &v1alpha1.CertificatesConfig{
+						Mode: &mode,
+					},
+				}
+				Expect(K8sClient.Create(ctx, gatekeeper)).Should(Succeed())
+			})
+
+			webhookDeployment := gatekeeperWebhookDeployment()
+			By(fmt.Sprintf("Checking %s argument is set", controllers.DisableCertRotationArg), func() {
+				value, found := getContainerArg(webhookDeployment.Spec.Template.Spec.Containers[0].Args, controllers.DisableCertRotationArg)
+				Expect(found).To(BeTrue())
+				Expect(value).To(Equal(controllers.DisableCertRotationArg + "=true"))
+			})
+
+			serverCert := &corev1.Secret{}
+			By("Checking the webhook server certificate is generated", func() {
+				Eventually(func() ([]byte, error) {
+					err := K8sClient.Get(ctx, types.NamespacedName{
+						Namespace: gkNamespace,
+						Name:      "gatekeeper-webhook-server-cert",
+					}, serverCert)
+					return serverCert.Data["tls.crt"], err
+				}, waitTimeout, pollInterval).ShouldNot(BeEmpty())
+			})
+
+			By("Checking the CA is injected into the ValidatingWebhookConfiguration", func() {
+				validatingWebhookConfiguration := &admregv1.ValidatingWebhookConfiguration{}
+				Eventually(func() error {
+					return K8sClient.Get(ctx, validatingWebhookName, validatingWebhookConfiguration)
+				}, waitTimeout, pollInterval).ShouldNot(HaveOccurred())
+				for _, webhook := range validatingWebhookConfiguration.Webhooks {
+					Expect(webhook.ClientConfig.CABundle).To(Equal(serverCert.Data["ca.crt"]))
+				}
+			})
+
+			By("Checking the certificate expiry is reported in status", func() {
+				Eventually(func() bool {
+					err := K8sClient.Get(ctx, gatekeeperName, gatekeeper)
+					if err != nil || gatekeeper.Status.Certificates == nil {
+						return false
+					}
+					return gatekeeper.Status.Certificates.Mode == v1alpha1.CertificatesOperator &&
+						gatekeeper.Status.Certificates.NotAfter != nil
+				}, waitTimeout, pollInterval).Should(BeTrue())
+			})
+		})
+	})
+
+	Describe("Management state", func() {
+		It("Leaves Gatekeeper resources untouched when unmanaged", func() {
+			gatekeeper := emptyGatekeeper()
+			By("Creating Gatekeeper resource", func() {
+				Expect(K8sClient.Create(ctx, gatekeeper)).Should(Succeed())
+			})
+
+			auditDeployment, _ := gatekeeperDeployments()
+
+			By("Setting the management state to Unmanaged", func() {
+				setManagementState(v1alpha1.ManagementStateUnmanaged)
+			})
+
+			By("Modifying the audit Deployment", func() {
+				Eventually(func() error {
+					err := K8sClient.Get(ctx, auditName, auditDeployment)
+					if err != nil {
+						return err
+					}
+					replicas := int32(2)
+					auditDeployment.Spec.Replicas = &replicas
+					return K8sClient.Update(ctx, auditDeployment)
+				}, waitTimeout, pollInterval).Should(Succeed())
+			})
+
+			By("Checking the audit Deployment is not reverted", func() {
+				Consistently(func() (int32, error) {
+					err := K8sClient.Get(ctx, auditName, auditDeployment)
+					if err != nil {
+						return 0, err
+					}
+					return *auditDeployment.Spec.Replicas, nil
+				}, 5*time.Second, pollInterval).Should(Equal(int32(2)))
+			})
+		})
+
+		It("Removes Gatekeeper and keeps the Gatekeeper resource when removed", func() {
+			gatekeeper := emptyGatekeeper()
+			By("Creating Gatekeeper resource", func() {
+				Expect(K8sClient.Create(ctx, gatekeeper)).Should(Succeed())
+			})
+
+			gatekeeperDeployments()
+			byCheckingValidationEnabled()
+
+			By("Setting the management state to Removed", func() {
+				setManagementState(v1alpha1.ManagementStateRemoved)
+			})
+
+			By("Checking Gatekeeper resources are removed", func() {
+				Eventually(func() bool {
+					err := K8sClient.Get(ctx, controllerManagerName, &appsv1.Deployment{})
+					return apierrors.IsNotFound(err)
+				}, longWaitTimeout, pollInterval).Should(BeTrue())
+				err := K8sClient.Get(ctx, validatingWebhookName, &admregv1.ValidatingWebhookConfiguration{})
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+				err = K8sClient.Get(ctx, auditName, &appsv1.Deployment{})
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			})
+
+			By("Checking Gatekeeper status is removed", func() {
+				Eventually(func() bool {
+					err := K8sClient.Get(ctx, gatekeeperName, gatekeeper)
+					if err != nil {
+						return false
+					}
+					return len(gatekeeper.Status.AuditConditions) == 1 &&
+						gatekeeper.Status.AuditConditions[0].Reason == controllers.RemovedReason &&
+						len(gatekeeper.Status.WebhookConditions) == 1 &&
+						gatekeeper.Status.WebhookConditions[0].Reason == controllers.RemovedReason
+				}, waitTimeout, pollInterval).Should(BeTrue())
+			})
+
+			By("Setting the management state back to Managed", func() {
+				setManagementState(v1alpha1.ManagementStateManaged)
+			})
+
+			gatekeeperDeployments()
+			byCheckingValidationEnabled()
+		})
+	})
+
+	Describe("Admission webhook", func() {
+		It("Fills in the default values", func() {
+			gatekeeper := emptyGatekeeper()
+			Expect(K8sClient.Create(ctx, gatekeeper)).Should(Succeed())
+
+			Expect(gatekeeper.Spec.ManagementState).NotTo(BeNil())
+			Expect(*gatekeeper.Spec.ManagementState).To(Equal(v1alpha1.ManagementStateManaged))
+			Expect(gatekeeper.Spec.ValidatingWebhook).NotTo(BeNil())
+			Expect(*gatekeeper.Spec.ValidatingWebhook).To(Equal(v1alpha1.WebhookEnabled))
+			Expect(gatekeeper.Spec.MutatingWebhook).NotTo(BeNil())
+			Expect(*gatekeeper.Spec.MutatingWebhook).To(Equal(v1alpha1.WebhookDisabled))
+			Expect(gatekeeper.Spec.Upgrade).NotTo(BeNil())
+			Expect(gatekeeper.Spec.Upgrade.HealthCheckTimeout.Duration).To(Equal(v1alpha1.DefaultHealthCheckTimeout))
+		})
+
+		It("Rejects invalid Gatekeeper resources", func() {
+			By("Rejecting a Gatekeeper resource not named gatekeeper", func() {
+				gatekeeper := emptyGatekeeper()
+				gatekeeper.Name = "not-gatekeeper"
+				err := K8sClient.Create(ctx, gatekeeper)
+				Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			})
+
+			By("Rejecting mutation enabled with validation disabled", func() {
+				gatekeeper := emptyGatekeeper()
+				validatingWebhookMode := v1alpha1.WebhookDisabled
+				mutatingWebhookMode := v1alpha1.WebhookEnabled
+				gatekeeper.Spec.ValidatingWebhook = &validatingWebhookMode
+				gatekeeper.Spec.MutatingWebhook = &mutatingWebhookMode
+				err := K8sClient.Create(ctx, gatekeeper)
+				Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			})
+
+			By("Rejecting no webhook replicas with failure policy Fail", func() {
+				gatekeeper := emptyGatekeeper()
+				replicas := int32(0)
+				failurePolicy := admregv1.Fail
+				gatekeeper.Spec.Webhook = &v1alpha1.WebhookConfig{
+					Replicas:      &replicas,
+					FailurePolicy: &failurePolicy,
+				}
+				err := K8sClient.Create(ctx, gatekeeper)
+				Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			})
+
+			By("Creating a valid Gatekeeper resource for cleanup", func() {
+				Expect(K8sClient.Create(ctx, emptyGatekeeper())).Should(Succeed())
+			})
+		})
+	})
+
+	Describe("Conversion webhook", func() {
+		It("Converts between v1alpha1 and v1beta1", func() {
+			gatekeeper := &v1beta1.Gatekeeper{
+				ObjectMeta: v1.ObjectMeta{
+					Name: gkName,
+				},
+				Spec: v1beta1.GatekeeperSpec{
+					Audit: &v1beta1.AuditConfig{
+						PodConfig: v1beta1.PodConfig{
+							PodAnnotations: map[string]string{"foo": "bar"},
+						},
+					},
+					Webhook: &v1beta1.WebhookConfig{
+						PodConfig: v1beta1.PodConfig{
+							PodAnnotations: map[string]string{"foo": "bar"},
+						},
+					},
+				},
+			}
+			Expect(K8sClient.Create(ctx, gatekeeper)).Should(Succeed())
+
+			By("Reading the v1beta1 resource as v1alpha1", func() {
+				alphaGatekeeper := &v1alpha1.Gatekeeper{}
+				Expect(K8sClient.Get(ctx, gatekeeperName, alphaGatekeeper)).Should(Succeed())
+				Expect(alphaGatekeeper.Spec.PodAnnotations).To(Equal(map[string]string{"foo": "bar"}))
+			})
+
+			By("Reporting the status as v1beta1 conditions", func() {
+				Eventually(func() bool {
+					Expect(K8sClient.Get(ctx, gatekeeperName, gatekeeper)).Should(Succeed())
+					return meta.IsStatusConditionTrue(gatekeeper.Status.Conditions, v1beta1.ConditionAuditReady)
+				}, longWaitTimeout, pollInterval).Should(BeTrue())
+				Expect(gatekeeper.Spec.Webhook.Validating).NotTo(BeNil())
+				Expect(*gatekeeper.Spec.Webhook.Validating.Mode).To(Equal(v1beta1.Enabled))
+			})
+
+			By("Applying different pod annotations for the audit and the webhook", func() {
+				auditAnnotations := map[string]string{"foo": "audit"}
+				gatekeeper.Spec.Audit.PodAnnotations = auditAnnotations
+				Expect(K8sClient.Update(ctx, gatekeeper)).Should(Succeed())
+
+				alphaGatekeeper := &v1alpha1.Gatekeeper{}
+				Expect(K8sClient.Get(ctx, gatekeeperName, alphaGatekeeper)).Should(Succeed())
+				Expect(alphaGatekeeper.Spec.Audit.PodAnnotations).To(Equal(auditAnnotations))
+				Expect(alphaGatekeeper.Spec.Webhook.PodAnnotations).To(Equal(map[string]string{"foo": "bar"}))
+
+				Eventually(func() map[string]string {
+					auditDeployment, _ := gatekeeperDeployments()
+					return auditDeployment.Spec.Template.Annotations
+				}, waitTimeout, pollInterval).Should(Equal(auditAnnotations))
+				_, webhookDeployment := gatekeeperDeployments()
+				Expect(webhookDeployment.Spec.Template.Annotations).To(Equal(map[string]string{"foo": "bar"}))
+			})
+		})
+	})
+
+	Describe("Uninstall", func() {
+		It("Removes Gatekeeper and retains the CRDs by default", func() {
+			gatekeeper := emptyGatekeeper()
+			By("Creating Gatekeeper resource", func() {
+				Expect(K8sClient.Create(ctx, gatekeeper)).Should(Succeed())
+			})
+
+			gatekeeperDeployments()
+			byCheckingValidationEnabled()
+
+			By("Deleting Gatekeeper resource", func() {
+				Expect(K8sClient.Delete(ctx, emptyGatekeeper())).Should(Succeed())
+				Eventually(func() bool {
+					err := K8sClient.Get(ctx, gatekeeperName, &v1alpha1.Gatekeeper{})
+					return apierrors.IsNotFound(err)
+				}, longWaitTimeout, pollInterval).Should(BeTrue())
+			})
+
+			By("Checking Gatekeeper resources are removed", func() {
+				err := K8sClient.Get(ctx, validatingWebhookName, &admregv1.ValidatingWebhookConfiguration{})
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+				err = K8sClient.Get(ctx, controllerManagerName, &appsv1.Deployment{})
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+				err = K8sClient.Get(ctx, auditName, &appsv1.Deployment{})
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			})
+
+			By("Checking Gatekeeper CRDs are retained", func() {
+				crd := &extv1beta1.CustomResourceDefinition{}
+				err := K8sClient.Get(ctx, types.NamespacedName{Name: "constrainttemplates.templates.gatekeeper.sh"}, crd)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(crd.OwnerReferences).To(BeEmpty())
+			})
+
+			By("Recreating Gatekeeper resource for cleanup", func() {
+				Expect(K8sClient.Create(ctx, emptyGatekeeper())).Should(Succeed())
+			})
 		})
 	})
 })
 
+func setManagementState(state v1alpha1.ManagementState) {
+	Eventually(func() error {
+		gatekeeper := &v1alpha1.Gatekeeper{}
+		err := K8sClient.Get(ctx, gatekeeperName, gatekeeper)
+		if err != nil {
+			return err
+		}
+		gatekeeper.Spec.ManagementState = &state
+		return K8sClient.Update(ctx, gatekeeper)
+	}, waitTimeout, pollInterval).Should(Succeed())
+}
+
 func gatekeeperDeployments() (auditDeployment, webhookDeployment *appsv1.Deployment) {
 	auditDeployment = &appsv1.Deployment{}
 	Eventually(func() error {
@@ -473,6 +837,14 @@ func gatekeeperWebhookDeployment() (webhookDeployment *appsv1.Deployment) {
 	return
 }
 
+func gatekeeperPodDisruptionBudget(name types.NamespacedName) (pdb *policyv1beta1.PodDisruptionBudget) {
+	pdb = &policyv1beta1.PodDisruptionBudget{}
+	Eventually(func() error {
+		return K8sClient.Get(ctx, name, pdb)
+	}, waitTimeout, pollInterval).ShouldNot(HaveOccurred())
+	return
+}
+
 func assertResources(expected, current corev1.ResourceRequirements) {
 	Expect(expected.Limits.Cpu().Cmp(*current.Limits.Cpu())).To(BeZero())
 	Expect(expected.Limits.Memory().Cmp(*current.Limits.Memory())).To(BeZero())
@@ -504,13 +876,7 @@ func byCheckingMutationEnabled(webhookDeployment *appsv1.Deployment) {
 		}, waitTimeout, pollInterval).ShouldNot(HaveOccurred())
 	})
 
-	var crdFn getCRDFunc
-	crdFn = func(crdName types.NamespacedName, mutatingCRD *extv1beta1.CustomResourceDefinition) {
-		Eventually(func() error {
-			return K8sClient.Get(ctx, crdName, mutatingCRD)
-		}, waitTimeout, pollInterval).ShouldNot(HaveOccurred())
-	}
-	byCheckingMutatingCRDs("deployed", crdFn)
+	byCheckingMutatingCRDs("deployed", crdDeployed)
 }
 
 func byCheckingValidationDisabled() {
@@ -539,15 +905,19 @@ func byCheckingMutationDisabled(webhookDeployment *appsv1.Deployment) {
 			return apierrors.IsNotFound(err)
 		}, waitTimeout, pollInterval).Should(BeTrue())
 	})
+}
 
-	var crdFn getCRDFunc
-	crdFn = func(crdName types.NamespacedName, mutatingCRD *extv1beta1.CustomResourceDefinition) {
-		Eventually(func() bool {
-			err := K8sClient.Get(ctx, crdName, mutatingCRD)
-			return apierrors.IsNotFound(err)
-		}, waitTimeout, pollInterval).Should(BeTrue())
-	}
-	byCheckingMutatingCRDs("not deployed", crdFn)
+func crdDeployed(crdName types.NamespacedName, mutatingCRD *extv1beta1.CustomResourceDefinition) {
+	Eventually(func() error {
+		return K8sClient.Get(ctx, crdName, mutatingCRD)
+	}, waitTimeout, pollInterval).ShouldNot(HaveOccurred())
+}
+
+func crdNotDeployed(crdName types.NamespacedName, mutatingCRD *extv1beta1.CustomResourceDefinition) {
+	Eventually(func() bool {
+		err := K8sClient.Get(ctx, crdName, mutatingCRD)
+		return apierrors.IsNotFound(err)
+	}, waitTimeout, pollInterval).Should(BeTrue())
 }
 
 func byCheckingMutatingCRDs(deployMsg string, f getCRDFunc) {
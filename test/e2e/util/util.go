@@ -32,6 +32,7 @@ type defaultConfig struct {
 	Resources         *corev1.ResourceRequirements
 	FailurePolicy     admregv1.FailurePolicyType
 	NamespaceSelector *metav1.LabelSelector
+	TimeoutSeconds    int32
 }
 
 // DefaultDeployment is the expected default configuration to be deployed
@@ -86,4 +87,5 @@ var DefaultDeployment = defaultConfig{
 			},
 		},
 	},
+	TimeoutSeconds: int32(3),
 }